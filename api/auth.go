@@ -77,6 +77,7 @@ func (c *Client) Login(req LoginRequest) (*LoginResponse, error) {
 	c.Config.RefreshToken = result.Data.RefreshToken
 	c.Config.ExpiresAt = result.Data.ExpiresAt
 	c.Config.RefreshTokenExpiresAt = result.Data.RefreshTokenExpiresAt
+	c.Config.UserID = result.Data.UserID
 
 	if err := c.Config.Save(); err != nil {
 		return nil, fmt.Errorf("failed to save config: %w", err)
@@ -102,7 +103,15 @@ type RefreshTokenResponse struct {
 	} `json:"data"`
 }
 
+// RefreshToken exchanges the refresh token for new tokens and saves them
 func (c *Client) RefreshToken() error {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+	return c.refreshToken()
+}
+
+// refreshToken is RefreshToken with tokenMu held
+func (c *Client) refreshToken() error {
 	if c.Config.RefreshToken == "" {
 		return fmt.Errorf("no refresh token available")
 	}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long cached responses are used before they are revalidated with the server
+const (
+	catalogCacheTTL   = time.Hour        // Categories and sections, which rarely change
+	articlesCacheTTL  = 10 * time.Minute // Article lists and articles
+	bookmarksCacheTTL = time.Minute
+)
+
+// Responses are cached per scope: the responses of authenticated requests carry the
+// user's bookmarks and are dropped when those change
+const (
+	scopePublic = "public"
+	scopeUser   = "user"
+)
+
+// defaultCache is the cache of the clients created by NewClient
+var defaultCache *Cache
+
+// SetCache sets the cache used by the clients created from now on; nil turns caching off
+func SetCache(cache *Cache) {
+	defaultCache = cache
+}
+
+// Cache keeps the responses of GET requests in memory and on disk, with the ETag they
+// came with so they can be revalidated with a conditional request once they expire.
+// It is safe for concurrent use.
+type Cache struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Body     []byte    `json:"body"`
+	ETag     string    `json:"etag,omitempty"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// NewCache returns a cache kept in dir, or only in memory if dir is empty
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, entries: make(map[string]cacheEntry)}
+}
+
+func (c *Cache) get(scope, path string) (cacheEntry, bool) {
+	key := scope + ":" + path
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		return entry, true
+	}
+	if c.dir == "" {
+		return cacheEntry{}, false
+	}
+
+	// Unreadable entries are misses, they get replaced by the next response
+	data, err := os.ReadFile(c.file(scope, path))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	c.entries[key] = entry
+	return entry, true
+}
+
+func (c *Cache) put(scope, path string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[scope+":"+path] = entry
+	if c.dir == "" {
+		return
+	}
+
+	// Saving is best effort, a lost entry is fetched again
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	file := c.file(scope, path)
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(file, data, 0600)
+}
+
+// clear drops every response of scope
+func (c *Cache) clear(scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, scope+":") {
+			delete(c.entries, key)
+		}
+	}
+	if c.dir != "" {
+		_ = os.RemoveAll(filepath.Join(c.dir, scope))
+	}
+}
+
+// file is where the response to path is kept on disk
+func (c *Cache) file(scope, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(c.dir, scope, hex.EncodeToString(sum[:])+".json")
+}
+
+// getCached makes a GET request to path through the client's cache and decodes the
+// response into result. Cached responses younger than ttl are used without a request,
+// older ones are revalidated with their ETag.
+func (c *Client) getCached(ctx context.Context, path string, requireAuth bool, ttl time.Duration, result any) error {
+	if c.Cache == nil {
+		resp, err := c.doRequestContext(ctx, "GET", path, nil, requireAuth, nil)
+		if err != nil {
+			return err
+		}
+		return c.handleResponse(resp, result)
+	}
+
+	scope := scopePublic
+	if requireAuth {
+		scope = scopeUser
+	}
+
+	cached, ok := c.Cache.get(scope, path)
+	if ok && time.Since(cached.StoredAt) < ttl {
+		return decodeResponse(cached.Body, result)
+	}
+
+	var header http.Header
+	if ok && cached.ETag != "" {
+		header = http.Header{"If-None-Match": {cached.ETag}}
+	}
+	resp, err := c.doRequestContext(ctx, "GET", path, nil, requireAuth, header)
+	if err != nil {
+		return err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		cached.StoredAt = time.Now()
+		c.Cache.put(scope, path, cached)
+		return decodeResponse(cached.Body, result)
+	}
+
+	body, err := readResponse(resp)
+	if err != nil {
+		return err
+	}
+	c.Cache.put(scope, path, cacheEntry{Body: body, ETag: resp.Header.Get("ETag"), StoredAt: time.Now()})
+	return decodeResponse(body, result)
+}
+
+// clearUserCache drops the cached responses that carry the user's bookmarks
+func (c *Client) clearUserCache() {
+	if c.Cache != nil {
+		c.Cache.clear(scopeUser)
+	}
+}
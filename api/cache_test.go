@@ -0,0 +1,152 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/config"
+)
+
+// cacheServer serves one article with an ETag, answering 304 to conditional requests
+// for it, and counts the requests it gets
+func cacheServer(t *testing.T) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.Method == "POST":
+			_, _ = w.Write([]byte(`{"data":{"id":"b1","article_id":"a1"}}`))
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"data":{"id":"a1","title":"Pour Over","content":"Pour slowly"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &notModified
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	server, requests, _ := cacheServer(t)
+	client := NewClient(&config.Config{APIURL: server.URL})
+	client.Cache = NewCache("")
+
+	for range 3 {
+		article, err := client.GetArticle("a1")
+		if err != nil {
+			t.Fatalf("GetArticle failed: %v", err)
+		}
+		if article.Title != "Pour Over" {
+			t.Errorf("article title = %q, want %q", article.Title, "Pour Over")
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestCacheRevalidatesExpiredResponses(t *testing.T) {
+	server, requests, notModified := cacheServer(t)
+	client := NewClient(&config.Config{APIURL: server.URL})
+	client.Cache = NewCache("")
+
+	if _, err := client.GetArticle("a1"); err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+
+	// Age the entry past its TTL
+	entry, _ := client.Cache.get(scopePublic, "/api/core/v1/content/articles/a1/")
+	entry.StoredAt = time.Now().Add(-articlesCacheTTL - time.Second)
+	client.Cache.put(scopePublic, "/api/core/v1/content/articles/a1/", entry)
+
+	article, err := client.GetArticle("a1")
+	if err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if article.Content != "Pour slowly" {
+		t.Errorf("article content = %q, want the cached content", article.Content)
+	}
+	if requests.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("server got %d requests, %d conditional, want 2 and 1", requests.Load(), notModified.Load())
+	}
+
+	// The revalidated entry is fresh again
+	if _, err := client.GetArticle("a1"); err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestCacheIsKeptOnDisk(t *testing.T) {
+	server, requests, _ := cacheServer(t)
+	dir := t.TempDir()
+
+	first := NewClient(&config.Config{APIURL: server.URL})
+	first.Cache = NewCache(dir)
+	if _, err := first.GetArticle("a1"); err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+
+	second := NewClient(&config.Config{APIURL: server.URL})
+	second.Cache = NewCache(dir)
+	article, err := second.GetArticle("a1")
+	if err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if article.Title != "Pour Over" {
+		t.Errorf("article title = %q, want %q", article.Title, "Pour Over")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestBookmarkChangesInvalidateUserResponses(t *testing.T) {
+	server, requests, _ := cacheServer(t)
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "token"})
+	client.Cache = NewCache(t.TempDir())
+
+	// An anonymous response, kept when bookmarks change
+	if _, err := client.ListCategories(); err != nil {
+		t.Fatalf("ListCategories failed: %v", err)
+	}
+	if _, err := client.GetArticle("a1"); err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if _, err := client.CreateBookmark("a1"); err != nil {
+		t.Fatalf("CreateBookmark failed: %v", err)
+	}
+	requests.Store(0)
+
+	if _, err := client.GetArticle("a1"); err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if _, err := client.ListCategories(); err != nil {
+		t.Fatalf("ListCategories failed: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server got %d requests after bookmarking, want 1 for the article", got)
+	}
+}
+
+func TestNoCacheAlwaysFetches(t *testing.T) {
+	server, requests, _ := cacheServer(t)
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	for range 2 {
+		if _, err := client.GetArticle("a1"); err != nil {
+			t.Fatalf("GetArticle failed: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
@@ -2,13 +2,18 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/utils"
 )
 
 // Version can be set via build flags: -ldflags "-X github.com/hassek/bc-cli/api.Version=x.y.z"
@@ -23,6 +28,8 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	Config     *config.Config
+	// Cache keeps the responses of the content endpoints, nil to always fetch them
+	Cache *Cache
 }
 
 func NewClient(cfg *config.Config) *Client {
@@ -30,21 +37,23 @@ func NewClient(cfg *config.Config) *Client {
 		BaseURL:    cfg.APIURL,
 		HTTPClient: &http.Client{},
 		Config:     cfg,
+		Cache:      defaultCache,
 	}
 }
 
 func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*http.Response, error) {
-	// Check if token needs refresh before making authenticated request
-	if requireAuth && c.Config.IsAuthenticated() {
-		if c.Config.IsTokenExpired() {
-			if c.Config.IsRefreshTokenExpired() {
-				return nil, fmt.Errorf("refresh token expired, please login again")
-			}
+	return c.doRequestContext(context.Background(), method, path, body, requireAuth, nil)
+}
 
-			// Attempt to refresh the token
-			if err := c.RefreshToken(); err != nil {
-				return nil, fmt.Errorf("failed to refresh token: %w", err)
-			}
+// doRequestContext is doRequest with a context, which cancels the request when done,
+// and extra request headers, e.g. for conditional requests
+func (c *Client) doRequestContext(ctx context.Context, method, path string, body any, requireAuth bool, header http.Header) (*http.Response, error) {
+	// Check if token needs refresh before making authenticated request
+	var token string
+	if requireAuth {
+		var err error
+		if token, err = c.accessToken(); err != nil {
+			return nil, err
 		}
 	}
 
@@ -60,7 +69,7 @@ func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*ht
 	url := c.BaseURL + path
 	logRequest(method, url, body)
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -68,9 +77,12 @@ func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*ht
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", UserAgent())
+	for name, values := range header {
+		req.Header[name] = values
+	}
 
-	if requireAuth && c.Config.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Config.AccessToken))
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
 
 	// For DELETE requests, disable connection reuse to prevent "Unsolicited response"
@@ -85,11 +97,12 @@ func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*ht
 	}
 
 	// If we get 401 Unauthorized and this is an authenticated request, try to refresh and retry once
-	if requireAuth && resp.StatusCode == 401 && !c.Config.IsRefreshTokenExpired() {
+	if token != "" && resp.StatusCode == 401 {
 		_ = resp.Body.Close()
 
 		// Attempt to refresh the token
-		if refreshErr := c.RefreshToken(); refreshErr != nil {
+		token, refreshErr := c.refreshRejected(token)
+		if refreshErr != nil {
 			logDebug("Failed to refresh token on 401: %v", refreshErr)
 			return resp, nil // Return original 401 response
 		}
@@ -105,7 +118,7 @@ func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*ht
 			reqBody = bytes.NewBuffer(jsonData)
 		}
 
-		retryReq, err := http.NewRequest(method, url, reqBody)
+		retryReq, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 		if err != nil {
 			logDebug("Failed to create retry request: %v", err)
 			return resp, nil // Return original 401 response
@@ -114,7 +127,10 @@ func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*ht
 		retryReq.Header.Set("Content-Type", "application/json")
 		retryReq.Header.Set("Accept", "application/json")
 		retryReq.Header.Set("User-Agent", UserAgent())
-		retryReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Config.AccessToken))
+		retryReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		for name, values := range header {
+			retryReq.Header[name] = values
+		}
 
 		retryResp, err := c.HTTPClient.Do(retryReq)
 		if err != nil {
@@ -128,6 +144,53 @@ func (c *Client) doRequest(method, path string, body any, requireAuth bool) (*ht
 	return resp, nil
 }
 
+// tokenMu guards the tokens in the config of the clients. Requests running at the same
+// time, like prefetches, would otherwise refresh them together, and with rotating
+// refresh tokens all but the first refresh fail.
+var tokenMu sync.Mutex
+
+// accessToken returns the access token for an authenticated request, refreshing it
+// first if it expired
+func (c *Client) accessToken() (string, error) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	if c.Config.IsAuthenticated() && c.Config.IsTokenExpired() {
+		if c.Config.IsRefreshTokenExpired() {
+			return "", fmt.Errorf("refresh token expired, please login again")
+		}
+		if err := c.refreshToken(); err != nil {
+			return "", fmt.Errorf("failed to refresh token: %w", err)
+		}
+	}
+	return c.Config.AccessToken, nil
+}
+
+// isAuthenticated reports whether the client has a user's tokens
+func (c *Client) isAuthenticated() bool {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+	return c.Config.IsAuthenticated()
+}
+
+// refreshRejected refreshes the access token after the API rejected token, unless
+// another request refreshed it meanwhile, and returns the token to retry with
+func (c *Client) refreshRejected(token string) (string, error) {
+	tokenMu.Lock()
+	defer tokenMu.Unlock()
+
+	if c.Config.AccessToken != token {
+		return c.Config.AccessToken, nil
+	}
+	if c.Config.IsRefreshTokenExpired() {
+		return "", fmt.Errorf("refresh token expired, please login again")
+	}
+	if err := c.refreshToken(); err != nil {
+		return "", err
+	}
+	return c.Config.AccessToken, nil
+}
+
 // logDebug logs debug messages (currently a no-op, but can be enhanced)
 func logDebug(format string, args ...any) {
 	// In production, this could write to a debug log file
@@ -135,6 +198,45 @@ func logDebug(format string, args ...any) {
 	_ = fmt.Sprintf(format, args...)
 }
 
+// StatusError is returned for non-2xx responses. Its message is the most specific
+// error text the API provided.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// IsNotFound reports whether err is an API 404 response
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnsupported reports whether err is an API response to a request the API doesn't
+// support, like updating fields it doesn't have
+func IsUnsupported(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusMethodNotAllowed || statusErr.StatusCode == http.StatusNotImplemented)
+}
+
+// IsUnauthorized reports whether err is an API 401 or 403 response
+func IsUnauthorized(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden)
+}
+
+// IsUnreachable reports whether err is a request that got no response from the API,
+// e.g. because there is no network connection
+func IsUnreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
 type APIError struct {
 	Data map[string]any `json:"data"`
 	Meta struct {
@@ -149,58 +251,81 @@ type APIError struct {
 }
 
 func (c *Client) handleResponse(resp *http.Response, result any) error {
+	body, err := readResponse(resp)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(body, result)
+}
+
+// readResponse reads and closes the body of resp. Non-2xx responses are returned as a
+// *StatusError.
+func readResponse(resp *http.Response) ([]byte, error) {
 	defer func() {
 		_ = resp.Body.Close() // Explicitly ignore error in defer
 	}()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	logResponse(resp.StatusCode, body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		// Try to parse structured API error
-		var apiErr APIError
-		if err := json.Unmarshal(body, &apiErr); err == nil {
-			// Extract field-specific errors
-			if len(apiErr.Meta.Errors) > 0 {
-				var errorMessages []string
-				for _, e := range apiErr.Meta.Errors {
-					if e.Field != "" {
-						errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", e.Field, e.Error))
-					} else {
-						errorMessages = append(errorMessages, e.Error)
-					}
-				}
-				if len(errorMessages) > 0 {
-					return fmt.Errorf("%s", strings.Join(errorMessages, "\n"))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: utils.SanitizeText(parseErrorMessage(resp.StatusCode, body))}
+	}
+	return body, nil
+}
+
+// decodeResponse unmarshals a response body into result
+func decodeResponse(body []byte, result any) error {
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		// Responses end up on the terminal, so they can't carry escape sequences. Content
+		// rendered as a template is sanitised again once rendered, since templates can make
+		// escape sequences out of clean text.
+		utils.SanitizeStrings(result)
+	}
+
+	return nil
+}
+
+// parseErrorMessage extracts a human-readable error message from an error response body
+func parseErrorMessage(statusCode int, body []byte) string {
+	// Try to parse structured API error
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err == nil {
+		// Extract field-specific errors
+		if len(apiErr.Meta.Errors) > 0 {
+			var errorMessages []string
+			for _, e := range apiErr.Meta.Errors {
+				if e.Field != "" {
+					errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", e.Field, e.Error))
+				} else {
+					errorMessages = append(errorMessages, e.Error)
 				}
 			}
-			// Fallback to meta message
-			if apiErr.Meta.Message != "" {
-				return fmt.Errorf("%s", apiErr.Meta.Message)
+			if len(errorMessages) > 0 {
+				return strings.Join(errorMessages, "\n")
 			}
 		}
-
-		// Try simple detail message format
-		var errResp map[string]any
-		if err := json.Unmarshal(body, &errResp); err == nil {
-			if msg, ok := errResp["detail"].(string); ok {
-				return fmt.Errorf("%s", msg)
-			}
+		// Fallback to meta message
+		if apiErr.Meta.Message != "" {
+			return apiErr.Meta.Message
 		}
-
-		// Fallback to raw response
-		return fmt.Errorf("request failed (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	if result != nil && len(body) > 0 {
-		if err := json.Unmarshal(body, result); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", err)
+	// Try simple detail message format
+	var errResp map[string]any
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		if msg, ok := errResp["detail"].(string); ok {
+			return msg
 		}
 	}
 
-	return nil
+	// Fallback to raw response
+	return fmt.Sprintf("request failed (status %d): %s", statusCode, string(body))
 }
@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"os"
+
+	"github.com/hassek/bc-cli/utils"
 )
 
 // EnableDebugLogging enables detailed request/response logging for debugging
@@ -27,6 +29,6 @@ func logResponse(statusCode int, body []byte) {
 	}
 	fmt.Printf("\n=== RESPONSE ===\n")
 	fmt.Printf("Status: %d\n", statusCode)
-	fmt.Printf("Body: %s\n", string(body))
+	fmt.Printf("Body: %s\n", utils.SanitizeText(string(body)))
 	fmt.Printf("================\n\n")
 }
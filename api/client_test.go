@@ -1,8 +1,10 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/hassek/bc-cli/config"
@@ -96,3 +98,106 @@ func containsDoubleSlash(url string) bool {
 	}
 	return false
 }
+
+// TestResponsesAreSanitized verifies escape sequences from the server never reach callers
+func TestResponsesAreSanitized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/core/v1/content/articles/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"detail":"\u001b]0;pwned\u0007Not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"meta":{"code":200,"message":"ok"},"data":{"id":"a1","title":"\u001b[8mHidden","content":"Body\u001b]52;c;aGk=\u0007"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	article, err := client.GetArticle("a1")
+	if err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if article.Title != "Hidden" || article.Content != "Body" {
+		t.Errorf("article not sanitized: title %q, content %q", article.Title, article.Content)
+	}
+
+	_, err = client.GetArticle("missing")
+	if err == nil || err.Error() != "Not found" {
+		t.Errorf("GetArticle error = %q, want %q", err, "Not found")
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	client := NewClient(&config.Config{APIURL: server.URL})
+
+	_, err := client.GetArticle("missing")
+	if err == nil || IsUnreachable(err) {
+		t.Errorf("GetArticle error = %v, want an API error", err)
+	}
+
+	server.Close()
+	_, err = client.GetArticle("missing")
+	if !IsUnreachable(err) {
+		t.Errorf("GetArticle error = %v, want the API unreachable", err)
+	}
+}
+
+// TestParallelRequestsRefreshOnce verifies requests rejected together refresh the
+// tokens once, since a rotated refresh token can't be used again
+func TestParallelRequestsRefreshOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var mu sync.Mutex
+	refreshes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/core/v1/users/token/refresh" {
+			var body RefreshTokenRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			defer mu.Unlock()
+			if body.RefreshToken != "refresh-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"detail":"refresh token already used"}`))
+				return
+			}
+			refreshes++
+			_, _ = w.Write([]byte(`{"data":{"access_token":"access-2","refresh_token":"refresh-2"}}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"detail":"token expired"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"a1","title":"Espresso"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "access-1", RefreshToken: "refresh-1"})
+	client.Cache = nil
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for range 8 {
+		wg.Go(func() {
+			_, err := client.GetArticle("a1")
+			errs <- err
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetArticle failed: %v", err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("tokens refreshed %d times, want once", refreshes)
+	}
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 )
 
 // Category represents a content category
@@ -37,6 +40,7 @@ type Article struct {
 	Tags         string  `json:"tags"`              // Comma-separated tags
 	PublishedAt  *string `json:"published_at"`
 	IsBookmarked bool    `json:"is_bookmarked"` // Only present for authenticated users
+	BookmarkID   string  `json:"bookmark_id"`   // The user's bookmark, if bookmarked
 }
 
 // Bookmark represents a user's bookmarked article
@@ -45,44 +49,43 @@ type Bookmark struct {
 	ArticleID string  `json:"article_id"`
 	Article   Article `json:"article"`
 	CreatedAt string  `json:"created_at"`
+	// Collection and Note are only present if the API keeps them
+	Collection string `json:"collection,omitempty"`
+	Note       string `json:"note,omitempty"`
 }
 
-// Response wrappers
-type categoriesResponse struct {
-	Meta struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"meta"`
-	Data struct {
-		Count    int        `json:"count"`
-		Next     *string    `json:"next"`
-		Previous *string    `json:"previous"`
-		Results  []Category `json:"results"`
-	} `json:"data"`
+// BookmarkChanges are the fields of a bookmark to update; nil fields are left as is
+type BookmarkChanges struct {
+	Collection *string `json:"collection,omitempty"`
+	Note       *string `json:"note,omitempty"`
 }
 
-type categoryResponse struct {
-	Meta struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"meta"`
-	Data Category `json:"data"`
+// ErrBookmarkNotFound is returned when the user has no bookmark for an article
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// articlesFetched is called with the articles of the responses that have them
+var articlesFetched func([]Article)
+
+// OnArticlesFetched sets f to be called with the articles of every response that has
+// them, lists and full articles, e.g. to index them for search. f may be called from
+// several goroutines at once.
+func OnArticlesFetched(f func([]Article)) {
+	articlesFetched = f
 }
 
-type sectionsResponse struct {
-	Meta struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"meta"`
-	Data []Section `json:"data"`
+func notifyArticles(articles []Article) {
+	if articlesFetched != nil && len(articles) > 0 {
+		articlesFetched(articles)
+	}
 }
 
-type articlesResponse struct {
+// Response wrappers
+type categoryResponse struct {
 	Meta struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 	} `json:"meta"`
-	Data []Article `json:"data"`
+	Data Category `json:"data"`
 }
 
 type articleResponse struct {
@@ -93,17 +96,6 @@ type articleResponse struct {
 	Data Article `json:"data"`
 }
 
-type bookmarksResponse struct {
-	Meta struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"meta"`
-	Data struct {
-		Count   int        `json:"count"`
-		Results []Bookmark `json:"results"`
-	} `json:"data"`
-}
-
 type bookmarkResponse struct {
 	Meta struct {
 		Code    int    `json:"code"`
@@ -112,114 +104,98 @@ type bookmarkResponse struct {
 	Data Bookmark `json:"data"`
 }
 
+// Categories returns the pages of the published categories
+func (c *Client) Categories() *Paginator[Category] {
+	return newPaginator[Category](c, "/api/core/v1/content/categories/", false, catalogCacheTTL)
+}
+
 // ListCategories retrieves all published categories
 func (c *Client) ListCategories() ([]Category, error) {
-	resp, err := c.doRequest("GET", "/api/core/v1/content/categories/", nil, false)
-	if err != nil {
-		return nil, err
-	}
-
-	var result categoriesResponse
-	if err := c.handleResponse(resp, &result); err != nil {
-		return nil, err
-	}
-
-	return result.Data.Results, nil
+	return c.Categories().All()
 }
 
 // GetCategory retrieves a category by slug
 func (c *Client) GetCategory(slug string) (*Category, error) {
 	url := fmt.Sprintf("/api/core/v1/content/categories/%s/", slug)
-	resp, err := c.doRequest("GET", url, nil, false)
-	if err != nil {
-		return nil, err
-	}
-
 	var result categoryResponse
-	if err := c.handleResponse(resp, &result); err != nil {
+	if err := c.getCached(context.Background(), url, false, catalogCacheTTL, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Data, nil
 }
 
-// ListCategorySections retrieves sections for a category
-func (c *Client) ListCategorySections(categorySlug string) ([]Section, error) {
+// CategorySections returns the pages of the sections of a category
+func (c *Client) CategorySections(categorySlug string) *Paginator[Section] {
 	url := fmt.Sprintf("/api/core/v1/content/categories/%s/sections/", categorySlug)
-	resp, err := c.doRequest("GET", url, nil, false)
-	if err != nil {
-		return nil, err
-	}
+	return newPaginator[Section](c, url, false, catalogCacheTTL)
+}
 
-	var result sectionsResponse
-	if err := c.handleResponse(resp, &result); err != nil {
-		return nil, err
-	}
+// ListCategorySections retrieves sections for a category
+func (c *Client) ListCategorySections(categorySlug string) ([]Section, error) {
+	return c.CategorySections(categorySlug).All()
+}
 
-	return result.Data, nil
+// CategoryArticles returns the pages of the articles in a category's default section
+func (c *Client) CategoryArticles(categorySlug string) *Paginator[Article] {
+	url := fmt.Sprintf("/api/core/v1/content/categories/%s/articles/", categorySlug)
+	return c.articlePages(url)
 }
 
 // ListCategoryArticles retrieves articles in category's default section
 func (c *Client) ListCategoryArticles(categorySlug string) ([]Article, error) {
-	url := fmt.Sprintf("/api/core/v1/content/categories/%s/articles/", categorySlug)
-	resp, err := c.doRequest("GET", url, nil, c.Config.IsAuthenticated())
-	if err != nil {
-		return nil, err
-	}
-
-	var result articlesResponse
-	if err := c.handleResponse(resp, &result); err != nil {
-		return nil, err
-	}
+	return c.CategoryArticles(categorySlug).All()
+}
 
-	return result.Data, nil
+// SectionArticles returns the pages of the articles in a section
+func (c *Client) SectionArticles(sectionID string) *Paginator[Article] {
+	url := fmt.Sprintf("/api/core/v1/content/sections/%s/articles/", sectionID)
+	return c.articlePages(url)
 }
 
 // ListSectionArticles retrieves articles in a specific section
 func (c *Client) ListSectionArticles(sectionID string) ([]Article, error) {
-	url := fmt.Sprintf("/api/core/v1/content/sections/%s/articles/", sectionID)
-	resp, err := c.doRequest("GET", url, nil, c.Config.IsAuthenticated())
-	if err != nil {
-		return nil, err
-	}
-
-	var result articlesResponse
-	if err := c.handleResponse(resp, &result); err != nil {
-		return nil, err
-	}
+	return c.SectionArticles(sectionID).All()
+}
 
-	return result.Data, nil
+func (c *Client) articlePages(url string) *Paginator[Article] {
+	pages := newPaginator[Article](c, url, c.isAuthenticated(), articlesCacheTTL)
+	pages.onPage = notifyArticles
+	return pages
 }
 
 // GetArticle retrieves full article with content
 func (c *Client) GetArticle(articleID string) (*Article, error) {
-	url := fmt.Sprintf("/api/core/v1/content/articles/%s/", articleID)
-	resp, err := c.doRequest("GET", url, nil, c.Config.IsAuthenticated())
-	if err != nil {
-		return nil, err
-	}
+	return c.getArticle(context.Background(), articleID)
+}
 
+func (c *Client) getArticle(ctx context.Context, articleID string) (*Article, error) {
+	url := fmt.Sprintf("/api/core/v1/content/articles/%s/", articleID)
 	var result articleResponse
-	if err := c.handleResponse(resp, &result); err != nil {
+	if err := c.getCached(ctx, url, c.isAuthenticated(), articlesCacheTTL, &result); err != nil {
 		return nil, err
 	}
 
+	notifyArticles([]Article{result.Data})
 	return &result.Data, nil
 }
 
-// ListBookmarks retrieves user's bookmarked articles (requires auth)
-func (c *Client) ListBookmarks() ([]Bookmark, error) {
-	resp, err := c.doRequest("GET", "/api/core/v1/content/bookmarks/", nil, true)
-	if err != nil {
-		return nil, err
-	}
-
-	var result bookmarksResponse
-	if err := c.handleResponse(resp, &result); err != nil {
-		return nil, err
+// Bookmarks returns the pages of the user's bookmarks (requires auth)
+func (c *Client) Bookmarks() *Paginator[Bookmark] {
+	pages := newPaginator[Bookmark](c, "/api/core/v1/content/bookmarks/", true, bookmarksCacheTTL)
+	pages.onPage = func(bookmarks []Bookmark) {
+		articles := make([]Article, len(bookmarks))
+		for i, bookmark := range bookmarks {
+			articles[i] = bookmark.Article
+		}
+		notifyArticles(articles)
 	}
+	return pages
+}
 
-	return result.Data.Results, nil
+// ListBookmarks retrieves all of the user's bookmarked articles (requires auth)
+func (c *Client) ListBookmarks() ([]Bookmark, error) {
+	return c.Bookmarks().All()
 }
 
 // CreateBookmark bookmarks an article (requires auth)
@@ -234,6 +210,8 @@ func (c *Client) CreateBookmark(articleID string) (*Bookmark, error) {
 	if err := c.handleResponse(resp, &result); err != nil {
 		return nil, err
 	}
+	// Bookmarks show in the article responses, which are fetched again
+	c.clearUserCache()
 
 	return &result.Data, nil
 }
@@ -248,12 +226,76 @@ func (c *Client) DeleteBookmark(bookmarkID string) error {
 
 	// DELETE returns 204 No Content on success
 	if resp.StatusCode != 204 {
-		return c.handleResponse(resp, nil)
+		if err := c.handleResponse(resp, nil); err != nil {
+			return err
+		}
 	}
 
+	c.clearUserCache()
 	return nil
 }
 
+// UpdateBookmark changes the collection or note of a bookmark (requires auth). It fails
+// with an unsupported error, see IsUnsupported, if the API doesn't keep them.
+func (c *Client) UpdateBookmark(bookmarkID string, changes BookmarkChanges) (*Bookmark, error) {
+	url := fmt.Sprintf("/api/core/v1/content/bookmarks/%s/", bookmarkID)
+	resp, err := c.doRequest("PATCH", url, changes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result bookmarkResponse
+	if err := c.handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	// An API that doesn't know the fields ignores them
+	if changes.Collection != nil && result.Data.Collection != *changes.Collection ||
+		changes.Note != nil && result.Data.Note != *changes.Note {
+		return nil, &StatusError{StatusCode: http.StatusNotImplemented, Message: "bookmark collections and notes are not supported"}
+	}
+	c.clearUserCache()
+
+	return &result.Data, nil
+}
+
+// FindBookmark returns the user's bookmark of an article (requires auth). It fetches the
+// pages of bookmarks until it's found.
+func (c *Client) FindBookmark(articleID string) (*Bookmark, error) {
+	pages := c.Bookmarks()
+	for pages.More() {
+		bookmarks, err := pages.Next()
+		if err != nil {
+			return nil, err
+		}
+		for _, bm := range bookmarks {
+			// Use the nested Article.ID since article_id might not be populated at top level
+			if bm.Article.ID == articleID {
+				return &bm, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w for article %s", ErrBookmarkNotFound, articleID)
+}
+
+// DeleteArticleBookmark removes the user's bookmark of an article (requires auth). The
+// bookmark is looked up if the article doesn't come with its ID.
+func (c *Client) DeleteArticleBookmark(article *Article) error {
+	bookmarkID := article.BookmarkID
+	if bookmarkID == "" {
+		bm, err := c.FindBookmark(article.ID)
+		if err != nil {
+			return err
+		}
+		bookmarkID = bm.ID
+	}
+
+	err := c.DeleteBookmark(bookmarkID)
+	if IsNotFound(err) {
+		return nil // Already removed, e.g. from another device
+	}
+	return err
+}
+
 // CategoryHasSections checks if a category has sections (helper for smart navigation)
 func (c *Client) CategoryHasSections(categorySlug string) (bool, error) {
 	sections, err := c.ListCategorySections(categorySlug)
@@ -2,8 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"path"
+	"slices"
 	"testing"
 
 	"github.com/hassek/bc-cli/config"
@@ -497,3 +500,123 @@ func TestListBookmarks(t *testing.T) {
 		t.Errorf("Expected bookmarked article to have IsBookmarked true")
 	}
 }
+
+func TestOnArticlesFetched(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data any
+		switch r.URL.Path {
+		case "/api/core/v1/content/articles/article-1/":
+			data = map[string]any{"id": "article-1", "title": "Article 1", "content": "# Content"}
+		case "/api/core/v1/content/sections/section-1/articles/":
+			data = []map[string]any{{"id": "article-1"}, {"id": "article-2"}}
+		case "/api/core/v1/content/bookmarks/":
+			data = map[string]any{"count": 1, "results": []map[string]any{{"id": "bookmark-1", "article": map[string]any{"id": "article-2"}}}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"code": 200}, "data": data})
+	}))
+	defer server.Close()
+
+	var fetched []string
+	OnArticlesFetched(func(articles []Article) {
+		for _, article := range articles {
+			fetched = append(fetched, article.ID)
+		}
+	})
+	defer OnArticlesFetched(nil)
+
+	client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "test-access-token"})
+	if _, err := client.GetArticle("article-1"); err != nil {
+		t.Fatalf("GetArticle failed: %v", err)
+	}
+	if _, err := client.ListSectionArticles("section-1"); err != nil {
+		t.Fatalf("ListSectionArticles failed: %v", err)
+	}
+	if _, err := client.ListBookmarks(); err != nil {
+		t.Fatalf("ListBookmarks failed: %v", err)
+	}
+
+	want := []string{"article-1", "article-1", "article-2", "article-2"}
+	if !slices.Equal(fetched, want) {
+		t.Errorf("Fetched articles %v, want %v", fetched, want)
+	}
+}
+
+func TestDeleteArticleBookmark(t *testing.T) {
+	tests := []struct {
+		name        string
+		article     Article
+		wantDeleted string
+		wantErr     error
+	}{
+		{"with its bookmark ID", Article{ID: "a9", BookmarkID: "b9"}, "b9", nil},
+		{"looked up on a later page", Article{ID: "a2"}, "b2", nil},
+		{"already removed", Article{ID: "a9", BookmarkID: "gone"}, "gone", nil},
+		{"not bookmarked", Article{ID: "a7"}, "", ErrBookmarkNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.Method == "DELETE":
+					deleted = path.Base(r.URL.Path)
+					if deleted == "gone" {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`{"meta":{"code":404,"message":"Not found"}}`))
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				case r.URL.Query().Get("page") == "2":
+					_, _ = w.Write([]byte(`{"data":{"count":2,"next":null,"results":[{"id":"b2","article":{"id":"a2"}}]}}`))
+				default:
+					_, _ = w.Write([]byte(`{"data":{"count":2,"next":"/api/core/v1/content/bookmarks/?page=2","results":[{"id":"b1","article":{"id":"a1"}}]}}`))
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "token"})
+			err := client.DeleteArticleBookmark(&tt.article)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteArticleBookmark error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted bookmark %q, want %q", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestUpdateBookmark(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		response        string
+		wantUnsupported bool
+	}{
+		{"kept", http.StatusOK, `{"data":{"id":"b1","collection":"Brewing","note":"Use 18g"}}`, false},
+		{"fields ignored", http.StatusOK, `{"data":{"id":"b1"}}`, true},
+		{"method not allowed", http.StatusMethodNotAllowed, `{"meta":{"code":405,"message":"Method not allowed"}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "PATCH" || r.URL.Path != "/api/core/v1/content/bookmarks/b1/" {
+					t.Errorf("got %s %s, want PATCH of the bookmark", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClient(&config.Config{APIURL: server.URL, AccessToken: "token"})
+			collection, note := "Brewing", "Use 18g"
+			_, err := client.UpdateBookmark("b1", BookmarkChanges{Collection: &collection, Note: &note})
+			if IsUnsupported(err) != tt.wantUnsupported || (!tt.wantUnsupported && err != nil) {
+				t.Errorf("UpdateBookmark error = %v, want unsupported %v", err, tt.wantUnsupported)
+			}
+		})
+	}
+}
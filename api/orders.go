@@ -142,3 +142,24 @@ func (c *Client) GetOrder(orderID string) (*Order, error) {
 
 	return &result.Data, nil
 }
+
+// ListOrders retrieves the user's orders
+func (c *Client) ListOrders() ([]Order, error) {
+	resp, err := c.doRequest("GET", "/api/core/v1/orders", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ListOrdersResponse
+	if err := c.handleResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Data {
+		if err := validateOrder(&result.Data[i]); err != nil {
+			return nil, fmt.Errorf("invalid order at index %d: %w", i, err)
+		}
+	}
+
+	return result.Data, nil
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// listPage is the data of a list response: one page of a paginated list, or the whole
+// list for endpoints that don't paginate, which send a bare array
+type listPage[T any] struct {
+	Count    int
+	Next     *string
+	Previous *string
+	Results  []T
+}
+
+func (p *listPage[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &p.Results); err != nil {
+			return err
+		}
+		p.Count = len(p.Results)
+		return nil
+	}
+
+	var page struct {
+		Count    int     `json:"count"`
+		Next     *string `json:"next"`
+		Previous *string `json:"previous"`
+		Results  []T     `json:"results"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return err
+	}
+	*p = listPage[T]{Count: page.Count, Next: page.Next, Previous: page.Previous, Results: page.Results}
+	return nil
+}
+
+type listResponse[T any] struct {
+	Meta struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"meta"`
+	Data listPage[T] `json:"data"`
+}
+
+// maxPages is the most pages a list is fetched in, so a server that keeps sending next
+// links can't make a list endless
+const maxPages = 1000
+
+// Paginator fetches a list one page at a time, following the next links of the
+// responses. Lists that don't paginate come as a single page.
+type Paginator[T any] struct {
+	client      *Client
+	next        string // path of the next page, empty once the last one was fetched
+	visited     map[string]bool
+	requireAuth bool
+	ttl         time.Duration
+	count       int
+	onPage      func([]T)
+}
+
+func newPaginator[T any](client *Client, path string, requireAuth bool, ttl time.Duration) *Paginator[T] {
+	return &Paginator[T]{client: client, next: path, visited: make(map[string]bool), requireAuth: requireAuth, ttl: ttl}
+}
+
+// More reports whether there are pages left to fetch
+func (p *Paginator[T]) More() bool {
+	return p.next != ""
+}
+
+// Count returns the number of items in the whole list, as reported by the last page
+// fetched
+func (p *Paginator[T]) Count() int {
+	return p.count
+}
+
+// Next fetches the next page. It returns no items once every page was fetched, and an
+// error, ending the list, if its next links go round in a loop or past maxPages.
+func (p *Paginator[T]) Next() ([]T, error) {
+	if p.next == "" {
+		return nil, nil
+	}
+
+	var result listResponse[T]
+	if err := p.client.getCached(context.Background(), p.next, p.requireAuth, p.ttl, &result); err != nil {
+		return nil, err
+	}
+	p.visited[p.next] = true
+
+	next := p.client.pagePath(result.Data.Next)
+	switch {
+	case next == p.next:
+		next = "" // A page linking to itself is taken as the only page
+	case p.visited[next]:
+		page := p.next
+		p.next = ""
+		return nil, fmt.Errorf("the pages of the list loop: %s links back to %s", page, next)
+	case next != "" && len(p.visited) >= maxPages:
+		p.next = ""
+		return nil, fmt.Errorf("the list has more than %d pages", maxPages)
+	}
+	p.next = next
+	p.count = result.Data.Count
+	if p.onPage != nil {
+		p.onPage(result.Data.Results)
+	}
+	return result.Data.Results, nil
+}
+
+// All fetches the remaining pages and returns their items
+func (p *Paginator[T]) All() ([]T, error) {
+	var items []T
+	for p.More() {
+		page, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
+
+// pagePath turns the link to a page into a path for doRequest. Links are absolute URLs,
+// on the API's host or, behind a proxy, on another one.
+func (c *Client) pagePath(link *string) string {
+	if link == nil || *link == "" {
+		return ""
+	}
+	if path, ok := strings.CutPrefix(*link, c.BaseURL); ok {
+		return path
+	}
+	u, err := url.Parse(*link)
+	if err != nil {
+		return ""
+	}
+	return u.RequestURI()
+}
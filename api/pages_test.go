@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/config"
+)
+
+// pagedServer serves bookmarks b1 to b5 two to a page, like the API does, and the
+// sections of a category as a bare array
+func pagedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/core/v1/content/bookmarks/":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			page = max(page, 1)
+			next := "null"
+			if page < 3 {
+				next = fmt.Sprintf("%q", fmt.Sprintf("%s/api/core/v1/content/bookmarks/?page=%d", server.URL, page+1))
+			}
+			var results string
+			for i := (page-1)*2 + 1; i <= min(page*2, 5); i++ {
+				if results != "" {
+					results += ","
+				}
+				results += fmt.Sprintf(`{"id":"b%d","article":{"id":"a%d"}}`, i, i)
+			}
+			_, _ = fmt.Fprintf(w, `{"data":{"count":5,"next":%s,"previous":null,"results":[%s]}}`, next, results)
+		case "/api/core/v1/content/categories/cycle/sections/":
+			// Page 1 links to page 2, which links back to page 1
+			next := "/api/core/v1/content/categories/cycle/sections/?page=2"
+			if r.URL.Query().Get("page") == "2" {
+				next = "/api/core/v1/content/categories/cycle/sections/"
+			}
+			_, _ = fmt.Fprintf(w, `{"data":{"count":2,"next":%q,"results":[{"id":"s1"}]}}`, next)
+		case "/api/core/v1/content/categories/loop/sections/":
+			_, _ = w.Write([]byte(`{"data":{"count":1,"next":"/api/core/v1/content/categories/loop/sections/","results":[{"id":"s1"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[{"id":"s1"},{"id":"s2"}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPaginatorFollowsNextLinks(t *testing.T) {
+	client := NewClient(&config.Config{APIURL: pagedServer(t).URL, AccessToken: "token"})
+
+	pages := client.Bookmarks()
+	var sizes []int
+	for pages.More() {
+		page, err := pages.Next()
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		sizes = append(sizes, len(page))
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" || pages.Count() != 5 {
+		t.Errorf("pages of %v, count %d, want [2 2 1] and 5", sizes, pages.Count())
+	}
+
+	page, err := pages.Next()
+	if page != nil || err != nil {
+		t.Errorf("Next after the last page = %v, %v, want nothing", page, err)
+	}
+}
+
+func TestListBookmarksFetchesEveryPage(t *testing.T) {
+	client := NewClient(&config.Config{APIURL: pagedServer(t).URL, AccessToken: "token"})
+
+	bookmarks, err := client.ListBookmarks()
+	if err != nil {
+		t.Fatalf("ListBookmarks failed: %v", err)
+	}
+	if len(bookmarks) != 5 || bookmarks[4].Article.ID != "a5" {
+		t.Errorf("ListBookmarks returned %d bookmarks, want all 5", len(bookmarks))
+	}
+}
+
+func TestPaginatorUnpaginatedLists(t *testing.T) {
+	client := NewClient(&config.Config{APIURL: pagedServer(t).URL})
+
+	tests := []struct {
+		name     string
+		category string
+		want     int
+	}{
+		{"bare array", "brewing", 2},
+		{"page linking to itself", "loop", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sections, err := client.ListCategorySections(tt.category)
+			if err != nil {
+				t.Fatalf("ListCategorySections failed: %v", err)
+			}
+			if len(sections) != tt.want {
+				t.Errorf("ListCategorySections returned %d sections, want %d", len(sections), tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginatorStopsOnCycles(t *testing.T) {
+	client := NewClient(&config.Config{APIURL: pagedServer(t).URL})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ListCategorySections("cycle")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "loop") {
+			t.Errorf("ListCategorySections error = %v, want the loop reported", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListCategorySections never returned")
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"sync"
+)
+
+// prefetchQueue is the number of prefetches waiting for a worker; more are skipped
+const prefetchQueue = 16
+
+// Prefetcher fetches articles in the background on a fixed number of workers, so their
+// responses are in the client's cache by the time the user opens them. Each call to
+// Prefetch cancels the prefetches of the previous one that haven't finished yet.
+type Prefetcher struct {
+	client *Client
+	jobs   chan prefetchJob
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	stopped bool
+}
+
+type prefetchJob struct {
+	ctx       context.Context
+	articleID string
+}
+
+// NewPrefetcher starts a prefetcher fetching through client on workers workers. Stop
+// it when done.
+func NewPrefetcher(client *Client, workers int) *Prefetcher {
+	p := &Prefetcher{
+		client: client,
+		jobs:   make(chan prefetchJob, prefetchQueue),
+		cancel: func() {},
+	}
+	for range max(workers, 1) {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+func (p *Prefetcher) work() {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		if job.ctx.Err() != nil {
+			continue // Stale, the user moved on
+		}
+		// Failures are left for when the article is opened, which reports them
+		_, _ = p.client.getArticle(job.ctx, job.articleID)
+	}
+}
+
+// Prefetch queues fetching the articles, in order, cancelling the ones queued before.
+// The cancelled ones are taken out of the queue, so the articles of the latest call
+// always get in. It never blocks: articles that don't fit in the queue are skipped.
+func (p *Prefetcher) Prefetch(articleIDs ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return
+	}
+
+	p.cancel()
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	// Whatever is still queued was cancelled above, the user moved on from it
+	for drained := false; !drained; {
+		select {
+		case <-p.jobs:
+		default:
+			drained = true
+		}
+	}
+	for _, id := range articleIDs {
+		select {
+		case p.jobs <- prefetchJob{ctx: ctx, articleID: id}:
+		default:
+			return
+		}
+	}
+}
+
+// Stop cancels the pending prefetches and waits for the workers to exit
+func (p *Prefetcher) Stop() {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	p.stopped = true
+	p.cancel()
+	close(p.jobs)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
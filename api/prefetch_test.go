@@ -0,0 +1,146 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/config"
+)
+
+func TestPrefetchFillsTheCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		id := path.Base(r.URL.Path)
+		_, _ = fmt.Fprintf(w, `{"data":{"id":%q,"content":"Content of %s"}}`, id, id)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+	client.Cache = NewCache("")
+
+	p := NewPrefetcher(client, 2)
+	p.Prefetch("a1", "a2", "a3")
+	deadline := time.Now().Add(5 * time.Second)
+	for !cached(client, "a1", "a2", "a3") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	p.Stop()
+
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("server got %d requests, want 3", got)
+	}
+	for _, id := range []string{"a1", "a2", "a3"} {
+		article, err := client.GetArticle(id)
+		if err != nil || article.Content != "Content of "+id {
+			t.Errorf("GetArticle(%s) = %v, %v", id, article, err)
+		}
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("opening prefetched articles made %d more requests", got-3)
+	}
+}
+
+func TestPrefetchCancelsStalePrefetches(t *testing.T) {
+	var (
+		fetched   = make(chan string, 2)
+		cancelled = make(chan bool, 1)
+		started   = make(chan struct{})
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := path.Base(r.URL.Path)
+		if id == "slow" {
+			close(started)
+			select {
+			case <-r.Context().Done():
+				cancelled <- true
+			case <-time.After(5 * time.Second):
+				cancelled <- false
+			}
+			return
+		}
+		fetched <- id
+		_, _ = fmt.Fprintf(w, `{"data":{"id":%q}}`, id)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+	client.Cache = NewCache("")
+
+	// One worker, busy with the slow article while the others wait behind it
+	p := NewPrefetcher(client, 1)
+	p.Prefetch("slow", "stale")
+	<-started
+	p.Prefetch("fresh")
+
+	if !<-cancelled {
+		t.Error("the in-flight prefetch wasn't cancelled")
+	}
+	if id := <-fetched; id != "fresh" {
+		t.Errorf("fetched %s, want only the fresh prefetch", id)
+	}
+	p.Stop()
+	if len(fetched) > 0 {
+		t.Errorf("fetched %s, want only the fresh prefetch", <-fetched)
+	}
+}
+
+func TestPrefetchQueuesTheLatestArticleWhenTheQueueIsFull(t *testing.T) {
+	var (
+		fetched = make(chan string, prefetchQueue+2)
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := path.Base(r.URL.Path)
+		if id == "slow" {
+			close(started)
+			<-release
+			return
+		}
+		fetched <- id
+		_, _ = fmt.Fprintf(w, `{"data":{"id":%q}}`, id)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{APIURL: server.URL})
+	client.Cache = NewCache("")
+
+	// The only worker is stuck on the slow article while the rows scrolled past fill
+	// the queue behind it
+	p := NewPrefetcher(client, 1)
+	defer p.Stop()
+	p.Prefetch("slow")
+	<-started
+	var rows []string
+	for i := range prefetchQueue {
+		rows = append(rows, fmt.Sprintf("row%d", i))
+	}
+	p.Prefetch(rows...)
+	p.Prefetch("latest")
+	close(release)
+
+	select {
+	case id := <-fetched:
+		if id != "latest" {
+			t.Errorf("fetched %s, want the latest article", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the latest article was never fetched")
+	}
+}
+
+// cached reports whether the articles are in the client's cache
+func cached(client *Client, articleIDs ...string) bool {
+	for _, id := range articleIDs {
+		if _, ok := client.Cache.get(scopePublic, "/api/core/v1/content/articles/"+id+"/"); !ok {
+			return false
+		}
+	}
+	return true
+}
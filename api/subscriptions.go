@@ -50,6 +50,19 @@ func (s *Subscription) GetTotalQuantity() int {
 	return s.DefaultQuantity
 }
 
+// SumQuantity returns the quantity across the subscription's preferences, or its
+// default quantity if it has none
+func (s *Subscription) SumQuantity() int {
+	if len(s.DefaultPreferences) == 0 {
+		return s.DefaultQuantity
+	}
+	total := 0
+	for _, pref := range s.DefaultPreferences {
+		total += pref.GetQuantity()
+	}
+	return total
+}
+
 // GetQuantity returns the quantity for a preference as an int
 func (p *SubscriptionPreference) GetQuantity() int {
 	return p.Quantity
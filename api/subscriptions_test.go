@@ -339,6 +339,26 @@ func TestSubscriptionGetTotalQuantity(t *testing.T) {
 	}
 }
 
+func TestSubscriptionSumQuantity(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  Subscription
+		want int
+	}{
+		{"no preferences", Subscription{DefaultQuantity: 3}, 3},
+		{"one preference", Subscription{DefaultQuantity: 3, DefaultPreferences: []SubscriptionPreference{{Quantity: 3}}}, 3},
+		{"several preferences", Subscription{DefaultQuantity: 2, DefaultPreferences: []SubscriptionPreference{{Quantity: 2}, {Quantity: 1}, {Quantity: 4}}}, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sub.SumQuantity(); got != tt.want {
+				t.Errorf("SumQuantity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetAvailableSubscriptions(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/core/v1/subscriptions/available" {
@@ -493,3 +513,34 @@ func TestGetAvailableProducts(t *testing.T) {
 		t.Error("Expected IsSubscription to be false")
 	}
 }
+
+func TestGetSubscriptionNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"detail": "Not found."}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		APIURL:      server.URL,
+		AccessToken: "test-token",
+	}
+	client := NewClient(cfg)
+
+	_, err := client.GetSubscription("missing")
+	if err == nil {
+		t.Fatal("Expected error for missing subscription")
+	}
+
+	if err.Error() != "Not found." {
+		t.Errorf("Expected error message 'Not found.', got '%s'", err.Error())
+	}
+
+	if !IsNotFound(err) {
+		t.Error("Expected IsNotFound to be true")
+	}
+
+	if IsUnauthorized(err) {
+		t.Error("Expected IsUnauthorized to be false")
+	}
+}
@@ -0,0 +1,120 @@
+package bookmarks
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/utils"
+)
+
+// Order is how bookmarks are sorted
+type Order string
+
+const (
+	OrderListed     Order = ""           // As the API lists them
+	OrderCreated    Order = "created"    // Newest first
+	OrderTitle      Order = "title"      // By article title
+	OrderCollection Order = "collection" // By collection, the ones in none last, then newest first
+)
+
+// Orders lists the values accepted by ParseOrder
+var Orders = []string{string(OrderCreated), string(OrderTitle), string(OrderCollection)}
+
+// ParseOrder validates a sort order; empty is OrderListed
+func ParseOrder(value string) (Order, error) {
+	switch Order(strings.ToLower(value)) {
+	case OrderListed:
+		return OrderListed, nil
+	case OrderCreated:
+		return OrderCreated, nil
+	case OrderTitle:
+		return OrderTitle, nil
+	case OrderCollection:
+		return OrderCollection, nil
+	}
+	return OrderListed, fmt.Errorf("invalid sort order %q (expected one of: %s)", value, strings.Join(Orders, ", "))
+}
+
+// Sort sorts bookmarks in order, keeping the order of equal ones
+func Sort(bookmarks []api.Bookmark, order Order) {
+	// The API's timestamps come in several formats. If any doesn't parse, newest
+	// first keeps the listed order rather than ordering only some of them.
+	created := make(map[string]time.Time, len(bookmarks))
+	parsed := true
+	for _, bookmark := range bookmarks {
+		t, err := utils.ParseTimestamp(bookmark.CreatedAt)
+		if err != nil {
+			parsed = false
+			break
+		}
+		created[bookmark.CreatedAt] = t
+	}
+	newest := func(a, b api.Bookmark) int {
+		if !parsed {
+			return 0
+		}
+		return created[b.CreatedAt].Compare(created[a.CreatedAt])
+	}
+
+	switch order {
+	case OrderTitle:
+		slices.SortStableFunc(bookmarks, func(a, b api.Bookmark) int {
+			return strings.Compare(strings.ToLower(a.Article.Title), strings.ToLower(b.Article.Title))
+		})
+	case OrderCollection:
+		slices.SortStableFunc(bookmarks, func(a, b api.Bookmark) int {
+			if (a.Collection == "") != (b.Collection == "") {
+				if a.Collection == "" {
+					return 1
+				}
+				return -1
+			}
+			return cmp.Or(strings.Compare(strings.ToLower(a.Collection), strings.ToLower(b.Collection)), newest(a, b))
+		})
+	case OrderCreated:
+		slices.SortStableFunc(bookmarks, newest)
+	}
+}
+
+// Filter returns the bookmarks in collection, whatever its case
+func Filter(bookmarks []api.Bookmark, collection string) []api.Bookmark {
+	var filtered []api.Bookmark
+	for _, bookmark := range bookmarks {
+		if strings.EqualFold(bookmark.Collection, collection) {
+			filtered = append(filtered, bookmark)
+		}
+	}
+	return filtered
+}
+
+// Collection is a named collection of bookmarks
+type Collection struct {
+	Name  string
+	Count int // Number of bookmarks in it
+}
+
+// Collections returns the collections the bookmarks are in, by name
+func Collections(bookmarks []api.Bookmark) []Collection {
+	var collections []Collection
+	for _, bookmark := range bookmarks {
+		if bookmark.Collection == "" {
+			continue
+		}
+		i := slices.IndexFunc(collections, func(c Collection) bool {
+			return strings.EqualFold(c.Name, bookmark.Collection)
+		})
+		if i < 0 {
+			collections = append(collections, Collection{Name: bookmark.Collection})
+			i = len(collections) - 1
+		}
+		collections[i].Count++
+	}
+	slices.SortFunc(collections, func(a, b Collection) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return collections
+}
@@ -0,0 +1,141 @@
+package bookmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+)
+
+func testBookmarks() []api.Bookmark {
+	return []api.Bookmark{
+		{ID: "b1", CreatedAt: "2025-01-01T00:00:00Z", Article: api.Article{ID: "a1", Title: "pour over basics"}},
+		{ID: "b2", CreatedAt: "2025-03-01T00:00:00Z", Article: api.Article{ID: "a2", Title: "Espresso ratios"}, Collection: "Espresso dial-in"},
+		{ID: "b3", CreatedAt: "2025-02-01T00:00:00Z", Article: api.Article{ID: "a3", Title: "Milk steaming"}, Collection: "Bar training"},
+		{ID: "b4", CreatedAt: "2025-04-01T00:00:00Z", Article: api.Article{ID: "a4", Title: "Grinders"}, Collection: "espresso dial-in"},
+	}
+}
+
+func ids(bookmarks []api.Bookmark) []string {
+	ids := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		order Order
+		want  []string
+	}{
+		{OrderListed, []string{"b1", "b2", "b3", "b4"}},
+		{OrderCreated, []string{"b4", "b2", "b3", "b1"}},
+		{OrderTitle, []string{"b2", "b4", "b3", "b1"}},
+		{OrderCollection, []string{"b3", "b4", "b2", "b1"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			bookmarks := testBookmarks()
+			Sort(bookmarks, tt.order)
+			if got := ids(bookmarks); !slices.Equal(got, tt.want) {
+				t.Errorf("Sort(%q) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortMixedTimestampFormats(t *testing.T) {
+	bookmarks := []api.Bookmark{
+		{ID: "b1", CreatedAt: "2025-01-01T00:00:00Z"},
+		{ID: "b2", CreatedAt: "2025-03-01 09:30:00"},
+		{ID: "b3", CreatedAt: "2025-02-01T12:00:00.123456+02:00"},
+		{ID: "b4", CreatedAt: "1743465600000"}, // 2025-04-01 in Unix milliseconds
+		{ID: "b5", CreatedAt: "2025-01-15"},
+	}
+	Sort(bookmarks, OrderCreated)
+	if got, want := ids(bookmarks), []string{"b4", "b2", "b3", "b5", "b1"}; !slices.Equal(got, want) {
+		t.Errorf("Sort(created) = %v, want %v", got, want)
+	}
+
+	// One timestamp that doesn't parse keeps the listed order
+	bookmarks = append(testBookmarks(), api.Bookmark{ID: "b5", CreatedAt: "yesterday"})
+	Sort(bookmarks, OrderCreated)
+	if got, want := ids(bookmarks), []string{"b1", "b2", "b3", "b4", "b5"}; !slices.Equal(got, want) {
+		t.Errorf("Sort(created) with an unknown format = %v, want %v", got, want)
+	}
+}
+
+func TestFilterAndCollections(t *testing.T) {
+	if got := ids(Filter(testBookmarks(), "ESPRESSO DIAL-IN")); !slices.Equal(got, []string{"b2", "b4"}) {
+		t.Errorf("Filter = %v, want b2 and b4 whatever the case", got)
+	}
+
+	want := []Collection{{Name: "Bar training", Count: 1}, {Name: "Espresso dial-in", Count: 2}}
+	got := Collections(testBookmarks())
+	if !slices.Equal(got, want) {
+		t.Errorf("Collections = %v, want %v", got, want)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int // the error the API answers with, none if zero
+		wantLocal bool
+	}{
+		{"API keeps annotations", 0, false},
+		{"API rejects the update", http.StatusMethodNotAllowed, true},
+		{"API doesn't implement the update", http.StatusNotImplemented, true},
+		{"API has no annotation endpoint", http.StatusNotFound, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					_, _ = fmt.Fprintf(w, `{"meta":{"code":%d,"message":"%s"}}`, tt.status, http.StatusText(tt.status))
+					return
+				}
+				var changes struct{ Collection, Note string }
+				_ = json.NewDecoder(r.Body).Decode(&changes)
+				_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+					"id": "b1", "collection": changes.Collection, "note": changes.Note,
+				}})
+			}))
+			defer server.Close()
+
+			client := api.NewClient(&config.Config{APIURL: server.URL, AccessToken: "token"})
+			dir := t.TempDir()
+			store := NewStore()
+			store.Annotations["a1"] = Annotation{Note: "Older note"}
+
+			bookmark := api.Bookmark{ID: "b1", Article: api.Article{ID: "a1"}, Collection: "Brewing", Note: "Use 18g"}
+			local, err := store.Annotate(client, dir, bookmark)
+			if err != nil {
+				t.Fatalf("Annotate failed: %v", err)
+			}
+			if local != tt.wantLocal {
+				t.Errorf("Annotate kept it locally = %v, want %v", local, tt.wantLocal)
+			}
+
+			loaded, err := Load(dir)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			listed := []api.Bookmark{{ID: "b1", Article: api.Article{ID: "a1"}}}
+			loaded.Apply(listed)
+			if tt.wantLocal && (listed[0].Collection != "Brewing" || listed[0].Note != "Use 18g") {
+				t.Errorf("the saved store applies %q and %q, want the annotation", listed[0].Collection, listed[0].Note)
+			}
+			if !tt.wantLocal && len(loaded.Annotations) > 0 {
+				t.Errorf("the store kept %v, want the local annotation dropped", loaded.Annotations)
+			}
+		})
+	}
+}
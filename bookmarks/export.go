@@ -0,0 +1,279 @@
+package bookmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/templates"
+)
+
+// Format is a format bookmarks are exported in
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+)
+
+// Formats lists the values accepted by ParseFormat
+var Formats = []string{string(FormatMarkdown), string(FormatHTML), string(FormatJSON)}
+
+// ParseFormat validates an export format
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatMarkdown, "markdown":
+		return FormatMarkdown, nil
+	case FormatHTML:
+		return FormatHTML, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return FormatMarkdown, fmt.Errorf("invalid export format %q (expected one of: %s)", value, strings.Join(Formats, ", "))
+}
+
+// Export writes bookmarks, in their order, with their notes and the content of their
+// articles, which must have been fetched. Markdown and HTML group them by collection.
+func Export(w io.Writer, format Format, bookmarks []api.Bookmark, exportedAt time.Time) error {
+	switch format {
+	case FormatJSON:
+		return exportJSON(w, bookmarks)
+	case FormatHTML:
+		return exportHTML(w, bookmarks, exportedAt)
+	default:
+		return exportMarkdown(w, bookmarks, exportedAt)
+	}
+}
+
+// exportedBookmark is the JSON schema of an exported bookmark: the --output one, with
+// the content of the article
+type exportedBookmark struct {
+	output.Bookmark
+	Article exportedArticle `json:"article"`
+}
+
+type exportedArticle struct {
+	output.Article
+	Content string `json:"content"`
+}
+
+func exportJSON(w io.Writer, bookmarks []api.Bookmark) error {
+	exported := make([]exportedBookmark, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		exported[i] = exportedBookmark{
+			Bookmark: output.FromBookmark(bookmark),
+			Article: exportedArticle{
+				Article: output.FromArticle(bookmark.Article),
+				Content: templates.ContentSource(bookmark.Article.Content),
+			},
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exported)
+}
+
+func exportMarkdown(w io.Writer, bookmarks []api.Bookmark, exportedAt time.Time) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Bookmarks\n\nExported on %s.\n", exportedAt.Format("January 2, 2006"))
+
+	groups := groupByCollection(bookmarks)
+	level := articleLevel(groups)
+	for _, group := range groups {
+		if group.Name != "" {
+			fmt.Fprintf(&b, "\n## %s\n", group.Name)
+		}
+		for _, bookmark := range group.Bookmarks {
+			article := bookmark.Article
+			fmt.Fprintf(&b, "\n%s %s\n", strings.Repeat("#", level), article.Title)
+			if meta := articleMeta(article); meta != "" {
+				fmt.Fprintf(&b, "\n*%s*\n", meta)
+			}
+			if bookmark.Note != "" {
+				fmt.Fprintf(&b, "\n> **Note:** %s\n", strings.ReplaceAll(bookmark.Note, "\n", "\n> "))
+			}
+			if content := articleContent(article, level); content != "" {
+				fmt.Fprintf(&b, "\n%s\n", content)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlExport = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Bookmarks</title>
+<style>
+body { font-family: sans-serif; line-height: 1.5; max-width: 46em; margin: 2em auto; padding: 0 1em; }
+.meta { color: #666; }
+.note { border-left: 3px solid #c8873a; margin-left: 0; padding-left: 1em; }
+</style>
+</head>
+<body>
+<h1>Bookmarks</h1>
+<p class="meta">Exported on {{.Date}}.</p>
+{{- range .Groups}}
+{{- if .Name}}
+<h2>{{.Name}}</h2>
+{{- end}}
+{{- range .Bookmarks}}
+<article>
+{{.Title}}
+{{- if .Meta}}
+<p class="meta">{{.Meta}}</p>
+{{- end}}
+{{- if .Note}}
+<blockquote class="note"><p><strong>Note</strong></p>
+{{.Note}}</blockquote>
+{{- end}}
+{{.Content}}</article>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func exportHTML(w io.Writer, bookmarks []api.Bookmark, exportedAt time.Time) error {
+	type htmlBookmark struct {
+		Title   template.HTML
+		Meta    string
+		Note    template.HTML
+		Content template.HTML
+	}
+	type htmlGroup struct {
+		Name      string
+		Bookmarks []htmlBookmark
+	}
+
+	groups := groupByCollection(bookmarks)
+	level := articleLevel(groups)
+	data := struct {
+		Date   string
+		Groups []htmlGroup
+	}{Date: exportedAt.Format("January 2, 2006")}
+	for _, group := range groups {
+		g := htmlGroup{Name: group.Name}
+		for _, bookmark := range group.Bookmarks {
+			article := bookmark.Article
+			title := template.HTMLEscapeString(article.Title)
+			g.Bookmarks = append(g.Bookmarks, htmlBookmark{
+				Title: template.HTML(fmt.Sprintf("<h%d>%s</h%d>", level, title, level)),
+				Meta:  articleMeta(article),
+				// Notes and content are Markdown, rendered without raw HTML
+				Note:    template.HTML(templates.MarkdownHTML(bookmark.Note)),
+				Content: template.HTML(templates.MarkdownHTML(articleContent(article, level))),
+			})
+		}
+		data.Groups = append(data.Groups, g)
+	}
+	return htmlExport.Execute(w, data)
+}
+
+// group is the bookmarks of a collection
+type group struct {
+	Name      string
+	Bookmarks []api.Bookmark
+}
+
+// groupByCollection groups bookmarks by collection, in the order the collections first
+// appear, the bookmarks in none last
+func groupByCollection(bookmarks []api.Bookmark) []group {
+	var groups []group
+	var rest []api.Bookmark
+	for _, bookmark := range bookmarks {
+		if bookmark.Collection == "" {
+			rest = append(rest, bookmark)
+			continue
+		}
+		i := len(groups)
+		for j, g := range groups {
+			if strings.EqualFold(g.Name, bookmark.Collection) {
+				i = j
+				break
+			}
+		}
+		if i == len(groups) {
+			groups = append(groups, group{Name: bookmark.Collection})
+		}
+		groups[i].Bookmarks = append(groups[i].Bookmarks, bookmark)
+	}
+
+	if len(rest) > 0 {
+		name := ""
+		if len(groups) > 0 {
+			name = "Other bookmarks"
+		}
+		groups = append(groups, group{Name: name, Bookmarks: rest})
+	}
+	return groups
+}
+
+// articleLevel is the heading level of the article titles, below the collections if
+// there are any
+func articleLevel(groups []group) int {
+	if len(groups) > 0 && groups[0].Name != "" {
+		return 3
+	}
+	return 2
+}
+
+// articleMeta describes an article in a line
+func articleMeta(article api.Article) string {
+	var parts []string
+	if article.Author != "" {
+		parts = append(parts, "By "+article.Author)
+	}
+	if article.ReadTime > 0 {
+		parts = append(parts, fmt.Sprintf("%d min read", article.ReadTime))
+	}
+	if article.Tags != "" {
+		parts = append(parts, "Tags: "+article.Tags)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// articleContent returns the Markdown of an article with its headings below the title,
+// at level
+func articleContent(article api.Article, level int) string {
+	return shiftHeadings(strings.TrimSpace(templates.ContentSource(article.Content)), level)
+}
+
+// shiftHeadings moves the ATX headings of Markdown down by levels, up to level 6,
+// leaving code blocks alone
+func shiftHeadings(markdown string, levels int) string {
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		depth := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		if depth == 0 || depth > 6 || len(line)-len(trimmed) > 3 {
+			continue
+		}
+		if rest := trimmed[depth:]; rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue // Not a heading, e.g. #hashtag
+		}
+		lines[i] = strings.Repeat("#", min(depth+levels, 6)) + trimmed[depth:]
+	}
+	return strings.Join(lines, "\n")
+}
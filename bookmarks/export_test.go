@@ -0,0 +1,67 @@
+package bookmarks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+func exportBookmarks() []api.Bookmark {
+	return []api.Bookmark{
+		{ID: "b1", Article: api.Article{ID: "a1", Title: "Pour over", Author: "Ana", ReadTime: 4, Content: "# Steps\n\nUse {{highlight \"fresh\"}} beans."}},
+		{ID: "b2", Collection: "Espresso", Note: "Try 1:2.2", Article: api.Article{ID: "a2", Title: "Ratios", Content: "```\n# not a heading\n```\n\n<script>alert(1)</script>"}},
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := Export(&b, FormatMarkdown, exportBookmarks(), time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	want := "# Bookmarks\n\nExported on May 1, 2025.\n" +
+		"\n## Espresso\n\n### Ratios\n\n> **Note:** Try 1:2.2\n\n```\n# not a heading\n```\n\n<script>alert(1)</script>\n" +
+		"\n## Other bookmarks\n\n### Pour over\n\n*By Ana · 4 min read*\n\n#### Steps\n\nUse fresh beans.\n"
+	if b.String() != want {
+		t.Errorf("Export wrote:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	var b strings.Builder
+	if err := Export(&b, FormatHTML, exportBookmarks(), time.Now()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	html := b.String()
+	for _, want := range []string{"<h2>Espresso</h2>", "<h3>Ratios</h3>", "<p>Try 1:2.2</p>", "<h4>Steps</h4>", "<code># not a heading\n</code>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Export HTML is missing %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("Export HTML kept the raw HTML of the content:\n%s", html)
+	}
+}
+
+func TestShiftHeadings(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"headings", "# One\n## Two\ntext", "### One\n#### Two\ntext"},
+		{"capped at level 6", "##### Five", "###### Five"},
+		{"code blocks", "~~~\n# code\n~~~\n# After", "~~~\n# code\n~~~\n### After"},
+		{"not headings", "#hashtag\n    # indented code", "#hashtag\n    # indented code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftHeadings(tt.markdown, 2); got != tt.want {
+				t.Errorf("shiftHeadings(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package bookmarks organizes the user's bookmarks in named collections and attaches
+// personal notes to them. The API keeps them where it supports them; otherwise they
+// are kept in a Store on disk. Bookmarks can be sorted, filtered and exported.
+package bookmarks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+// storeVersion is the version of the on-disk format; stores of other versions are
+// ignored
+const storeVersion = 1
+
+// Annotation is the collection and note of a bookmarked article
+type Annotation struct {
+	Collection string    `json:"collection,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Store keeps the annotations of the bookmarks the API can't keep them for
+type Store struct {
+	Version int `json:"version"`
+	// Annotations are by article ID, which survives removing and adding a bookmark again
+	Annotations map[string]Annotation `json:"annotations"`
+}
+
+// NewStore returns an empty store
+func NewStore() *Store {
+	return &Store{Version: storeVersion, Annotations: make(map[string]Annotation)}
+}
+
+// Path returns the file the store is kept in
+func Path(dir string) string {
+	return filepath.Join(dir, "annotations.json")
+}
+
+// Load reads the store kept in dir. Without one, or with one of another version, it
+// returns an empty store.
+func Load(dir string) (*Store, error) {
+	data, err := os.ReadFile(Path(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewStore(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmark notes: %w", err)
+	}
+
+	store := NewStore()
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmark notes: %w", err)
+	}
+	if store.Version != storeVersion {
+		return NewStore(), nil
+	}
+	if store.Annotations == nil {
+		store.Annotations = make(map[string]Annotation)
+	}
+	return store, nil
+}
+
+// Save writes the store to dir, replacing the previous one atomically
+func (s *Store) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmark notes: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create bookmarks directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "annotations.*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save bookmark notes: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save bookmark notes: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save bookmark notes: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), Path(dir)); err != nil {
+		return fmt.Errorf("failed to save bookmark notes: %w", err)
+	}
+	return nil
+}
+
+// Apply sets the collection and note of the bookmarks kept in the store. The store only
+// has the ones the API didn't keep, so they replace what the API sent.
+func (s *Store) Apply(bookmarks []api.Bookmark) {
+	for i := range bookmarks {
+		if a, ok := s.Annotations[ArticleID(bookmarks[i])]; ok {
+			bookmarks[i].Collection, bookmarks[i].Note = a.Collection, a.Note
+		}
+	}
+}
+
+// Annotate saves the collection and note of a bookmark, through the API if it keeps
+// them and in the store, saved to dir, otherwise. It reports whether they were kept
+// locally.
+func (s *Store) Annotate(client *api.Client, dir string, bookmark api.Bookmark) (bool, error) {
+	articleID := ArticleID(bookmark)
+	_, err := client.UpdateBookmark(bookmark.ID, api.BookmarkChanges{Collection: &bookmark.Collection, Note: &bookmark.Note})
+	if err == nil {
+		// The API has them now, older local ones would hide them
+		if _, ok := s.Annotations[articleID]; ok {
+			delete(s.Annotations, articleID)
+			return false, s.Save(dir)
+		}
+		return false, nil
+	}
+	// Servers without the annotation endpoint at all answer 404
+	if !api.IsUnsupported(err) && !api.IsNotFound(err) {
+		return false, err
+	}
+
+	if bookmark.Collection == "" && bookmark.Note == "" {
+		delete(s.Annotations, articleID)
+	} else {
+		s.Annotations[articleID] = Annotation{Collection: bookmark.Collection, Note: bookmark.Note, UpdatedAt: time.Now()}
+	}
+	return true, s.Save(dir)
+}
+
+// ArticleID returns the ID of a bookmarked article
+func ArticleID(bookmark api.Bookmark) string {
+	// The nested article is used since article_id might not be populated at top level
+	if bookmark.Article.ID != "" {
+		return bookmark.Article.ID
+	}
+	return bookmark.ArticleID
+}
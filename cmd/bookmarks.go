@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/bookmarks"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/spf13/cobra"
+)
+
+var learnBookmarksNoteCmd = &cobra.Command{
+	Use:   "note <article-id> <note>",
+	Short: "Attach a personal note to a bookmark",
+	Long: `Attach a personal note to the bookmark of an article, replacing the one it had. An
+empty note removes it. Notes are kept by the server where it supports them, and on this
+computer otherwise.`,
+	Example: `  bc-cli learn bookmarks note a1b2c3 "Try 1:2.2 with the new grinder"`,
+	Args:    usageArgs(cobra.ExactArgs(2)),
+	RunE:    runLearnBookmarksNote,
+}
+
+var learnBookmarksMoveCmd = &cobra.Command{
+	Use:   "move <article-id> <collection>",
+	Short: "Put a bookmark in a collection",
+	Long: `Put the bookmark of an article in a named collection, like "Espresso dial-in" or
+"Team onboarding". An empty name takes it out of its collection.`,
+	Example: `  bc-cli learn bookmarks move a1b2c3 "Espresso dial-in"`,
+	Args:    usageArgs(cobra.ExactArgs(2)),
+	RunE:    runLearnBookmarksMove,
+}
+
+var learnBookmarksCollectionsCmd = &cobra.Command{
+	Use:   "collections",
+	Short: "List your bookmark collections",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runLearnBookmarksCollections,
+}
+
+var learnBookmarksExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your bookmarked articles with your notes",
+	Long: `Write your bookmarked articles, with their content and your notes, to standard
+output for sharing: as Markdown or HTML grouped by collection, or as JSON.`,
+	Example: `  bc-cli learn bookmarks export > bookmarks.md
+  bc-cli learn bookmarks export --format html --collection "Team onboarding" > onboarding.html`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runLearnBookmarksExport,
+}
+
+func init() {
+	learnBookmarksCmd.AddCommand(learnBookmarksNoteCmd)
+	learnBookmarksCmd.AddCommand(learnBookmarksMoveCmd)
+	learnBookmarksCmd.AddCommand(learnBookmarksCollectionsCmd)
+	learnBookmarksCmd.AddCommand(learnBookmarksExportCmd)
+
+	for _, cmd := range []*cobra.Command{learnBookmarksCmd, learnBookmarksExportCmd} {
+		cmd.Flags().String("collection", "", "Only the bookmarks in this collection")
+		cmd.Flags().String("sort", "", "Sort by "+strings.Join(bookmarks.Orders, ", ")+" (default: as listed)")
+		_ = cmd.RegisterFlagCompletionFunc("collection", completeCollections)
+		_ = cmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(bookmarks.Orders, cobra.ShellCompDirectiveNoFileComp))
+	}
+	learnBookmarksExportCmd.Flags().String("format", string(bookmarks.FormatMarkdown), "Export format: "+strings.Join(bookmarks.Formats, ", "))
+	_ = learnBookmarksExportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(bookmarks.Formats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// bookmarkFilter is the collection and order the bookmarks are listed in
+type bookmarkFilter struct {
+	collection string
+	order      bookmarks.Order
+}
+
+// isSet reports whether the bookmarks are filtered or sorted, which needs all of them
+func (f bookmarkFilter) isSet() bool {
+	return f.collection != "" || f.order != bookmarks.OrderListed
+}
+
+// apply filters and sorts bookmarks
+func (f bookmarkFilter) apply(list []api.Bookmark) []api.Bookmark {
+	if f.collection != "" {
+		list = bookmarks.Filter(list, f.collection)
+	}
+	bookmarks.Sort(list, f.order)
+	return list
+}
+
+// bookmarkFilterFlags reads the --collection and --sort flags
+func bookmarkFilterFlags(cmd *cobra.Command) (bookmarkFilter, error) {
+	collection, _ := cmd.Flags().GetString("collection")
+	sort, _ := cmd.Flags().GetString("sort")
+	order, err := bookmarks.ParseOrder(sort)
+	if err != nil {
+		return bookmarkFilter{}, withExitCode(ExitCodeUsage, err)
+	}
+	return bookmarkFilter{collection: collection, order: order}, nil
+}
+
+// bookmarksClient returns a client for the logged in user's bookmarks
+func bookmarksClient() (*api.Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.IsAuthenticated() {
+		return nil, withExitCode(ExitCodeNotAuthenticated, errors.New("not logged in, please run: bc-cli login"))
+	}
+	return api.NewClient(cfg), nil
+}
+
+// loadBookmarks returns all of the user's bookmarks with their collections and notes,
+// and the store of the ones the API doesn't keep with the directory it's kept in
+func loadBookmarks(client *api.Client) ([]api.Bookmark, *bookmarks.Store, string, error) {
+	list, err := client.ListBookmarks()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to fetch bookmarks: %w", err)
+	}
+
+	dir, err := config.GetBookmarksDir(client.Config.UserID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to find bookmarks directory: %w", err)
+	}
+	store, err := bookmarks.Load(dir)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	store.Apply(list)
+	return list, store, dir, nil
+}
+
+// annotateBookmark finds the bookmark of an article and changes its collection and note
+// with change, printing done once they're saved
+func annotateBookmark(articleID string, change func(bookmark *api.Bookmark), done string) error {
+	client, err := bookmarksClient()
+	if err != nil {
+		return err
+	}
+	list, store, dir, err := loadBookmarks(client)
+	if err != nil {
+		return err
+	}
+
+	for _, bookmark := range list {
+		if bookmarks.ArticleID(bookmark) != articleID {
+			continue
+		}
+		change(&bookmark)
+		local, err := store.Annotate(client, dir, bookmark)
+		if err != nil {
+			return fmt.Errorf("failed to update bookmark: %w", err)
+		}
+		if local {
+			done += " (kept on this computer)"
+		}
+		fmt.Fprintln(tui.Output(), "✓ "+done)
+		return nil
+	}
+	return withExitCode(ExitCodeNotFound, fmt.Errorf("article %s isn't bookmarked", articleID))
+}
+
+func runLearnBookmarksNote(cmd *cobra.Command, args []string) error {
+	note := strings.TrimSpace(args[1])
+	done := "Note saved"
+	if note == "" {
+		done = "Note removed"
+	}
+	return annotateBookmark(args[0], func(bookmark *api.Bookmark) {
+		bookmark.Note = note
+	}, done)
+}
+
+func runLearnBookmarksMove(cmd *cobra.Command, args []string) error {
+	collection := strings.TrimSpace(args[1])
+	done := fmt.Sprintf("Moved to %q", collection)
+	if collection == "" {
+		done = "Taken out of its collection"
+	}
+	return annotateBookmark(args[0], func(bookmark *api.Bookmark) {
+		bookmark.Collection = collection
+	}, done)
+}
+
+func runLearnBookmarksCollections(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := bookmarksClient()
+	if err != nil {
+		return err
+	}
+	list, _, _, err := loadBookmarks(client)
+	if err != nil {
+		return err
+	}
+
+	collections := bookmarks.Collections(list)
+	items := make([]output.Collection, len(collections))
+	for i, c := range collections {
+		items[i] = output.Collection{Name: c.Name, Bookmarks: c.Count}
+	}
+	if format.IsStructured() {
+		return writeOutput(format, items, output.CollectionsTable(items))
+	}
+
+	if len(items) == 0 {
+		fmt.Fprintln(tui.Output(), "You don't have any collections yet.")
+		fmt.Fprintln(tui.Output(), `Put a bookmark in one with: bc-cli learn bookmarks move <article-id> "Espresso dial-in"`)
+		return nil
+	}
+	for _, c := range items {
+		fmt.Fprintf(tui.Output(), "%s (%d)\n", c.Name, c.Bookmarks)
+	}
+	return nil
+}
+
+func runLearnBookmarksExport(cmd *cobra.Command, args []string) error {
+	value, _ := cmd.Flags().GetString("format")
+	format, err := bookmarks.ParseFormat(value)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+	filter, err := bookmarkFilterFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := bookmarksClient()
+	if err != nil {
+		return err
+	}
+	list, _, _, err := loadBookmarks(client)
+	if err != nil {
+		return err
+	}
+	list = filter.apply(list)
+
+	// The bookmarks only have the summaries of their articles
+	progress := tui.ShowsProgress() && len(list) > 0
+	for i, bookmark := range list {
+		if progress {
+			fmt.Fprintf(os.Stderr, "\rFetching articles... %d/%d", i+1, len(list))
+		}
+		article, err := client.GetArticle(bookmarks.ArticleID(bookmark))
+		if err != nil {
+			if progress {
+				fmt.Fprintln(os.Stderr)
+			}
+			return fmt.Errorf("failed to fetch article %s: %w", bookmark.Article.Title, err)
+		}
+		list[i].Article = *article
+	}
+	if progress {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	return bookmarks.Export(os.Stdout, format, list, time.Now())
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/bookmarks"
+	"github.com/hassek/bc-cli/config"
+)
+
+func TestFilteredBookmarksKeepToTheCollection(t *testing.T) {
+	useTempHome(t)
+
+	// Two pages of bookmarks, one of them in the collection kept on this computer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = fmt.Fprint(w, `{"data":{"count":2,"next":null,"results":[
+				{"id":"b2","article_id":"a2","article":{"id":"a2","title":"Pour over basics"}}]}}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data":{"count":2,"next":"%s/api/core/v1/content/bookmarks/?page=2","results":[
+			{"id":"b1","article_id":"a1","article":{"id":"a1","title":"Dialing in espresso"}}]}}`, "http://"+r.Host)
+	}))
+	defer server.Close()
+
+	dir, err := config.GetBookmarksDir("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := bookmarks.NewStore()
+	store.Annotations["a2"] = bookmarks.Annotation{Collection: "Filter"}
+	if err := store.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	client := api.NewClient(&config.Config{APIURL: server.URL, AccessToken: "token", UserID: "u1"})
+	client.Cache = nil
+	filter := bookmarkFilter{collection: "Filter"}
+
+	articles, more, err := firstBookmarks(client, filter)
+	if err != nil {
+		t.Fatalf("firstBookmarks() error = %v", err)
+	}
+	if more != nil || len(articles) != 1 || articles[0].ID != "a2" {
+		t.Errorf("firstBookmarks() = %v, more: %v, want only a2 from the collection", articles, more != nil)
+	}
+
+	// What batch actions offer
+	articles, err = filteredBookmarks(client, filter)
+	if err != nil {
+		t.Fatalf("filteredBookmarks() error = %v", err)
+	}
+	if len(articles) != 1 || articles[0].ID != "a2" || articles[0].BookmarkID != "b2" {
+		t.Errorf("filteredBookmarks() = %v, want only a2 from the collection", articles)
+	}
+
+	articles, err = filteredBookmarks(client, bookmarkFilter{})
+	if err != nil {
+		t.Fatalf("filteredBookmarks() error = %v", err)
+	}
+	if len(articles) != 2 {
+		t.Errorf("filteredBookmarks() without a filter = %v, want both bookmarks", articles)
+	}
+
+	// Another account on this computer doesn't get the collection
+	other := api.NewClient(&config.Config{APIURL: server.URL, AccessToken: "token", UserID: "u2"})
+	other.Cache = nil
+	articles, err = filteredBookmarks(other, filter)
+	if err != nil {
+		t.Fatalf("filteredBookmarks() error = %v", err)
+	}
+	if len(articles) != 0 {
+		t.Errorf("filteredBookmarks() for another user = %v, want none", articles)
+	}
+}
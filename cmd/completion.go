@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/bookmarks"
+	"github.com/hassek/bc-cli/cmd/order"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/offline"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/spf13/cobra"
+)
+
+// Completion results are cached on disk so repeated tab presses don't hit the API.
+// Account data changes more often than the catalog and content, so it expires sooner.
+const (
+	accountCompletionTTL = 30 * time.Second
+	catalogCompletionTTL = 10 * time.Minute
+)
+
+// completionCacheEntry is the on-disk format of a cached completion list
+type completionCacheEntry[T any] struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Items     []T       `json:"items"`
+}
+
+// cachedCompletionList returns the cached list called name if it is younger than ttl,
+// otherwise it calls fetch and caches the result. Cache failures are ignored.
+func cachedCompletionList[T any](name string, ttl time.Duration, fetch func() ([]T, error)) ([]T, error) {
+	var path string
+	if dir, err := config.GetCacheDir(); err == nil {
+		path = completionCacheFile(dir, name)
+		if data, err := os.ReadFile(path); err == nil {
+			var entry completionCacheEntry[T]
+			if json.Unmarshal(data, &entry) == nil && time.Since(entry.FetchedAt) < ttl {
+				return entry.Items, nil
+			}
+		}
+	}
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		if data, err := json.Marshal(completionCacheEntry[T]{FetchedAt: time.Now(), Items: items}); err == nil {
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+				_ = os.WriteFile(path, data, 0600)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// completionCacheFile returns the file the completion list called name is cached in
+func completionCacheFile(dir, name string) string {
+	return filepath.Join(dir, "completion-"+name+".json")
+}
+
+// forgetCompletions drops the cached completion lists called names, e.g. after a
+// command changed what they list
+func forgetCompletions(names ...string) {
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		_ = os.Remove(completionCacheFile(dir, name))
+	}
+}
+
+// completionClient returns an API client for completions, or nil when authentication
+// is required but the user isn't logged in
+func completionClient(requireAuth bool) *api.Client {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	if requireAuth && !cfg.IsAuthenticated() {
+		return nil
+	}
+	return api.NewClient(cfg)
+}
+
+// firstArgCompletion wraps a completer so it only runs for the first positional argument
+func firstArgCompletion(complete func(toComplete string) []string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeSubscriptionIDs suggests subscription IDs, optionally limited to some statuses
+func completeSubscriptionIDs(statuses ...string) cobra.CompletionFunc {
+	return firstArgCompletion(func(toComplete string) []string {
+		client := completionClient(true)
+		if client == nil {
+			return nil
+		}
+
+		subscriptions, err := cachedCompletionList("subscriptions", accountCompletionTTL, client.ListSubscriptions)
+		if err != nil {
+			return nil
+		}
+
+		var completions []string
+		for _, s := range subscriptions {
+			if len(statuses) > 0 && !slices.Contains(statuses, s.Status) {
+				continue
+			}
+			completions = append(completions, fmt.Sprintf("%s\t%s (%s)", s.ID, s.Tier, s.Status))
+		}
+		return completions
+	})
+}
+
+// completeOrderIDs suggests the user's order IDs
+func completeOrderIDs() cobra.CompletionFunc {
+	return firstArgCompletion(func(toComplete string) []string {
+		client := completionClient(true)
+		if client == nil {
+			return nil
+		}
+
+		orders, err := cachedCompletionList("orders", accountCompletionTTL, client.ListOrders)
+		if err != nil {
+			return nil
+		}
+
+		completions := make([]string, len(orders))
+		for i, o := range orders {
+			completions[i] = fmt.Sprintf("%s\t%s, %d units (%s)", o.ID, o.Tier, o.GetTotalQuantity(), o.Status)
+		}
+		return completions
+	})
+}
+
+// completeTiers suggests subscription tier names
+func completeTiers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client := completionClient(false)
+	if client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	tiers, err := cachedCompletionList("tiers", catalogCompletionTTL, client.GetAvailableSubscriptions)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, len(tiers))
+	for i, tier := range tiers {
+		completions[i] = fmt.Sprintf("%s\t%s (%s %s)", tier.Tier, tier.Name, tier.Price, tier.Currency)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProductIDs suggests product IDs
+func completeProductIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client := completionClient(false)
+	if client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	products, err := cachedCompletionList("products", catalogCompletionTTL, client.GetAvailableProducts)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, len(products))
+	for i, product := range products {
+		completions[i] = fmt.Sprintf("%s\t%s (%s %s)", product.ID, product.Name, product.Price, product.Currency)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCategorySlugs suggests learn category slugs
+func completeCategorySlugs() cobra.CompletionFunc {
+	return firstArgCompletion(func(toComplete string) []string {
+		client := completionClient(false)
+		if client == nil {
+			return nil
+		}
+
+		categories, err := cachedCompletionList("categories", catalogCompletionTTL, client.ListCategories)
+		if err != nil {
+			return nil
+		}
+
+		completions := make([]string, len(categories))
+		for i, category := range categories {
+			completions[i] = fmt.Sprintf("%s\t%s", category.Slug, category.Name)
+		}
+		return completions
+	})
+}
+
+// completeArticleIDs suggests the IDs of the articles known on this computer: those
+// in the offline copy and the search index. Fetching every list of the knowledge base
+// would take a request per category and section, too slow for a tab press.
+func completeArticleIDs() cobra.CompletionFunc {
+	return firstArgCompletion(func(toComplete string) []string {
+		titles := make(map[string]string)
+		if dir, err := config.GetOfflineDir(); err == nil {
+			if store, err := offline.Load(dir); err == nil {
+				for id, article := range store.Articles {
+					titles[id] = article.Title
+				}
+			}
+		}
+		for _, doc := range loadSearchIndex().Documents() {
+			titles[doc.ID] = doc.Title
+		}
+
+		completions := make([]string, 0, len(titles))
+		for id, title := range titles {
+			completions = append(completions, fmt.Sprintf("%s\t%s", id, title))
+		}
+		slices.Sort(completions)
+		return completions
+	})
+}
+
+// completeBookmarkedArticleIDs suggests the IDs of the user's bookmarked articles
+func completeBookmarkedArticleIDs() cobra.CompletionFunc {
+	return firstArgCompletion(func(toComplete string) []string {
+		client := completionClient(true)
+		if client == nil {
+			return nil
+		}
+
+		list, err := cachedCompletionList("bookmarks", accountCompletionTTL, client.ListBookmarks)
+		if err != nil {
+			return nil
+		}
+
+		completions := make([]string, len(list))
+		for i, bookmark := range list {
+			completions[i] = fmt.Sprintf("%s\t%s", bookmarks.ArticleID(bookmark), bookmark.Article.Title)
+		}
+		return completions
+	})
+}
+
+// completeCollections suggests the names of the user's bookmark collections
+func completeCollections(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client := completionClient(true)
+	if client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	list, err := cachedCompletionList("bookmarks", accountCompletionTTL, client.ListBookmarks)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if dir, err := config.GetBookmarksDir(client.Config.UserID); err == nil {
+		if store, err := bookmarks.Load(dir); err == nil {
+			store.Apply(list)
+		}
+	}
+
+	var names []string
+	for _, c := range bookmarks.Collections(list) {
+		names = append(names, c.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSectionIDs suggests the sections of the category given as the first argument
+func completeSectionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	client := completionClient(false)
+	if client == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// The slug is whatever was typed, so it's hashed to keep the file in the cache directory
+	slug := sha256.Sum256([]byte(args[0]))
+	sections, err := cachedCompletionList("sections-"+hex.EncodeToString(slug[:8]), catalogCompletionTTL, func() ([]api.Section, error) {
+		return client.ListCategorySections(args[0])
+	})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := make([]string, len(sections))
+	for i, section := range sections {
+		completions[i] = fmt.Sprintf("%s\t%s", section.ID, section.Name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLineItem completes --line values one field at a time: the brewing method,
+// then the grind type, leaving the quantity to the user
+func completeLineItem(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	parts := strings.Split(toComplete, ":")
+
+	var completions []string
+	switch len(parts) {
+	case 1:
+		for _, method := range order.BrewingMethods {
+			completions = append(completions, fmt.Sprintf("%s:\t%s", method, order.BrewingMethodDisplay(method)))
+		}
+	case 2:
+		for _, grind := range order.GrindTypes {
+			completions = append(completions, fmt.Sprintf("%s:%s:\t%s", parts[0], grind, order.FormatPreparation(grind, parts[0])))
+		}
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeOutputFormats suggests the values accepted by --output
+func completeOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return output.Formats, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplateNames suggests the names of the output templates
+func completeTemplateNames() cobra.CompletionFunc {
+	return firstArgCompletion(func(toComplete string) []string {
+		return templates.Names()
+	})
+}
+
+// Positional argument completers. Flag completers are registered next to their flags.
+func init() {
+	subscriptionsShowCmd.ValidArgsFunction = completeSubscriptionIDs()
+	subscriptionsPauseCmd.ValidArgsFunction = completeSubscriptionIDs("active")
+	subscriptionsResumeCmd.ValidArgsFunction = completeSubscriptionIDs("paused")
+	subscriptionsCancelCmd.ValidArgsFunction = completeSubscriptionIDs("active", "paused")
+	subscriptionsUpdateCmd.ValidArgsFunction = completeSubscriptionIDs("active", "paused")
+	manageUpdateCmd.ValidArgsFunction = completeSubscriptionIDs("active", "paused")
+
+	orderShowCmd.ValidArgsFunction = completeOrderIDs()
+
+	learnArticlesCmd.ValidArgsFunction = completeCategorySlugs()
+	learnReadCmd.ValidArgsFunction = completeArticleIDs()
+	learnBookmarksNoteCmd.ValidArgsFunction = completeBookmarkedArticleIDs()
+	learnBookmarksMoveCmd.ValidArgsFunction = completeBookmarkedArticleIDs()
+	// Queries are free text
+	learnSearchCmd.ValidArgsFunction = cobra.NoFileCompletions
+
+	templatesShowCmd.ValidArgsFunction = completeTemplateNames()
+	templatesEditCmd.ValidArgsFunction = completeTemplateNames()
+	templatesResetCmd.ValidArgsFunction = completeTemplateNames()
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/offline"
+	"github.com/hassek/bc-cli/search"
+	"github.com/spf13/cobra"
+)
+
+// useTempHome points the config directory at an empty temporary directory
+func useTempHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir, err := config.GetCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestCachedCompletionList(t *testing.T) {
+	tests := []struct {
+		name      string
+		cached    string // contents of the cache file, none if empty
+		ttl       time.Duration
+		fetchErr  error
+		want      []string
+		wantFetch bool
+		wantSaved bool // the fetched list replaces the cache file
+	}{
+		{
+			name:   "Fresh cache is used",
+			cached: `{"fetched_at":"` + time.Now().Format(time.RFC3339Nano) + `","items":["cached"]}`,
+			ttl:    time.Minute,
+			want:   []string{"cached"},
+		},
+		{
+			name:      "Expired cache is fetched again",
+			cached:    `{"fetched_at":"` + time.Now().Add(-time.Hour).Format(time.RFC3339Nano) + `","items":["cached"]}`,
+			ttl:       time.Minute,
+			want:      []string{"fetched"},
+			wantFetch: true,
+			wantSaved: true,
+		},
+		{
+			name:      "Corrupt cache is fetched again",
+			cached:    `{"fetched_at":`,
+			ttl:       time.Minute,
+			want:      []string{"fetched"},
+			wantFetch: true,
+			wantSaved: true,
+		},
+		{
+			name:      "No cache",
+			ttl:       time.Minute,
+			want:      []string{"fetched"},
+			wantFetch: true,
+			wantSaved: true,
+		},
+		{
+			name:      "Fetch error isn't cached",
+			ttl:       time.Minute,
+			fetchErr:  errors.New("offline"),
+			wantFetch: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := useTempHome(t)
+			if tt.cached != "" {
+				if err := os.MkdirAll(dir, 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(completionCacheFile(dir, "tiers"), []byte(tt.cached), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fetched := false
+			got, err := cachedCompletionList("tiers", tt.ttl, func() ([]string, error) {
+				fetched = true
+				if tt.fetchErr != nil {
+					return nil, tt.fetchErr
+				}
+				return []string{"fetched"}, nil
+			})
+
+			if !errors.Is(err, tt.fetchErr) {
+				t.Fatalf("cachedCompletionList() error = %v, want %v", err, tt.fetchErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("cachedCompletionList() = %v, want %v", got, tt.want)
+			}
+			if fetched != tt.wantFetch {
+				t.Errorf("fetched = %v, want %v", fetched, tt.wantFetch)
+			}
+
+			again, _ := cachedCompletionList("tiers", time.Minute, func() ([]string, error) {
+				return []string{"refetched"}, nil
+			})
+			if saved := slices.Equal(again, []string{"fetched"}); saved != tt.wantSaved {
+				t.Errorf("next call returned %v, want the fetched list saved: %v", again, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestCachedCompletionListsAreKeptApart(t *testing.T) {
+	useTempHome(t)
+
+	for _, name := range []string{"sections-espresso", "sections-filter"} {
+		if _, err := cachedCompletionList(name, time.Minute, func() ([]string, error) {
+			return []string{name}, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"sections-espresso", "sections-filter"} {
+		got, _ := cachedCompletionList(name, time.Minute, func() ([]string, error) {
+			return nil, errors.New("should be cached")
+		})
+		if !slices.Equal(got, []string{name}) {
+			t.Errorf("cached list %s = %v, want %v", name, got, []string{name})
+		}
+	}
+}
+
+func TestForgetCompletions(t *testing.T) {
+	useTempHome(t)
+
+	for _, name := range []string{"subscriptions", "orders"} {
+		if _, err := cachedCompletionList(name, time.Minute, func() ([]string, error) {
+			return []string{"old"}, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	forgetCompletions("subscriptions")
+
+	subscriptions, _ := cachedCompletionList("subscriptions", time.Minute, func() ([]string, error) {
+		return []string{"new"}, nil
+	})
+	if !slices.Equal(subscriptions, []string{"new"}) {
+		t.Errorf("forgotten list = %v, want it fetched again", subscriptions)
+	}
+	orders, _ := cachedCompletionList("orders", time.Minute, func() ([]string, error) {
+		return []string{"new"}, nil
+	})
+	if !slices.Equal(orders, []string{"old"}) {
+		t.Errorf("other list = %v, want it still cached", orders)
+	}
+}
+
+func TestCompleteArticleIDs(t *testing.T) {
+	useTempHome(t)
+
+	dir, err := config.GetOfflineDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := offline.NewStore()
+	store.Articles["a1"] = api.Article{ID: "a1", Title: "Dialing in espresso"}
+	if err := store.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	searchIndexOnce.Do(func() {})
+	searchIndex = search.NewIndex()
+	searchIndexPath = filepath.Join(t.TempDir(), searchIndexFile)
+	searchIndex.Add(search.Document{ID: "a2", Title: "Pour over basics"})
+
+	got, directive := completeArticleIDs()(learnReadCmd, nil, "")
+	want := []string{"a1\tDialing in espresso", "a2\tPour over basics"}
+	if !slices.Equal(got, want) {
+		t.Errorf("completeArticleIDs() = %q, want %q", got, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want no file completion", directive)
+	}
+
+	if got, _ := completeArticleIDs()(learnReadCmd, []string{"a1"}, ""); got != nil {
+		t.Errorf("completeArticleIDs() after the first argument = %q, want nothing", got)
+	}
+}
+
+func TestCompleteLineItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		wantPrefix string
+		wantNone   bool
+	}{
+		{name: "Brewing method", toComplete: "", wantPrefix: "espresso:\t"},
+		{name: "Grind type", toComplete: "espresso:", wantPrefix: "espresso:whole_bean:\t"},
+		{name: "Quantity is left to the user", toComplete: "espresso:whole_bean:", wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := completeLineItem(subscriptionsUpdateCmd, nil, tt.toComplete)
+			if tt.wantNone {
+				if len(got) != 0 {
+					t.Errorf("completeLineItem(%q) = %q, want nothing", tt.toComplete, got)
+				}
+				return
+			}
+			if !slices.ContainsFunc(got, func(c string) bool { return strings.HasPrefix(c, tt.wantPrefix) }) {
+				t.Errorf("completeLineItem(%q) = %q, want one starting with %q", tt.toComplete, got, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+// complete runs the hidden __complete command like a shell would and returns its output
+func complete(t *testing.T, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(io.Discard)
+	rootCmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	}()
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("__complete %v: %v", args, err)
+	}
+	return out.String()
+}
+
+func TestCompletePlans(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("is_subscription") == "true" {
+			_, _ = fmt.Fprint(w, `{"data":[
+				{"id":"s1","tier":"explorer","name":"Explorer","price":"20.00","currency":"USD","is_active":true},
+				{"id":"s2","tier":"summit","name":"Summit","price":"90.00","currency":"USD","is_active":false}]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"data":[
+			{"id":"p1","name":"Holiday blend","price":"15.00","currency":"USD","is_active":true}]}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+		skip string // a completion that must not be offered
+	}{
+		{
+			name: "Tiers",
+			args: []string{"order", "apply", "--tier", ""},
+			want: "explorer\tExplorer (20.00 USD)",
+			skip: "summit",
+		},
+		{
+			name: "Product IDs",
+			args: []string{"order", "apply", "--product-id", ""},
+			want: "p1\tHoliday blend (15.00 USD)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempHome(t)
+			t.Setenv("BASE_HOSTNAME", server.URL)
+
+			got := complete(t, tt.args...)
+			lines := strings.Split(got, "\n")
+			if !slices.Contains(lines, tt.want) {
+				t.Errorf("__complete %v = %q, want %q", tt.args, got, tt.want)
+			}
+			if tt.skip != "" && strings.Contains(got, tt.skip) {
+				t.Errorf("__complete %v = %q, want no %q", tt.args, got, tt.skip)
+			}
+			if !slices.Contains(lines, ":"+strconv.Itoa(int(cobra.ShellCompDirectiveNoFileComp))) {
+				t.Errorf("__complete %v = %q, want no file completion", tt.args, got)
+			}
+		})
+	}
+}
+
+func TestCompleteSectionIDsKeepsTheCacheInItsDirectory(t *testing.T) {
+	dir := useTempHome(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"data":{"count":1,"next":null,"results":[{"id":"s1","name":"Basics"}]}}`)
+	}))
+	defer server.Close()
+	t.Setenv("BASE_HOSTNAME", server.URL)
+
+	got, _ := completeSectionIDs(learnArticlesCmd, []string{"../../../escaped"}, "")
+	if !slices.Equal(got, []string{"s1\tBasics"}) {
+		t.Fatalf("completeSectionIDs() = %q, want the section", got)
+	}
+
+	err := filepath.WalkDir(os.Getenv("HOME"), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && filepath.Ext(path) == ".json" && filepath.Dir(path) != dir {
+			t.Errorf("section list cached in %s, want it in %s", path, dir)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,6 +10,10 @@ const (
 	// Subscription preferences
 	DefaultPreferenceQuantity = 2 // Default quantity for new preferences
 
+	// Product quantity limits when the backend doesn't set them
+	DefaultProductMinQuantity = 1
+	DefaultProductMaxQuantity = 10
+
 	// Token expiry safety margin
 	TokenExpirySafetyMarginSeconds = 30 // Consider token expired 30 seconds before actual expiration
 )
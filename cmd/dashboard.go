@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
+	"github.com/hassek/bc-cli/tui/models"
+	"github.com/hassek/bc-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+// dashboardRecentOrders is how many orders the dashboard lists
+const dashboardRecentOrders = 3
+
+// dashboardBookmarks is how many bookmarked articles the dashboard lists
+const dashboardBookmarks = 5
+
+// coffeeTips are shown one per day on the dashboard
+var coffeeTips = []string{
+	"Grind right before brewing. Ground coffee loses most of its aroma within 15 minutes.",
+	"Use water just off the boil, around 92-96°C, for most brewing methods.",
+	"A 1:16 coffee to water ratio is a good starting point for pour over.",
+	"Store beans in an airtight container away from light, heat and moisture.",
+	"Rinse paper filters with hot water first to remove any papery taste.",
+	"Let freshly roasted beans rest a few days before brewing so they can degas.",
+	"If your coffee tastes sour, grind finer. If it tastes bitter, grind coarser.",
+	"Weigh your coffee instead of using scoops for a consistent cup every time.",
+	"Bloom pour over coffee with a little water for 30 seconds before the main pour.",
+	"Filtered water makes a noticeable difference, coffee is over 98% water.",
+}
+
+// dashboardData is what the dashboard panes show. Each list has its own error so one
+// failing request doesn't hide the other panes.
+type dashboardData struct {
+	authenticated    bool
+	subscriptions    []api.Subscription
+	subscriptionsErr error
+	orders           []api.Order
+	ordersErr        error
+	bookmarks        []api.Bookmark
+	bookmarksErr     error
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	// The dashboard needs the full-screen interface, scripts get the usual help
+	if tui.IsPlain() || cmd.Flags().Changed("output") {
+		return cmd.Help()
+	}
+
+	return app.Run("Home", func() error {
+		for {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			client := api.NewClient(cfg)
+			data := loadDashboardData(cfg, client)
+
+			action, err := models.ShowDashboard(buildDashboardPanes(data, time.Now()), dashboardShortcuts())
+			if err != nil {
+				return err
+			}
+
+			flow := dashboardFlow(action)
+			if flow == nil {
+				return nil
+			}
+			flowErr := flow(cfg, client)
+
+			// A failing flow returns to the dashboard with the error shown above it
+			if errors.Is(flowErr, tui.ErrQuit) {
+				return flowErr
+			}
+			if flowErr != nil {
+				fmt.Fprintf(tui.Output(), "Error: %v\n", flowErr)
+			}
+		}
+	})
+}
+
+// dashboardFlow returns the flow a dashboard shortcut opens, nil for leaving the
+// dashboard. The flows are those of the commands, with their default options.
+func dashboardFlow(action string) func(cfg *config.Config, client *api.Client) error {
+	switch action {
+	case "manage":
+		return manageSubscriptions
+	case "subscriptions":
+		return func(cfg *config.Config, client *api.Client) error {
+			available, err := client.GetAvailableSubscriptions()
+			if err != nil {
+				return fmt.Errorf("failed to get available subscriptions: %w", err)
+			}
+			return browseSubscriptions(cfg, client, available)
+		}
+	case "products":
+		return func(cfg *config.Config, client *api.Client) error {
+			available, err := client.GetAvailableProducts()
+			if err != nil {
+				return fmt.Errorf("failed to get available products: %w", err)
+			}
+			return browseProducts(cfg, client, available)
+		}
+	case "learn":
+		return func(cfg *config.Config, client *api.Client) error {
+			kb, err := knowledgeBaseFor(client, false)
+			if err != nil {
+				return err
+			}
+			return browseKnowledgeBase(cfg, client, kb)
+		}
+	case "bookmarks":
+		return func(cfg *config.Config, client *api.Client) error {
+			return browseBookmarks(cfg, client, bookmarkFilter{})
+		}
+	}
+	return nil
+}
+
+// loadDashboardData fetches the subscriptions, orders and bookmarks concurrently. The
+// requests share client, which refreshes an expired token once for all of them.
+func loadDashboardData(cfg *config.Config, client *api.Client) dashboardData {
+	data := dashboardData{authenticated: cfg.IsAuthenticated()}
+	if !data.authenticated {
+		return data
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		data.subscriptions, data.subscriptionsErr = client.ListSubscriptions()
+	}()
+	go func() {
+		defer wg.Done()
+		data.orders, data.ordersErr = client.ListOrders()
+	}()
+	go func() {
+		defer wg.Done()
+		data.bookmarks, data.bookmarksErr = client.ListBookmarks()
+	}()
+	wg.Wait()
+
+	return data
+}
+
+func buildDashboardPanes(data dashboardData, now time.Time) []models.DashboardPane {
+	account := models.DashboardPane{Title: "Account"}
+	if data.authenticated {
+		account.Lines = []string{"✓ Logged in"}
+		if data.subscriptionsErr == nil {
+			active := 0
+			for _, sub := range data.subscriptions {
+				if sub.Status == "active" {
+					active++
+				}
+			}
+			account.Lines = append(account.Lines, fmt.Sprintf("%d active of %d subscriptions", active, len(data.subscriptions)))
+		}
+	} else {
+		account.Lines = []string{"Not logged in", "Run 'bc-cli login' to see your subscriptions, orders and bookmarks"}
+	}
+
+	return []models.DashboardPane{
+		account,
+		{Title: "Subscriptions", Lines: subscriptionLines(data)},
+		{Title: "Recent Orders", Lines: orderLines(data)},
+		{Title: "Bookmarks", Lines: bookmarkLines(data)},
+		{Title: "Tip of the Day", Lines: []string{tipOfTheDay(now)}},
+	}
+}
+
+func subscriptionLines(data dashboardData) []string {
+	switch {
+	case !data.authenticated:
+		return []string{"Login required"}
+	case data.subscriptionsErr != nil:
+		return []string{fmt.Sprintf("Could not load subscriptions: %v", data.subscriptionsErr)}
+	}
+
+	var lines []string
+	for _, sub := range data.subscriptions {
+		if sub.Status != "active" && sub.Status != "paused" {
+			continue
+		}
+		line := fmt.Sprintf("%s %s", getStatusIcon(sub.Status), sub.Tier)
+		if sub.Status == "active" && sub.StartedAt != nil {
+			if next := calculateNextShipment(*sub.StartedAt); next != "" {
+				line += fmt.Sprintf(" · next shipment %s", next)
+			}
+		} else if sub.Status == "paused" {
+			line += " · paused"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return []string{"No active subscriptions", "Press s to browse the tiers"}
+	}
+	return lines
+}
+
+func orderLines(data dashboardData) []string {
+	switch {
+	case !data.authenticated:
+		return []string{"Login required"}
+	case data.ordersErr != nil:
+		return []string{fmt.Sprintf("Could not load orders: %v", data.ordersErr)}
+	case len(data.orders) == 0:
+		return []string{"No orders yet"}
+	}
+
+	orders := make([]api.Order, len(data.orders))
+	copy(orders, data.orders)
+	sort.SliceStable(orders, func(i, j int) bool {
+		a, _ := utils.ParseTimestamp(orders[i].CreatedOn)
+		b, _ := utils.ParseTimestamp(orders[j].CreatedOn)
+		return a.After(b)
+	})
+
+	lines := make([]string, 0, dashboardRecentOrders)
+	for _, o := range orders[:min(len(orders), dashboardRecentOrders)] {
+		lines = append(lines, fmt.Sprintf("%s  %s × %d  %s", utils.FormatTimestamp(o.CreatedOn), o.Tier, o.TotalQuantity, o.Status))
+	}
+	return lines
+}
+
+func bookmarkLines(data dashboardData) []string {
+	switch {
+	case !data.authenticated:
+		return []string{"Login required"}
+	case data.bookmarksErr != nil:
+		return []string{fmt.Sprintf("Could not load bookmarks: %v", data.bookmarksErr)}
+	case len(data.bookmarks) == 0:
+		return []string{"No bookmarks yet", "Press l to find something to read"}
+	}
+
+	lines := make([]string, 0, dashboardBookmarks+1)
+	for _, bookmark := range data.bookmarks[:min(len(data.bookmarks), dashboardBookmarks)] {
+		lines = append(lines, "🔖 "+bookmark.Article.Title)
+	}
+	if more := len(data.bookmarks) - dashboardBookmarks; more > 0 {
+		lines = append(lines, fmt.Sprintf("and %d more", more))
+	}
+	return lines
+}
+
+// tipOfTheDay picks the tip for now's day, so it changes daily but not between runs
+func tipOfTheDay(now time.Time) string {
+	return coffeeTips[now.YearDay()%len(coffeeTips)]
+}
+
+func dashboardShortcuts() []models.DashboardShortcut {
+	return []models.DashboardShortcut{
+		{Key: "m", ActionItem: models.ActionItem{Action: "manage", Display: "Manage"}},
+		{Key: "s", ActionItem: models.ActionItem{Action: "subscriptions", Display: "Subscriptions"}},
+		{Key: "p", ActionItem: models.ActionItem{Action: "products", Display: "Products"}},
+		{Key: "l", ActionItem: models.ActionItem{Action: "learn", Display: "Learn"}},
+		{Key: "b", ActionItem: models.ActionItem{Action: "bookmarks", Display: "Bookmarks"}},
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+func TestOrderLines(t *testing.T) {
+	orders := []api.Order{
+		{Tier: "oldest", CreatedOn: "2025-01-01T10:00:00Z", TotalQuantity: 1, Status: "delivered"},
+		{Tier: "newest", CreatedOn: "2025-04-01T10:00:00Z", TotalQuantity: 2, Status: "pending"},
+		{Tier: "older", CreatedOn: "2025-02-01T10:00:00Z", TotalQuantity: 1, Status: "delivered"},
+		{Tier: "newer", CreatedOn: "2025-03-01T10:00:00Z", TotalQuantity: 3, Status: "shipped"},
+	}
+
+	tests := []struct {
+		name string
+		data dashboardData
+		want []string
+	}{
+		{
+			name: "Not logged in",
+			data: dashboardData{},
+			want: []string{"Login required"},
+		},
+		{
+			name: "Failed to load",
+			data: dashboardData{authenticated: true, ordersErr: errors.New("timeout")},
+			want: []string{"Could not load orders: timeout"},
+		},
+		{
+			name: "No orders",
+			data: dashboardData{authenticated: true},
+			want: []string{"No orders yet"},
+		},
+		{
+			name: "Most recent first",
+			data: dashboardData{authenticated: true, orders: orders},
+			want: []string{"newest × 2  pending", "newer × 3  shipped", "older × 1  delivered"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderLines(tt.data)
+			if len(got) != len(tt.want) {
+				t.Fatalf("orderLines() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if !strings.HasSuffix(got[i], tt.want[i]) {
+					t.Errorf("orderLines()[%d] = %q, want it to end with %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if orders[0].Tier != "oldest" {
+		t.Error("orderLines() sorted the orders it was given")
+	}
+}
+
+func TestTipOfTheDay(t *testing.T) {
+	day := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	if tipOfTheDay(day) != tipOfTheDay(day.Add(12*time.Hour)) {
+		t.Error("tipOfTheDay() changed within a day")
+	}
+	if tipOfTheDay(day) == tipOfTheDay(day.AddDate(0, 0, 1)) {
+		t.Error("tipOfTheDay() is the same on consecutive days")
+	}
+}
+
+func TestBuildDashboardPanes(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Not logged in", func(t *testing.T) {
+		panes := buildDashboardPanes(dashboardData{}, now)
+		titles := make([]string, len(panes))
+		for i, pane := range panes {
+			titles[i] = pane.Title
+		}
+		want := []string{"Account", "Subscriptions", "Recent Orders", "Bookmarks", "Tip of the Day"}
+		if !slices.Equal(titles, want) {
+			t.Fatalf("pane titles = %q, want %q", titles, want)
+		}
+		if panes[0].Lines[0] != "Not logged in" || panes[1].Lines[0] != "Login required" {
+			t.Errorf("panes = %+v, want them to ask for a login", panes)
+		}
+		if panes[4].Lines[0] != tipOfTheDay(now) {
+			t.Errorf("tip = %q, want today's", panes[4].Lines[0])
+		}
+	})
+
+	t.Run("Logged in", func(t *testing.T) {
+		bookmarks := make([]api.Bookmark, dashboardBookmarks+2)
+		for i := range bookmarks {
+			bookmarks[i].Article.Title = "Article"
+		}
+		data := dashboardData{
+			authenticated: true,
+			subscriptions: []api.Subscription{{Tier: "gold", Status: "active"}, {Tier: "silver", Status: "paused"}, {Tier: "bronze", Status: "cancelled"}},
+			bookmarks:     bookmarks,
+		}
+
+		panes := buildDashboardPanes(data, now)
+		if want := []string{"✓ Logged in", "1 active of 3 subscriptions"}; !slices.Equal(panes[0].Lines, want) {
+			t.Errorf("account = %q, want %q", panes[0].Lines, want)
+		}
+		if got := panes[1].Lines; len(got) != 2 || !strings.Contains(got[0], "gold") || !strings.HasSuffix(got[1], "silver · paused") {
+			t.Errorf("subscriptions = %q, want gold and the paused silver", got)
+		}
+		if got := panes[3].Lines; len(got) != dashboardBookmarks+1 || got[dashboardBookmarks] != "and 2 more" {
+			t.Errorf("bookmarks = %q, want %d and a count of the rest", got, dashboardBookmarks)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by bc-cli so scripts can tell failures apart
+const (
+	ExitCodeError            = 1
+	ExitCodeUsage            = 2
+	ExitCodeNotAuthenticated = 3
+	ExitCodeNotFound         = 4
+	ExitCodeCancelled        = 5
+	ExitCodeInvalidState     = 6
+)
+
+// ExitError attaches a process exit code to an error
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// withExitCode wraps err so the process exits with code
+func withExitCode(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// exitCodeFor maps an error returned by a command to a process exit code
+func exitCodeFor(err error) int {
+	var exitErr *ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	case api.IsNotFound(err):
+		return ExitCodeNotFound
+	case api.IsUnauthorized(err):
+		return ExitCodeNotAuthenticated
+	default:
+		return ExitCodeError
+	}
+}
+
+// usageArgs wraps a positional argument validator so its errors exit with ExitCodeUsage
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := validate(cmd, args); err != nil {
+			return withExitCode(ExitCodeUsage, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/history"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
+	"github.com/hassek/bc-cli/tui/models"
+	"github.com/hassek/bc-cli/tui/prompts"
+	"github.com/spf13/cobra"
+)
+
+// recentArticles is how many recently read articles 'learn recent' lists by default
+const recentArticles = 20
+
+var learnRecentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List the articles you read recently",
+	Long:  `List the articles you read recently, most recent first, with how much of each you read.`,
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runLearnRecent,
+}
+
+var learnContinueCmd = &cobra.Command{
+	Use:   "continue",
+	Short: "Continue reading the last article you opened",
+	Long:  `Reopen the last article you read where you left it.`,
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runLearnContinue,
+}
+
+func init() {
+	learnCmd.AddCommand(learnRecentCmd)
+	learnCmd.AddCommand(learnContinueCmd)
+
+	learnRecentCmd.Flags().IntP("limit", "n", recentArticles, "Number of articles to list, 0 for all")
+
+	models.SetReadingProgress(func(articleID string) (history.Entry, bool) {
+		return loadReadingHistory().Get(articleID)
+	})
+}
+
+// readingHistory is the reading history, loaded on first use
+var readingHistory *history.Store
+
+// loadReadingHistory returns the reading history, empty if it can't be read
+func loadReadingHistory() *history.Store {
+	if readingHistory != nil {
+		return readingHistory
+	}
+	readingHistory = history.NewStore()
+	if dir, err := config.GetHistoryDir(); err == nil {
+		if store, err := history.Load(dir); err == nil {
+			readingHistory = store
+		}
+	}
+	return readingHistory
+}
+
+// recordReading saves how far an article was read. It's best effort, the history only
+// being a convenience.
+func recordReading(progress history.Entry) {
+	progress.ReadAt = time.Now()
+	store := loadReadingHistory()
+	store.Record(progress)
+	if dir, err := config.GetHistoryDir(); err == nil {
+		_ = store.Save(dir)
+	}
+}
+
+func runLearnRecent(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("invalid limit %d", limit))
+	}
+
+	entries := loadReadingHistory().Recent(limit)
+	if format.IsStructured() {
+		items := make([]output.ReadingProgress, len(entries))
+		for i, entry := range entries {
+			items[i] = output.FromReadingProgress(entry)
+		}
+		return writeOutput(format, items, output.ReadingHistoryTable(items))
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(tui.Output(), "You haven't read any articles yet.")
+		fmt.Fprintln(tui.Output(), "Run 'bc-cli learn' to find something to read!")
+		return nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(cfg)
+	kb, err := openKnowledgeBase(cmd, client)
+	if err != nil {
+		return err
+	}
+	defer app.SetBadge("")
+
+	return app.Run("Recently read", func() error {
+		return browseRecent(cfg, client, kb, limit)
+	})
+}
+
+// browseRecent lets the user pick recently read articles and read them, until they go
+// back
+func browseRecent(cfg *config.Config, client *api.Client, kb knowledgeBase, limit int) error {
+	for {
+		// Reading moves articles up, so the list is made again each time
+		entries := loadReadingHistory().Recent(limit)
+		articles := make([]api.Article, len(entries))
+		for i, entry := range entries {
+			articles[i] = api.Article{ID: entry.ArticleID, Title: entry.Title}
+		}
+
+		picked, err := models.PickArticle(articles, nil, false, nil)
+		if err != nil || picked == nil {
+			return err
+		}
+
+		article, err := getArticle(kb, picked.ID)
+		if err != nil {
+			fmt.Fprintf(tui.Output(), "\nError loading article: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
+			continue
+		}
+		action, err := viewArticleWithActions(cfg, client, kb, article)
+		if err != nil {
+			return err
+		}
+		if action == models.ArticleActionQuit {
+			return ErrUserQuit
+		}
+	}
+}
+
+func runLearnContinue(cmd *cobra.Command, args []string) error {
+	last := loadReadingHistory().Recent(1)
+	if len(last) == 0 {
+		return withExitCode(ExitCodeNotFound, errors.New("nothing to continue, read an article with 'bc-cli learn' first"))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := api.NewClient(cfg)
+	kb, err := openKnowledgeBase(cmd, client)
+	if err != nil {
+		return err
+	}
+	defer app.SetBadge("")
+
+	article, err := getArticle(kb, last[0].ArticleID)
+	if err != nil {
+		return err
+	}
+
+	return app.Run("Learn", func() error {
+		_, err := viewArticleWithActions(cfg, client, kb, article)
+		return err
+	})
+}
@@ -1,37 +1,80 @@
 package cmd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"os"
 
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/offline"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
 	"github.com/hassek/bc-cli/tui/models"
+	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/spf13/cobra"
 )
 
-// ErrUserQuit is returned when user presses Ctrl+C to exit
-var ErrUserQuit = errors.New("user quit")
+// ErrUserQuit is returned when user presses Ctrl+C to exit. It is the app shell's
+// quit error so quitting the shell unwinds the navigation loops the same way.
+var ErrUserQuit = tui.ErrQuit
 
 var learnCmd = &cobra.Command{
 	Use:   "learn",
 	Short: "Explore coffee knowledge and articles",
-	Long:  `Browse categories, sections, and articles about coffee. Save your favorites with bookmarks.`,
-	RunE:  runLearn,
+	Long: `Browse categories, sections, and articles about coffee, or search them. Save your favorites with bookmarks.
+
+The reader remembers how far you read each article. Pick up the last one with
+'bc-cli learn continue', or the ones before it with 'bc-cli learn recent'.
+
+Run 'bc-cli learn sync' to download the articles; they are read from that copy when the
+server can't be reached, or with --offline.`,
+	RunE: runLearn,
 }
 
 var learnBookmarksCmd = &cobra.Command{
 	Use:   "bookmarks",
 	Short: "View your bookmarked articles",
-	Long:  `Display all articles you've saved to your bookmarks. Requires authentication.`,
-	RunE:  runLearnBookmarks,
+	Long: `Display all articles you've saved to your bookmarks, or only the ones in a collection
+with --collection. Organize them with 'note' and 'move', and share them with 'export'.
+Requires authentication.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runLearnBookmarks,
+}
+
+var learnCategoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "List the knowledge base categories",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runLearnCategories,
+}
+
+var learnArticlesCmd = &cobra.Command{
+	Use:   "articles <category-slug>",
+	Short: "List the articles in a category",
+	Long:  `List the articles in a category. Use --section to list the articles of one of its sections instead.`,
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runLearnArticles,
+}
+
+var learnReadCmd = &cobra.Command{
+	Use:   "read <article-id>",
+	Short: "Read an article",
+	Long:  `Open an article directly in the reader. Use 'bc-cli learn articles' to find article IDs.`,
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runLearnRead,
 }
 
 func init() {
 	rootCmd.AddCommand(learnCmd)
 	learnCmd.AddCommand(learnBookmarksCmd)
+	learnCmd.AddCommand(learnCategoriesCmd)
+	learnCmd.AddCommand(learnArticlesCmd)
+	learnCmd.AddCommand(learnReadCmd)
+
+	learnArticlesCmd.Flags().String("section", "", "Section ID to list articles from")
+	_ = learnArticlesCmd.RegisterFlagCompletionFunc("section", completeSectionIDs)
 }
 
 func runLearn(cmd *cobra.Command, args []string) error {
@@ -42,21 +85,56 @@ func runLearn(cmd *cobra.Command, args []string) error {
 
 	client := api.NewClient(cfg)
 
-	// Main navigation loop
+	kb, err := openKnowledgeBase(cmd, client)
+	if err != nil {
+		return err
+	}
+	return browseKnowledgeBase(cfg, client, kb)
+}
+
+// browseKnowledgeBase lets the user browse the knowledge base read from kb, until they
+// go back
+func browseKnowledgeBase(cfg *config.Config, client *api.Client, kb knowledgeBase) error {
+	defer app.SetBadge("")
+
+	return app.Run("Learn", func() error {
+		return browseCategories(cfg, client, kb)
+	})
+}
+
+// browseCategories is the main navigation loop of the knowledge base. Articles are read
+// from kb, the client is used for bookmarks.
+func browseCategories(cfg *config.Config, client *api.Client, kb knowledgeBase) error {
 	for {
 		// Fetch categories
-		categories, err := client.ListCategories()
+		categories, err := kb.ListCategories()
 		if err != nil {
 			return fmt.Errorf("failed to fetch categories: %w", err)
 		}
 
 		if len(categories) == 0 {
-			fmt.Println("No content available at this time.")
+			fmt.Fprintln(tui.Output(), "No content available at this time.")
 			return nil
 		}
 
 		// Show category picker
 		category, err := models.PickCategory(categories)
+		if errors.Is(err, models.ErrSearchSelected) {
+			err = app.Run("Search", func() error {
+				if err := ensureSearchIndex(kb, false); err != nil {
+					return err
+				}
+				return searchArticles(cfg, client, kb, "")
+			})
+			if errors.Is(err, ErrUserQuit) {
+				return nil
+			}
+			if err != nil {
+				fmt.Fprintf(tui.Output(), "\nError: %v\n", err)
+				prompts.WaitForEnter("Press Enter to continue...")
+			}
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -67,19 +145,141 @@ func runLearn(cmd *cobra.Command, args []string) error {
 		}
 
 		// Navigate into category
-		if err := navigateCategory(cfg, client, category); err != nil {
+		err = app.Run(category.Name, func() error {
+			return navigateCategory(cfg, client, kb, category)
+		})
+		if err != nil {
 			if errors.Is(err, ErrUserQuit) {
 				return nil // Exit cleanly
 			}
-			fmt.Printf("\nError: %v\n", err)
-			fmt.Print("Press Enter to continue...")
-			_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			fmt.Fprintf(tui.Output(), "\nError: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
 			continue
 		}
 	}
 }
 
 func runLearnBookmarks(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	filter, err := bookmarkFilterFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := api.NewClient(cfg)
+
+	if !format.IsStructured() {
+		return browseBookmarks(cfg, client, filter)
+	}
+
+	if !cfg.IsAuthenticated() {
+		return withExitCode(ExitCodeNotAuthenticated, errors.New("not logged in, please run: bc-cli login"))
+	}
+
+	bookmarks, _, _, err := loadBookmarks(client)
+	if err != nil {
+		return err
+	}
+	bookmarks = filter.apply(bookmarks)
+
+	items := make([]output.Bookmark, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		items[i] = output.FromBookmark(bookmark)
+	}
+	return writeOutput(format, items, output.BookmarksTable(items))
+}
+
+func runLearnCategories(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	kb, err := openKnowledgeBase(cmd, api.NewClient(cfg))
+	if err != nil {
+		return err
+	}
+
+	categories, err := kb.ListCategories()
+	if err != nil {
+		return fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	if format.IsStructured() {
+		items := make([]output.Category, len(categories))
+		for i, category := range categories {
+			items[i] = output.FromCategory(category)
+		}
+		return writeOutput(format, items, output.CategoriesTable(items))
+	}
+
+	return templates.RenderToStdout(templates.CategoryListTemplate, struct {
+		Categories []api.Category
+	}{
+		Categories: categories,
+	})
+}
+
+func runLearnArticles(cmd *cobra.Command, args []string) error {
+	sectionID, _ := cmd.Flags().GetString("section")
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	kb, err := openKnowledgeBase(cmd, api.NewClient(cfg))
+	if err != nil {
+		return err
+	}
+
+	var articles []api.Article
+	if sectionID != "" {
+		articles, err = kb.ListSectionArticles(sectionID)
+	} else {
+		articles, err = kb.ListCategoryArticles(args[0])
+	}
+	if err != nil {
+		if api.IsNotFound(err) {
+			return withExitCode(ExitCodeNotFound, fmt.Errorf("category %s not found", args[0]))
+		}
+		return fmt.Errorf("failed to fetch articles: %w", err)
+	}
+
+	if format.IsStructured() {
+		items := make([]output.Article, len(articles))
+		for i, article := range articles {
+			items[i] = output.FromArticle(article)
+		}
+		return writeOutput(format, items, output.ArticlesTable(items))
+	}
+
+	return templates.RenderToStdout(templates.ArticleListTemplate, struct {
+		Articles []api.Article
+	}{
+		Articles: articles,
+	})
+}
+
+func runLearnRead(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -87,33 +287,60 @@ func runLearnBookmarks(cmd *cobra.Command, args []string) error {
 
 	client := api.NewClient(cfg)
 
-	return showBookmarksView(cfg, client)
+	kb, err := openKnowledgeBase(cmd, client)
+	if err != nil {
+		return err
+	}
+	defer app.SetBadge("")
+
+	article, err := getArticle(kb, args[0])
+	if err != nil {
+		return err
+	}
+
+	return app.Run("Learn", func() error {
+		_, err := viewArticleWithActions(cfg, client, kb, article)
+		return err
+	})
+}
+
+// getArticle returns an article with its content, failing with ExitCodeNotFound if
+// there's no such article
+func getArticle(kb knowledgeBase, articleID string) (*api.Article, error) {
+	article, err := kb.GetArticle(articleID)
+	if err != nil {
+		if api.IsNotFound(err) || errors.Is(err, offline.ErrNotSynced) {
+			return nil, withExitCode(ExitCodeNotFound, fmt.Errorf("article %s not found", articleID))
+		}
+		return nil, fmt.Errorf("failed to load article: %w", err)
+	}
+	return article, nil
 }
 
-func navigateCategory(cfg *config.Config, client *api.Client, category *api.Category) error {
+func navigateCategory(cfg *config.Config, client *api.Client, kb knowledgeBase, category *api.Category) error {
 	// Smart detection: check if category has sections
-	hasSections, err := client.CategoryHasSections(category.Slug)
+	hasSections, err := kb.CategoryHasSections(category.Slug)
 	if err != nil {
 		return err
 	}
 
 	if hasSections {
 		// Show sections first
-		return navigateSections(cfg, client, category)
+		return navigateSections(cfg, client, kb, category)
 	}
 	// Show articles directly
-	return navigateArticles(cfg, client, category.Slug, nil)
+	return navigateArticles(cfg, client, kb, category.Slug, nil)
 }
 
-func navigateSections(cfg *config.Config, client *api.Client, category *api.Category) error {
+func navigateSections(cfg *config.Config, client *api.Client, kb knowledgeBase, category *api.Category) error {
 	for {
-		sections, err := client.ListCategorySections(category.Slug)
+		sections, err := kb.ListCategorySections(category.Slug)
 		if err != nil {
 			return err
 		}
 
 		if len(sections) == 0 {
-			fmt.Println("No sections available in this category.")
+			fmt.Fprintln(tui.Output(), "No sections available in this category.")
 			return nil
 		}
 
@@ -123,60 +350,69 @@ func navigateSections(cfg *config.Config, client *api.Client, category *api.Cate
 		}
 
 		// Navigate into section's articles
-		if err := navigateArticles(cfg, client, category.Slug, &section.ID); err != nil {
+		err = app.Run(section.Name, func() error {
+			return navigateArticles(cfg, client, kb, category.Slug, &section.ID)
+		})
+		if err != nil {
 			if errors.Is(err, ErrUserQuit) {
 				return err // Propagate quit signal
 			}
-			fmt.Printf("\nError: %v\n", err)
-			fmt.Print("Press Enter to continue...")
-			_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			fmt.Fprintf(tui.Output(), "\nError: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
 			continue
 		}
 	}
 }
 
-func navigateArticles(cfg *config.Config, client *api.Client, categorySlug string, sectionID *string) error {
+func navigateArticles(cfg *config.Config, client *api.Client, kb knowledgeBase, categorySlug string, sectionID *string) error {
 	for {
-		var articles []api.Article
-		var err error
-
-		if sectionID == nil {
-			// Category's default articles
-			articles, err = client.ListCategoryArticles(categorySlug)
-		} else {
-			// Section's articles
-			articles, err = client.ListSectionArticles(*sectionID)
-		}
-
+		// The first page of articles, the picker loads the others as the user scrolls
+		articles, more, err := firstArticles(kb, categorySlug, sectionID)
 		if err != nil {
 			return err
 		}
 
 		if len(articles) == 0 {
-			fmt.Println("No articles available in this section.")
+			fmt.Fprintln(tui.Output(), "No articles available in this section.")
 			return nil
 		}
 
-		article, err := models.PickArticle(articles)
+		highlight, stopPrefetch := startPrefetch(kb, articles)
+		article, err := models.PickArticle(articles, more, canBookmark(cfg, kb) && (len(articles) > 1 || more != nil), highlight)
+		stopPrefetch()
+		if errors.Is(err, models.ErrBatchSelected) {
+			// Bookmarking several articles offers all of them
+			if more != nil {
+				if articles, err = listArticles(kb, categorySlug, sectionID); err != nil {
+					return err
+				}
+			}
+			bookmarked := make([]bool, len(articles))
+			for i, a := range articles {
+				bookmarked[i] = a.IsBookmarked
+			}
+			if err := bookmarkSeveral(client, articles, bookmarked); err != nil {
+				return err
+			}
+			continue
+		}
 		if err != nil || article == nil {
 			return err // Back or exit
 		}
 
 		// Fetch full article with content
-		fullArticle, err := client.GetArticle(article.ID)
+		fullArticle, err := kb.GetArticle(article.ID)
 		if err != nil {
-			fmt.Printf("\nError loading article: %v\n", err)
-			fmt.Print("Press Enter to continue...")
-			_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			fmt.Fprintf(tui.Output(), "\nError loading article: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
 			continue
 		}
 
 		// View article with actions
-		action, err := viewArticleWithActions(cfg, client, fullArticle)
+		action, err := viewArticleWithActions(cfg, client, kb, fullArticle)
 		if err != nil {
-			fmt.Printf("\nError: %v\n", err)
-			fmt.Print("Press Enter to continue...")
-			_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			fmt.Fprintf(tui.Output(), "\nError: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
 			continue
 		}
 
@@ -192,103 +428,255 @@ func navigateArticles(cfg *config.Config, client *api.Client, categorySlug strin
 	}
 }
 
-func viewArticleWithActions(cfg *config.Config, client *api.Client, article *api.Article) (models.ArticleAction, error) {
-	canBookmark := cfg.IsAuthenticated()
+// Articles are prefetched while the user browses a list of them
+const (
+	prefetchWorkers = 2 // concurrent prefetch requests
+	prefetchAhead   = 3 // articles prefetched after the highlighted one
+)
 
-	for {
-		action, err := models.ViewArticle(article, canBookmark)
-		if err != nil {
-			return models.ArticleActionNone, err
-		}
-
-		switch action {
-		case models.ArticleActionToggleBookmark:
-			// Handle bookmark toggle
-			if err := toggleBookmark(client, article); err != nil {
-				fmt.Printf("\nError toggling bookmark: %v\n", err)
-				fmt.Print("Press Enter to continue...")
-				_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
-			} else {
-				// Refresh article to get updated bookmark status
-				updated, err := client.GetArticle(article.ID)
-				if err == nil {
-					article = updated
-					if article.IsBookmarked {
-						fmt.Println("\n✓ Article bookmarked!")
-					} else {
-						fmt.Println("\n✓ Bookmark removed")
-					}
-					fmt.Print("Press Enter to continue reading...")
-					_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
-				}
-			}
-			// Continue viewing article
-			continue
+// startPrefetch prefetches the highlighted article of a list, and the few after it,
+// into the client's cache so opening them is instant. It returns the highlight callback
+// for the article picker, starting from the first article, and a func to stop
+// prefetching. Without a cache to prefetch into, or offline, the callback is nil.
+func startPrefetch(kb knowledgeBase, articles []api.Article) (func(next []api.Article), func()) {
+	client, ok := kb.(*api.Client)
+	if !ok || client.Cache == nil || len(articles) == 0 {
+		return nil, func() {}
+	}
 
-		case models.ArticleActionShowRelated:
-			return action, nil
+	prefetcher := api.NewPrefetcher(client, prefetchWorkers)
+	highlight := func(next []api.Article) {
+		next = next[:min(1+prefetchAhead, len(next))]
+		ids := make([]string, len(next))
+		for i, article := range next {
+			ids[i] = article.ID
+		}
+		prefetcher.Prefetch(ids...)
+	}
+	highlight(articles)
+	return highlight, prefetcher.Stop
+}
+
+// listArticles returns the articles of a category's default section, or of one of its
+// sections
+func listArticles(kb knowledgeBase, categorySlug string, sectionID *string) ([]api.Article, error) {
+	if sectionID == nil {
+		return kb.ListCategoryArticles(categorySlug)
+	}
+	return kb.ListSectionArticles(*sectionID)
+}
+
+// firstArticles returns the first page of the articles listArticles returns, and a func
+// loading the following pages, nil if there are none. The offline copy has them all.
+func firstArticles(kb knowledgeBase, categorySlug string, sectionID *string) ([]api.Article, models.MoreArticles, error) {
+	client, ok := kb.(*api.Client)
+	if !ok {
+		articles, err := listArticles(kb, categorySlug, sectionID)
+		return articles, nil, err
+	}
+
+	pages := client.CategoryArticles(categorySlug)
+	if sectionID != nil {
+		pages = client.SectionArticles(*sectionID)
+	}
+	articles, err := pages.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	return articles, nextPages(pages), nil
+}
 
-		default:
-			// Back/quit
-			return action, nil
+// nextPages returns a func loading the pages left in pages one at a time, for a picker
+// loading its items lazily, or nil if there are none left
+func nextPages[T any](pages *api.Paginator[T]) func() ([]T, bool, error) {
+	if !pages.More() {
+		return nil
+	}
+	return func() ([]T, bool, error) {
+		items, err := pages.Next()
+		return items, pages.More(), err
+	}
+}
+
+// canBookmark reports whether articles read from kb can be bookmarked, which needs a
+// login and a connection
+func canBookmark(cfg *config.Config, kb knowledgeBase) bool {
+	return cfg.IsAuthenticated() && !readingOffline(kb)
+}
+
+// viewArticleWithActions shows an article until the user leaves it, letting them
+// bookmark it if they can; article is updated with its bookmark state. It opens where
+// the user left it last time, and records how far they read.
+func viewArticleWithActions(cfg *config.Config, client *api.Client, kb knowledgeBase, article *api.Article) (models.ArticleAction, error) {
+	var toggle models.ToggleBookmark
+	if canBookmark(cfg, kb) {
+		toggle = func(article api.Article) (api.Article, error) {
+			return toggleBookmark(client, article)
 		}
 	}
+
+	progress, _ := loadReadingHistory().Get(article.ID)
+	progress.ArticleID, progress.Title = article.ID, article.Title
+	action, err := models.ViewArticle(article, toggle, &progress)
+	recordReading(progress)
+	return action, err
 }
 
-func toggleBookmark(client *api.Client, article *api.Article) error {
+// toggleBookmark bookmarks the article, or removes its bookmark, and returns it with
+// its new bookmark state
+func toggleBookmark(client *api.Client, article api.Article) (api.Article, error) {
 	// Check if user is authenticated
 	if !client.Config.IsAuthenticated() {
-		return fmt.Errorf("you need to login to bookmark articles. Run 'bc-cli login' to authenticate")
+		return article, fmt.Errorf("you need to login to bookmark articles. Run 'bc-cli login' to authenticate")
 	}
 
 	if article.IsBookmarked {
-		// Need to find bookmark ID to delete
-		bookmarks, err := client.ListBookmarks()
-		if err != nil {
-			return fmt.Errorf("failed to fetch bookmarks: %w", err)
+		if err := client.DeleteArticleBookmark(&article); err != nil {
+			return article, err
 		}
+		article.IsBookmarked, article.BookmarkID = false, ""
+		return article, nil
+	}
+
+	bookmark, err := client.CreateBookmark(article.ID)
+	if err != nil {
+		return article, err
+	}
+	article.IsBookmarked, article.BookmarkID = true, bookmark.ID
+	return article, nil
+}
+
+// bookmarkSeveral lets the user check the articles to keep bookmarked, then bookmarks
+// the newly checked ones and removes the bookmarks of the unchecked ones. bookmarked
+// marks the articles bookmarked so far.
+func bookmarkSeveral(client *api.Client, articles []api.Article, bookmarked []bool) error {
+	picked, err := models.PickArticles("Check the articles to keep bookmarked", articles, bookmarked)
+	if errors.Is(err, prompts.ErrUserCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	want := make(map[string]bool, len(picked))
+	for _, article := range picked {
+		want[article.ID] = true
+	}
+
+	// Bookmark IDs the articles don't come with are looked up on first use
+	var bookmarkIDs map[string]string
+	added, removed, failed := 0, 0, 0
+	for i, article := range articles {
+		switch {
+		case want[article.ID] && !bookmarked[i]:
+			fmt.Fprintf(tui.Output(), "Bookmarking %s... ", article.Title)
+			if _, err := client.CreateBookmark(article.ID); err != nil {
+				fmt.Fprintf(tui.Output(), "✗ %v\n", err)
+				failed++
+				continue
+			}
+			fmt.Fprintln(tui.Output(), "✓")
+			added++
+
+		case !want[article.ID] && bookmarked[i]:
+			if article.BookmarkID == "" && bookmarkIDs == nil {
+				bookmarks, err := client.ListBookmarks()
+				if err != nil {
+					return fmt.Errorf("failed to fetch bookmarks: %w", err)
+				}
+				bookmarkIDs = make(map[string]string, len(bookmarks))
+				for _, bm := range bookmarks {
+					bookmarkIDs[bm.Article.ID] = bm.ID
+				}
+			}
 
-		for _, bm := range bookmarks {
-			// Use the nested Article.ID since article_id might not be populated at top level
-			if bm.Article.ID == article.ID {
-				return client.DeleteBookmark(bm.ID)
+			fmt.Fprintf(tui.Output(), "Removing bookmark for %s... ", article.Title)
+			id, ok := article.BookmarkID, true
+			if id == "" {
+				id, ok = bookmarkIDs[article.ID]
+			}
+			if !ok {
+				fmt.Fprintln(tui.Output(), "✗ bookmark not found")
+				failed++
+				continue
 			}
+			if err := client.DeleteBookmark(id); err != nil {
+				fmt.Fprintf(tui.Output(), "✗ %v\n", err)
+				failed++
+				continue
+			}
+			fmt.Fprintln(tui.Output(), "✓")
+			removed++
 		}
-		return fmt.Errorf("bookmark not found for article %s (searched %d bookmarks)", article.ID, len(bookmarks))
 	}
-	_, err := client.CreateBookmark(article.ID)
-	return err
+
+	if added+removed+failed == 0 {
+		fmt.Fprintln(tui.Output(), "No bookmarks changed.")
+		return nil
+	}
+	fmt.Fprintf(tui.Output(), "\n✓ %d bookmarked, %d removed", added, removed)
+	if failed > 0 {
+		fmt.Fprintf(tui.Output(), ", %d failed", failed)
+	}
+	fmt.Fprintln(tui.Output())
+	return nil
 }
 
-func showBookmarksView(cfg *config.Config, client *api.Client) error {
+// browseBookmarks lets the user read their bookmarked articles, the ones filter lists,
+// until they go back
+func browseBookmarks(cfg *config.Config, client *api.Client, filter bookmarkFilter) error {
 	if !cfg.IsAuthenticated() {
-		fmt.Println("\nPlease login to view bookmarks.")
-		fmt.Println("Run 'bc-cli login' to authenticate.")
+		fmt.Fprintln(tui.Output(), "\nPlease login to view bookmarks.")
+		fmt.Fprintln(tui.Output(), "Run 'bc-cli login' to authenticate.")
 		return nil
 	}
+	return app.Run("Bookmarks", func() error {
+		return showBookmarksView(cfg, client, filter)
+	})
+}
 
-	bookmarks, err := client.ListBookmarks()
+func showBookmarksView(cfg *config.Config, client *api.Client, filter bookmarkFilter) error {
+	// The first page of bookmarks, the picker loads the others as the user scrolls
+	articles, more, err := firstBookmarks(client, filter)
 	if err != nil {
 		return err
 	}
 
-	if len(bookmarks) == 0 {
-		fmt.Println("\nYou don't have any bookmarks yet.")
-		fmt.Println("Press 'b' while reading an article to bookmark it!")
-		fmt.Print("\nPress Enter to continue...")
-		_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+	if len(articles) == 0 && filter.collection != "" {
+		fmt.Fprintf(tui.Output(), "\nYou don't have any bookmarks in %q.\n", filter.collection)
+		prompts.WaitForEnter("\nPress Enter to continue...")
 		return nil
 	}
-
-	// Extract articles from bookmarks
-	articles := make([]api.Article, len(bookmarks))
-	for i, bm := range bookmarks {
-		articles[i] = bm.Article
+	if len(articles) == 0 {
+		fmt.Fprintln(tui.Output(), "\nYou don't have any bookmarks yet.")
+		fmt.Fprintln(tui.Output(), "Press 'b' while reading an article to bookmark it!")
+		prompts.WaitForEnter("\nPress Enter to continue...")
+		return nil
 	}
 
 	// Use article picker
 	for {
-		article, err := models.PickArticle(articles)
+		highlight, stopPrefetch := startPrefetch(client, articles)
+		article, err := models.PickArticle(articles, more, len(articles) > 1 || more != nil, highlight)
+		stopPrefetch()
+		if errors.Is(err, models.ErrBatchSelected) {
+			// Bookmarking several articles offers all of them, the ones filter lists
+			if more != nil {
+				articles, err = filteredBookmarks(client, filter)
+				if err != nil {
+					return err
+				}
+			}
+			bookmarked := make([]bool, len(articles))
+			for i := range bookmarked {
+				bookmarked[i] = true
+			}
+			if err := bookmarkSeveral(client, articles, bookmarked); err != nil {
+				return err
+			}
+			// Start over with the bookmarks that are left
+			return showBookmarksView(cfg, client, filter)
+		}
 		if err != nil || article == nil {
 			return err
 		}
@@ -296,37 +684,71 @@ func showBookmarksView(cfg *config.Config, client *api.Client) error {
 		// View article
 		fullArticle, err := client.GetArticle(article.ID)
 		if err != nil {
-			fmt.Printf("\nError loading article: %v\n", err)
-			fmt.Print("Press Enter to continue...")
-			_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			fmt.Fprintf(tui.Output(), "\nError loading article: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
 			continue
 		}
 
-		action, err := viewArticleWithActions(cfg, client, fullArticle)
-		if err != nil {
+		if _, err := viewArticleWithActions(cfg, client, client, fullArticle); err != nil {
 			return err
 		}
 
 		// If user removed bookmark, refresh the bookmarks list
-		if action == models.ArticleActionToggleBookmark && !fullArticle.IsBookmarked {
+		if !fullArticle.IsBookmarked {
 			// Refresh bookmarks list
-			bookmarks, err = client.ListBookmarks()
+			articles, more, err = firstBookmarks(client, filter)
 			if err != nil {
 				return err
 			}
 
-			if len(bookmarks) == 0 {
-				fmt.Println("\nYou don't have any bookmarks left.")
-				fmt.Print("Press Enter to continue...")
-				_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			if len(articles) == 0 {
+				fmt.Fprintln(tui.Output(), "\nYou don't have any bookmarks left.")
+				prompts.WaitForEnter("Press Enter to continue...")
 				return nil
 			}
+		}
+	}
+}
 
-			// Update articles list
-			articles = make([]api.Article, len(bookmarks))
-			for i, bm := range bookmarks {
-				articles[i] = bm.Article
-			}
+// firstBookmarks returns the articles of the first page of bookmarks, and a func loading
+// the following pages, nil if there are none. Filtering or sorting them needs them all.
+func firstBookmarks(client *api.Client, filter bookmarkFilter) ([]api.Article, models.MoreArticles, error) {
+	if filter.isSet() {
+		articles, err := filteredBookmarks(client, filter)
+		return articles, nil, err
+	}
+
+	pages := client.Bookmarks()
+	bookmarks, err := pages.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var more models.MoreArticles
+	if load := nextPages(pages); load != nil {
+		more = func() ([]api.Article, bool, error) {
+			bookmarks, hasMore, err := load()
+			return bookmarkedArticles(bookmarks), hasMore, err
 		}
 	}
+	return bookmarkedArticles(bookmarks), more, nil
+}
+
+// filteredBookmarks returns the articles of every bookmark filter lists, in its order
+func filteredBookmarks(client *api.Client, filter bookmarkFilter) ([]api.Article, error) {
+	bookmarks, _, _, err := loadBookmarks(client)
+	if err != nil {
+		return nil, err
+	}
+	return bookmarkedArticles(filter.apply(bookmarks)), nil
+}
+
+// bookmarkedArticles extracts the articles from bookmarks, with their bookmark IDs
+func bookmarkedArticles(bookmarks []api.Bookmark) []api.Article {
+	articles := make([]api.Article, len(bookmarks))
+	for i, bm := range bookmarks {
+		articles[i] = bm.Article
+		articles[i].IsBookmarked, articles[i].BookmarkID = true, bm.ID
+	}
+	return articles
 }
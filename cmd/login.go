@@ -1,17 +1,14 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strings"
-	"syscall"
 
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/config"
 	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 )
 
 var loginCmd = &cobra.Command{
@@ -35,31 +32,23 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		if err := templates.RenderToStdout(templates.AlreadyLoggedInTemplate, nil); err != nil {
 			return fmt.Errorf("failed to render template: %w", err)
 		}
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.ToLower(strings.TrimSpace(response))
+		response, _ := prompts.ReadLine("")
+		response = strings.ToLower(response)
 		// Default to yes when user presses Enter
 		if response == "n" || response == "no" {
 			return nil
 		}
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Print("Username: ")
-	username, err := reader.ReadString('\n')
+	username, err := prompts.ReadLine("Username: ")
 	if err != nil {
 		return fmt.Errorf("failed to read username: %w", err)
 	}
-	username = strings.TrimSpace(username)
 
-	fmt.Print("Password: ")
-	passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
+	password, err := prompts.ReadPassword("Password: ")
 	if err != nil {
 		return fmt.Errorf("failed to read password: %w", err)
 	}
-	password := string(passwordBytes)
-	fmt.Println()
 
 	client := api.NewClient(cfg)
 
@@ -74,6 +63,9 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("login failed: %w", err)
 	}
 
+	// Drop anything cached for a previous account
+	_ = config.ClearCache()
+
 	if err := templates.RenderToStdout(templates.LoginSuccessTemplate, struct{ Username string }{Username: username}); err != nil {
 		return fmt.Errorf("failed to render template: %w", err)
 	}
@@ -37,11 +37,15 @@ func runLogout(cmd *cobra.Command, args []string) error {
 	cfg.RefreshToken = ""
 	cfg.ExpiresAt = ""
 	cfg.RefreshTokenExpiresAt = ""
+	cfg.UserID = ""
 
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
+	// Cached data belongs to the account that just logged out
+	_ = config.ClearCache()
+
 	if err := templates.RenderToStdout(templates.LogoutSuccessTemplate, nil); err != nil {
 		return fmt.Errorf("failed to render template: %w", err)
 	}
@@ -1,15 +1,16 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/cmd/order"
 	"github.com/hassek/bc-cli/config"
 	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
 	"github.com/hassek/bc-cli/tui/models"
 	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/hassek/bc-cli/utils"
@@ -23,8 +24,36 @@ var manageCmd = &cobra.Command{
 	RunE:  runManage,
 }
 
+var manageUpdateCmd = &cobra.Command{
+	Use:   "update <subscription-id> -f <file>",
+	Short: "Update subscription preferences from a YAML or JSON file",
+	Long: `Update a subscription's quantity and preferences from a YAML or JSON file.
+
+A diff between the current and proposed preferences, including quantity and
+price changes, is shown before anything is sent. Use --dry-run to only show
+the diff. Use "-f -" to read the file from stdin.
+
+Example prefs.yaml:
+
+  total_quantity: 4       # optional, defaults to the sum of the line items
+  line_items:
+    - quantity: 3
+      grind_type: ground
+      brewing_method: espresso
+    - quantity: 1
+      grind_type: whole_bean
+      brewing_method: v60`,
+	Args: cobra.ExactArgs(1),
+	RunE: runManageUpdate,
+}
+
 func init() {
 	rootCmd.AddCommand(manageCmd)
+	manageCmd.AddCommand(manageUpdateCmd)
+
+	manageUpdateCmd.Flags().StringP("file", "f", "", "Preferences file (YAML or JSON, \"-\" for stdin)")
+	manageUpdateCmd.Flags().Bool("dry-run", false, "Show the changes without updating the subscription")
+	_ = manageUpdateCmd.MarkFlagRequired("file")
 }
 
 func runManage(cmd *cobra.Command, args []string) error {
@@ -32,7 +61,12 @@ func runManage(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	return manageSubscriptions(cfg, api.NewClient(cfg))
+}
 
+// manageSubscriptions lets the user pick their subscriptions and manage them, until
+// they go back
+func manageSubscriptions(cfg *config.Config, client *api.Client) error {
 	if !cfg.IsAuthenticated() {
 		if err := templates.RenderToStdout(templates.ManageNotAuthenticatedTemplate, nil); err != nil {
 			return err
@@ -40,8 +74,6 @@ func runManage(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	client := api.NewClient(cfg)
-
 	subscriptions, err := client.ListSubscriptions()
 	if err != nil {
 		return fmt.Errorf("failed to get subscriptions: %w", err)
@@ -54,24 +86,107 @@ func runManage(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	subscription, err := selectSubscriptionToManage(subscriptions)
+	return app.Run("Manage", func() error {
+		for {
+			subscription, err := selectSubscriptionToManage(subscriptions)
+			if errors.Is(err, models.ErrBatchSelected) {
+				if err := pauseResumeSeveral(client, subscriptions); err != nil {
+					return err
+				}
+				// Statuses changed, so the picker starts over with fresh subscriptions
+				if refreshed, err := client.ListSubscriptions(); err == nil {
+					subscriptions = refreshed
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if subscription == nil {
+				return nil
+			}
+
+			// Fetch full subscription details including order configuration
+			fullSubscription, err := client.GetSubscription(subscription.ID)
+			if err != nil {
+				// If we can't fetch full details, continue with what we have
+				fmt.Fprintf(tui.Output(), "Note: Could not fetch full subscription details: %v\n\n", err)
+				fullSubscription = subscription
+			}
+
+			// Going back from the menu returns to the subscription picker
+			done, err := showManagementMenu(cfg, client, fullSubscription)
+			if err != nil || done {
+				return err
+			}
+		}
+	})
+}
+
+func runManageUpdate(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("file")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if subscription == nil {
+	if !cfg.IsAuthenticated() {
+		if err := templates.RenderToStdout(templates.ManageNotAuthenticatedTemplate, nil); err != nil {
+			return err
+		}
 		return nil
 	}
 
-	// Fetch full subscription details including order configuration
-	fullSubscription, err := client.GetSubscription(subscription.ID)
+	spec, err := order.LoadPreferencesSpec(path)
 	if err != nil {
-		// If we can't fetch full details, continue with what we have
-		fmt.Printf("Note: Could not fetch full subscription details: %v\n\n", err)
-		fullSubscription = subscription
+		return err
 	}
 
-	return showManagementMenu(cfg, client, fullSubscription)
+	client := api.NewClient(cfg)
+
+	subscription, err := client.GetSubscription(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	pricing, err := client.GetSubscriptionPricing(subscription.Tier)
+	if err != nil {
+		return fmt.Errorf("failed to get tier information: %w", err)
+	}
+
+	minQty, maxQty := planQuantityLimits(cfg, *pricing, true)
+	if err := spec.Validate(minQty, maxQty); err != nil {
+		return fmt.Errorf("invalid preferences file: %w", err)
+	}
+
+	lineItems := spec.OrderLineItems()
+
+	fmt.Fprintln(tui.Output(), order.RenderPreferenceDiff(subscription, pricing, spec.TotalQuantity, lineItems))
+	fmt.Fprintln(tui.Output())
+
+	if !order.HasChanges(order.DiffPreferences(subscription.DefaultPreferences, lineItems)) && spec.TotalQuantity == subscription.GetTotalQuantity() {
+		fmt.Fprintln(tui.Output(), "Nothing to update, your preferences already match.")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Fprintln(tui.Output(), "Dry run: no changes were sent.")
+		return nil
+	}
+
+	confirmed, err := prompts.PromptConfirm("Update subscription with these preferences?")
+	if err != nil || !confirmed {
+		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Update"}); err != nil {
+			fmt.Fprintln(tui.Output(), "Update cancelled.")
+		}
+		return nil
+	}
+
+	_, err = applyPreferenceUpdate(client, subscription.ID, spec.TotalQuantity, lineItems)
+	return err
 }
 
 func selectSubscriptionToManage(subscriptions []api.Subscription) (*api.Subscription, error) {
@@ -85,83 +200,175 @@ func selectSubscriptionToManage(subscriptions []api.Subscription) (*api.Subscrip
 
 	// If no active subscriptions, return early
 	if len(activeSubscriptions) == 0 {
-		fmt.Println("You don't have any active subscriptions to manage.")
-		fmt.Println("All your subscriptions have been cancelled.")
+		fmt.Fprintln(tui.Output(), "You don't have any active subscriptions to manage.")
+		fmt.Fprintln(tui.Output(), "All your subscriptions have been cancelled.")
 		return nil, nil
 	}
 
-	items := make([]models.ManageSubscriptionItem, len(activeSubscriptions)+1)
-	for i, sub := range activeSubscriptions {
-		statusIcon := getStatusIcon(sub.Status)
-		display := fmt.Sprintf("%s %s (%s)", statusIcon, sub.Tier, sub.Status)
-
-		item := models.ManageSubscriptionItem{
-			Subscription:    sub,
-			Display:         display,
-			Status:          sub.Status,
-			StartedAt:       "",
-			ExpiresAt:       "",
-			TotalQuantity:   0,
-			HasOrderDetails: false,
-			IsExit:          false,
-		}
+	items := make([]models.ManageSubscriptionItem, 0, len(activeSubscriptions)+2)
+	for _, sub := range activeSubscriptions {
+		items = append(items, manageSubscriptionItem(sub))
+	}
+
+	if len(activeSubscriptions) > 1 {
+		items = append(items, models.ManageSubscriptionItem{
+			Display: "☑ Pause or resume several",
+			IsBatch: true,
+		})
+	}
+
+	items = append(items, models.ManageSubscriptionItem{
+		Display: "← Exit",
+		IsExit:  true,
+	})
+
+	return models.PickManageSubscription(items)
+}
+
+// manageSubscriptionItem describes sub for the subscription pickers
+func manageSubscriptionItem(sub api.Subscription) models.ManageSubscriptionItem {
+	statusIcon := getStatusIcon(sub.Status)
+	display := fmt.Sprintf("%s %s (%s)", statusIcon, sub.Tier, sub.Status)
+
+	item := models.ManageSubscriptionItem{
+		Subscription: sub,
+		Display:      display,
+		Status:       sub.Status,
+	}
+
+	if sub.StartedAt != nil {
+		item.StartedAt = utils.FormatTimestamp(*sub.StartedAt)
+	}
+	if sub.ExpiresAt != nil {
+		item.ExpiresAt = utils.FormatTimestamp(*sub.ExpiresAt)
+	}
+	if sub.DefaultQuantity > 0 {
+		item.TotalQuantity = sub.GetTotalQuantity()
+		item.HasOrderDetails = true
+	}
+	return item
+}
 
-		if sub.StartedAt != nil {
-			item.StartedAt = utils.FormatTimestamp(*sub.StartedAt)
+// pauseResumeSeveral lets the user check several subscriptions and pause or resume
+// them all. Subscriptions whose status doesn't allow the action are skipped.
+func pauseResumeSeveral(client *api.Client, subscriptions []api.Subscription) error {
+	var items []models.ManageSubscriptionItem
+	for _, sub := range subscriptions {
+		if sub.Status == "active" || sub.Status == "paused" {
+			items = append(items, manageSubscriptionItem(sub))
 		}
-		if sub.ExpiresAt != nil {
-			item.ExpiresAt = utils.FormatTimestamp(*sub.ExpiresAt)
+	}
+
+	picked, err := models.PickSubscriptions("Check the subscriptions to pause or resume", items)
+	if errors.Is(err, prompts.ErrUserCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(picked) == 0 {
+		fmt.Fprintln(tui.Output(), "No subscriptions selected.")
+		return nil
+	}
+
+	action, err := models.SelectAction([]models.ActionItem{
+		{Action: "pause", Display: fmt.Sprintf("⏸  Pause %d selected", len(picked))},
+		{Action: "resume", Display: fmt.Sprintf("▶  Resume %d selected", len(picked))},
+		{Action: "exit", Display: "← Back"},
+	})
+	if err != nil || action == "exit" || action == "" {
+		return err
+	}
+
+	// from is the status a subscription needs for the action
+	verb, progress, past, from := "Pause", "Pausing", "paused", "active"
+	if action == "resume" {
+		verb, progress, past, from = "Resume", "Resuming", "resumed", "paused"
+	}
+
+	confirmed, err := prompts.PromptConfirm(fmt.Sprintf("%s %d subscriptions?", verb, len(picked)))
+	if err != nil || !confirmed {
+		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: verb}); err != nil {
+			fmt.Fprintln(tui.Output(), "Action cancelled.")
 		}
-		if sub.DefaultQuantity > 0 {
-			item.TotalQuantity = sub.GetTotalQuantity()
-			item.HasOrderDetails = true
+		return err
+	}
+
+	done, skipped, failed := 0, 0, 0
+	for _, sub := range picked {
+		if sub.Status != from {
+			fmt.Fprintf(tui.Output(), "Skipping %s: it is %s\n", sub.Tier, sub.Status)
+			skipped++
+			continue
 		}
 
-		items[i] = item
+		fmt.Fprintf(tui.Output(), "%s %s... ", progress, sub.Tier)
+		if action == "pause" {
+			_, err = client.PauseSubscription(sub.ID)
+		} else {
+			_, err = client.ResumeSubscription(sub.ID)
+		}
+		if err != nil {
+			fmt.Fprintf(tui.Output(), "✗ %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Fprintln(tui.Output(), "✓")
+		done++
 	}
-
-	items[len(activeSubscriptions)] = models.ManageSubscriptionItem{
-		Display: "← Exit",
-		IsExit:  true,
+	if done > 0 {
+		forgetCompletions("subscriptions")
 	}
 
-	return models.PickManageSubscription(items)
+	fmt.Fprintf(tui.Output(), "\n✓ %d %s", done, past)
+	if skipped > 0 {
+		fmt.Fprintf(tui.Output(), ", %d skipped", skipped)
+	}
+	if failed > 0 {
+		fmt.Fprintf(tui.Output(), ", %d failed", failed)
+	}
+	fmt.Fprintln(tui.Output())
+	return nil
 }
 
-func showManagementMenu(cfg *config.Config, client *api.Client, subscription *api.Subscription) error {
+// showManagementMenu shows the actions for subscription until one completes, which
+// it reports as done, or the user goes back
+func showManagementMenu(cfg *config.Config, client *api.Client, subscription *api.Subscription) (bool, error) {
 	for {
 		if err := displaySubscriptionInfo(client, subscription); err != nil {
-			return err
+			return false, err
 		}
 
 		actions := buildActionMenu(subscription.Status)
 
 		if len(actions) == 0 {
 			if err := templates.RenderToStdout(templates.NoActionsAvailableTemplate, nil); err != nil {
-				return err
+				return false, err
 			}
-			return nil
+			return true, nil
 		}
 
 		action, err := models.SelectAction(actions)
 		if err != nil {
-			return nil
+			return false, err
 		}
 
-		if action == "exit" {
-			return nil
+		if action == "exit" || action == "" {
+			return false, nil
 		}
 
 		_, err = executeAction(cfg, client, subscription, action)
 		if err != nil {
-			fmt.Printf("\nError: %v\n\n", err)
-			fmt.Print("Press Enter to continue...")
-			_, _ = bufio.NewReader(os.Stdin).ReadBytes('\n')
+			if errors.Is(err, tui.ErrQuit) {
+				return false, err
+			}
+			fmt.Fprintf(tui.Output(), "\nError: %v\n\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
 			continue
 		}
 
 		// Action completed successfully - exit the CLI
-		return nil
+		return true, nil
 	}
 }
 
@@ -207,18 +414,19 @@ func handlePause(client *api.Client, subscription *api.Subscription) (*api.Subsc
 	confirmed, err := prompts.PromptConfirm("Pause subscription? (You can resume it anytime)")
 	if err != nil || !confirmed {
 		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Pause"}); err != nil {
-			fmt.Println("Pause cancelled.")
+			fmt.Fprintln(tui.Output(), "Pause cancelled.")
 		}
 		return nil, nil
 	}
 
-	fmt.Print("\nPausing subscription... ")
+	fmt.Fprint(tui.Output(), "\nPausing subscription... ")
 	updatedSub, err := client.PauseSubscription(subscription.ID)
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return nil, err
 	}
-	fmt.Println("✓")
+	forgetCompletions("subscriptions")
+	fmt.Fprintln(tui.Output(), "✓")
 
 	if err := templates.RenderToStdout(templates.SubscriptionPausedTemplate, struct {
 		HasResumeDate bool
@@ -241,18 +449,19 @@ func handleResume(client *api.Client, subscription *api.Subscription) (*api.Subs
 	confirmed, err := prompts.PromptConfirm("Resume subscription?")
 	if err != nil || !confirmed {
 		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Resume"}); err != nil {
-			fmt.Println("Resume cancelled.")
+			fmt.Fprintln(tui.Output(), "Resume cancelled.")
 		}
 		return nil, nil
 	}
 
-	fmt.Print("\nResuming subscription... ")
+	fmt.Fprint(tui.Output(), "\nResuming subscription... ")
 	updatedSub, err := client.ResumeSubscription(subscription.ID)
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return nil, err
 	}
-	fmt.Println("✓")
+	forgetCompletions("subscriptions")
+	fmt.Fprintln(tui.Output(), "✓")
 
 	if err := templates.RenderToStdout(templates.SubscriptionResumedTemplate, nil); err != nil {
 		return nil, err
@@ -269,21 +478,7 @@ func handleUpdate(cfg *config.Config, client *api.Client, subscription *api.Subs
 	// Show current configuration
 	currentQuantity := subscription.GetTotalQuantity()
 	if currentQuantity > 0 && len(subscription.DefaultPreferences) > 0 {
-		formattedItems := make([]string, len(subscription.DefaultPreferences))
-		for i, pref := range subscription.DefaultPreferences {
-			qty := pref.GetQuantity()
-			if pref.GrindType == "whole_bean" {
-				formattedItems[i] = fmt.Sprintf("%d → Whole beans for %s",
-					qty,
-					order.BrewingMethodDisplay(pref.BrewingMethod))
-			} else {
-				grindDesc := order.GetGrindDescription(pref.BrewingMethod)
-				formattedItems[i] = fmt.Sprintf("%d → Ground for %s (%s)",
-					qty,
-					order.BrewingMethodDisplay(pref.BrewingMethod),
-					grindDesc)
-			}
-		}
+		formattedItems := formatPreferences(subscription.DefaultPreferences)
 
 		if err := templates.RenderToStdout(templates.CurrentSubscriptionConfigTemplate, struct {
 			TotalQuantity int
@@ -332,18 +527,18 @@ func handleUpdate(cfg *config.Config, client *api.Client, subscription *api.Subs
 	totalQuantity, err := prompts.PromptQuantityInt("New total quantity per month", minQty, maxQty, defaultQty)
 	if err != nil {
 		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Update"}); err != nil {
-			fmt.Println("Update cancelled.")
+			fmt.Fprintln(tui.Output(), "Update cancelled.")
 		}
 		return nil, nil
 	}
 
-	fmt.Printf("\n✓ New quantity: %d per month\n\n", totalQuantity)
+	fmt.Fprintf(tui.Output(), "\n✓ New quantity: %d per month\n\n", totalQuantity)
 
 	// Ask if they want to update preferences as well
 	wantsUpdatePreferences, err := prompts.PromptConfirm("Would you like to update your coffee preferences as well?")
 	if err != nil {
 		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Update"}); err != nil {
-			fmt.Println("Update cancelled.")
+			fmt.Fprintln(tui.Output(), "Update cancelled.")
 		}
 		return nil, nil
 	}
@@ -352,46 +547,13 @@ func handleUpdate(cfg *config.Config, client *api.Client, subscription *api.Subs
 
 	if !wantsUpdatePreferences {
 		// Quantity-only update - keep existing preferences proportionally
-		// or use a simple default if no preferences exist
-		if len(subscription.DefaultPreferences) > 0 {
-			// Keep existing preferences but adjust quantities proportionally
-			totalCurrentQty := float64(currentQuantity)
-			for _, pref := range subscription.DefaultPreferences {
-				currentQty := pref.GetQuantity()
-				// Calculate proportional quantity
-				proportion := float64(currentQty) / totalCurrentQty
-				newQty := max(
-					// Round to nearest
-					int(proportion*float64(totalQuantity)+0.5),
-					// Ensure at least 1
-					1)
-				lineItems = append(lineItems, api.OrderLineItem{
-					Quantity:      newQty,
-					GrindType:     pref.GrindType,
-					BrewingMethod: pref.BrewingMethod,
-				})
-			}
-			// Adjust for rounding errors
-			sumQty := 0
-			for _, item := range lineItems {
-				sumQty += item.Quantity
-			}
-			if sumQty != totalQuantity {
-				lineItems[0].Quantity += totalQuantity - sumQty
-			}
-		} else {
-			// No existing preferences, create a default uniform order
-			lineItems = []api.OrderLineItem{
-				{
-					Quantity:      totalQuantity,
-					GrindType:     "whole_bean",
-					BrewingMethod: "espresso",
-				},
-			}
+		lineItems, err = order.ScalePreferences(subscription.DefaultPreferences, currentQuantity, totalQuantity)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		// Full preference update
-		fmt.Println()
+		fmt.Fprintln(tui.Output())
 
 		if totalQuantity == 1 {
 			// Only 1 unit - cannot split, go straight to uniform order
@@ -404,7 +566,7 @@ func handleUpdate(cfg *config.Config, client *api.Client, subscription *api.Subs
 			wantsSplit, err := prompts.PromptConfirm("Would you like different grind methods?")
 			if err != nil {
 				if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Update"}); err != nil {
-					fmt.Println("Update cancelled.")
+					fmt.Fprintln(tui.Output(), "Update cancelled.")
 				}
 				return nil, nil
 			}
@@ -423,49 +585,33 @@ func handleUpdate(cfg *config.Config, client *api.Client, subscription *api.Subs
 		}
 	}
 
-	formattedItems := make([]string, len(lineItems))
-	for i, item := range lineItems {
-		if item.GrindType == "whole_bean" {
-			formattedItems[i] = fmt.Sprintf("%d → Whole beans for %s",
-				item.Quantity,
-				order.BrewingMethodDisplay(item.BrewingMethod))
-		} else {
-			grindDesc := order.GetGrindDescription(item.BrewingMethod)
-			formattedItems[i] = fmt.Sprintf("%d → Ground for %s (%s)",
-				item.Quantity,
-				order.BrewingMethodDisplay(item.BrewingMethod),
-				grindDesc)
-		}
-	}
-
-	if err := templates.RenderToStdout(templates.UpdatePreferencesSummaryTemplate, struct {
-		TotalQuantity int
-		LineItems     []string
-	}{
-		TotalQuantity: totalQuantity,
-		LineItems:     formattedItems,
-	}); err != nil {
-		return nil, err
-	}
+	fmt.Fprintln(tui.Output())
+	fmt.Fprintln(tui.Output(), order.RenderPreferenceDiff(subscription, currentTier, totalQuantity, lineItems))
+	fmt.Fprintln(tui.Output())
 
 	confirmed, err := prompts.PromptConfirm("Update subscription with these preferences?")
 	if err != nil || !confirmed {
 		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Update"}); err != nil {
-			fmt.Println("Update cancelled.")
+			fmt.Fprintln(tui.Output(), "Update cancelled.")
 		}
 		return nil, nil
 	}
 
-	fmt.Print("\nUpdating subscription... ")
-	updatedSub, err := client.UpdateSubscription(subscription.ID, api.UpdateSubscriptionRequest{
+	return applyPreferenceUpdate(client, subscription.ID, totalQuantity, lineItems)
+}
+
+// applyPreferenceUpdate sends new preferences to the API and reports the result
+func applyPreferenceUpdate(client *api.Client, subscriptionID string, totalQuantity int, lineItems []api.OrderLineItem) (*api.Subscription, error) {
+	fmt.Fprint(tui.Output(), "\nUpdating subscription... ")
+	updatedSub, err := client.UpdateSubscription(subscriptionID, api.UpdateSubscriptionRequest{
 		TotalQuantity: totalQuantity,
 		Preferences:   lineItems,
 	})
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return nil, err
 	}
-	fmt.Println("✓")
+	fmt.Fprintln(tui.Output(), "✓")
 
 	if err := templates.RenderToStdout(templates.SubscriptionUpdatedTemplate, nil); err != nil {
 		return nil, err
@@ -503,18 +649,19 @@ func handleCancel(client *api.Client, subscription *api.Subscription) (*api.Subs
 	confirmed, err := prompts.PromptConfirm("Type 'y' to permanently cancel")
 	if err != nil || !confirmed {
 		if err := templates.RenderToStdout(templates.ActionCancelledTemplate, struct{ Action string }{Action: "Cancellation"}); err != nil {
-			fmt.Println("Cancellation aborted.")
+			fmt.Fprintln(tui.Output(), "Cancellation aborted.")
 		}
 		return nil, nil
 	}
 
-	fmt.Print("\nCancelling subscription... ")
+	fmt.Fprint(tui.Output(), "\nCancelling subscription... ")
 	updatedSub, err := client.CancelSubscription(subscription.ID)
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return nil, err
 	}
-	fmt.Println("✓")
+	forgetCompletions("subscriptions")
+	fmt.Fprintln(tui.Output(), "✓")
 
 	if err := templates.RenderToStdout(templates.SubscriptionCancelledTemplate, nil); err != nil {
 		return nil, err
@@ -523,39 +670,37 @@ func handleCancel(client *api.Client, subscription *api.Subscription) (*api.Subs
 	return updatedSub, nil
 }
 
+// subscriptionView is the template data describing a subscription
+type subscriptionView struct {
+	ID              string
+	Tier            string
+	Status          string
+	StatusIcon      string
+	StartedAt       string
+	ExpiresAt       string
+	NextShipment    string
+	HasNextShipment bool
+	HasOrderDetails bool
+	TotalQuantity   int
+	LineItems       []string
+	HasPricing      bool
+	Price           string
+	Currency        string
+	BillingPeriod   string
+}
+
 func displaySubscriptionInfo(client *api.Client, subscription *api.Subscription) error {
-	statusIcon := getStatusIcon(subscription.Status)
-
-	data := struct {
-		Tier            string
-		Status          string
-		StatusIcon      string
-		StartedAt       string
-		ExpiresAt       string
-		NextShipment    string
-		HasNextShipment bool
-		HasOrderDetails bool
-		TotalQuantity   int
-		LineItems       []string
-		HasPricing      bool
-		Price           string
-		Currency        string
-		BillingPeriod   string
-	}{
-		Tier:            subscription.Tier,
-		Status:          subscription.Status,
-		StatusIcon:      statusIcon,
-		StartedAt:       "",
-		ExpiresAt:       "",
-		NextShipment:    "",
-		HasNextShipment: false,
-		HasOrderDetails: false,
-		TotalQuantity:   0,
-		LineItems:       []string{},
-		HasPricing:      false,
-		Price:           "",
-		Currency:        "",
-		BillingPeriod:   "",
+	return templates.RenderToStdout(templates.ManageSubscriptionHeaderTemplate, buildSubscriptionView(client, subscription))
+}
+
+// buildSubscriptionView collects the details shown for a subscription, including its price
+func buildSubscriptionView(client *api.Client, subscription *api.Subscription) subscriptionView {
+	data := subscriptionView{
+		ID:         subscription.ID,
+		Tier:       subscription.Tier,
+		Status:     subscription.Status,
+		StatusIcon: getStatusIcon(subscription.Status),
+		LineItems:  []string{},
 	}
 
 	if subscription.StartedAt != nil {
@@ -593,24 +738,19 @@ func displaySubscriptionInfo(client *api.Client, subscription *api.Subscription)
 		}
 
 		// Format preferences
-		data.LineItems = make([]string, len(subscription.DefaultPreferences))
-		for i, pref := range subscription.DefaultPreferences {
-			qty := pref.GetQuantity()
-			if pref.GrindType == "whole_bean" {
-				data.LineItems[i] = fmt.Sprintf("%d → Whole beans for %s",
-					qty,
-					order.BrewingMethodDisplay(pref.BrewingMethod))
-			} else {
-				grindDesc := order.GetGrindDescription(pref.BrewingMethod)
-				data.LineItems[i] = fmt.Sprintf("%d → Ground for %s (%s)",
-					qty,
-					order.BrewingMethodDisplay(pref.BrewingMethod),
-					grindDesc)
-			}
-		}
+		data.LineItems = formatPreferences(subscription.DefaultPreferences)
 	}
 
-	return templates.RenderToStdout(templates.ManageSubscriptionHeaderTemplate, data)
+	return data
+}
+
+// formatPreferences formats subscription preferences as "quantity → preparation" lines
+func formatPreferences(preferences []api.SubscriptionPreference) []string {
+	lines := make([]string, len(preferences))
+	for i, pref := range preferences {
+		lines[i] = fmt.Sprintf("%d → %s", pref.GetQuantity(), order.FormatPreparation(pref.GrindType, pref.BrewingMethod))
+	}
+	return lines
 }
 
 func calculateNextShipment(startedAtStr string) string {
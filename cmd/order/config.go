@@ -2,10 +2,12 @@ package order
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
 	"github.com/hassek/bc-cli/tui/models"
 	"github.com/hassek/bc-cli/tui/prompts"
 )
@@ -14,6 +16,22 @@ const (
 	DefaultPreferenceQuantity = 2 // Default quantity for new preferences
 )
 
+// GrindTypes lists the grind types accepted by the API
+var GrindTypes = []string{"whole_bean", "ground"}
+
+// BrewingMethods lists the brewing methods accepted by the API, in display order
+var BrewingMethods = []string{"espresso", "moka", "v60", "french_press", "pour_over", "drip", "cold_brew"}
+
+// IsValidGrindType reports whether grindType is a supported grind type
+func IsValidGrindType(grindType string) bool {
+	return slices.Contains(GrindTypes, grindType)
+}
+
+// IsValidBrewingMethod reports whether method is a supported brewing method
+func IsValidBrewingMethod(method string) bool {
+	return slices.Contains(BrewingMethods, method)
+}
+
 // ConfigureUniformOrder guides the user through configuring a uniform order (all same grind/brew method)
 func ConfigureUniformOrder(totalQuantity int) ([]api.OrderLineItem, error) {
 	if err := templates.RenderToStdout(templates.UniformOrderIntroTemplate, struct{ TotalQuantity int }{TotalQuantity: totalQuantity}); err != nil {
@@ -27,11 +45,11 @@ func ConfigureUniformOrder(totalQuantity int) ([]api.OrderLineItem, error) {
 	}
 
 	// Show confirmation based on choice
-	fmt.Println()
+	fmt.Fprintln(tui.Output())
 	if grindType == "whole_bean" {
-		fmt.Println("✓ You'll grind these beans yourself")
+		fmt.Fprintln(tui.Output(), "✓ You'll grind these beans yourself")
 	} else {
-		fmt.Println("✓ We'll grind these beans for you!")
+		fmt.Fprintln(tui.Output(), "✓ We'll grind these beans for you!")
 	}
 
 	// ALWAYS prompt for brewing method
@@ -41,16 +59,16 @@ func ConfigureUniformOrder(totalQuantity int) ([]api.OrderLineItem, error) {
 	}
 
 	// Confirmation message
-	fmt.Printf("\n✓ Perfect! All %d will be ", totalQuantity)
+	fmt.Fprintf(tui.Output(), "\n✓ Perfect! All %d will be ", totalQuantity)
 	if grindType == "whole_bean" {
-		fmt.Printf("whole beans, roasted for %s.\n", BrewingMethodDisplay(brewResult.Method))
+		fmt.Fprintf(tui.Output(), "whole beans, roasted for %s.\n", BrewingMethodDisplay(brewResult.Method))
 	} else {
-		fmt.Printf("ground for %s.\n", BrewingMethodDisplay(brewResult.Method))
+		fmt.Fprintf(tui.Output(), "ground for %s.\n", BrewingMethodDisplay(brewResult.Method))
 	}
 	if brewResult.Notes != "" {
-		fmt.Printf("  Notes: %s\n", brewResult.Notes)
+		fmt.Fprintf(tui.Output(), "  Notes: %s\n", brewResult.Notes)
 	}
-	fmt.Println()
+	fmt.Fprintln(tui.Output())
 
 	// Create single line item with full quantity
 	lineItems := []api.OrderLineItem{
@@ -79,8 +97,8 @@ func ConfigureLineItems(totalQuantity int) ([]api.OrderLineItem, error) {
 	for remaining > 0 {
 		// Show preference header with remaining amount
 		lowRemaining := float64(remaining) < float64(totalQuantity)*0.3
-		fmt.Println() // Add newline before the box
-		fmt.Println(templates.RenderPreferenceHeader(preferenceNum, totalQuantity, remaining, lowRemaining))
+		fmt.Fprintln(tui.Output()) // Add newline before the box
+		fmt.Fprintln(tui.Output(), templates.RenderPreferenceHeader(preferenceNum, totalQuantity, remaining, lowRemaining))
 
 		// Prompt for quantity with smart defaults
 		maxQty := remaining
@@ -96,24 +114,24 @@ func ConfigureLineItems(totalQuantity int) ([]api.OrderLineItem, error) {
 
 		// Show allocation confirmation
 		if quantity >= remaining {
-			fmt.Printf("\n✓ Allocating %d (this will complete your order!)\n\n", quantity)
+			fmt.Fprintf(tui.Output(), "\n✓ Allocating %d (this will complete your order!)\n\n", quantity)
 		} else {
-			fmt.Printf("\n✓ Allocating %d\n\n", quantity)
+			fmt.Fprintf(tui.Output(), "\n✓ Allocating %d\n\n", quantity)
 		}
 
 		// Prompt for grind type with explanation
-		fmt.Printf("  How would you like these %d prepared?\n\n", quantity)
+		fmt.Fprintf(tui.Output(), "  How would you like these %d prepared?\n\n", quantity)
 		grindType, err := SelectGrindType()
 		if err != nil {
 			return nil, err
 		}
 
 		// Show grind type confirmation
-		fmt.Println()
+		fmt.Fprintln(tui.Output())
 		if grindType == "ground" {
-			fmt.Println("✓ We'll grind these beans for you!")
+			fmt.Fprintln(tui.Output(), "✓ We'll grind these beans for you!")
 		} else {
-			fmt.Println("✓ You'll grind these beans yourself")
+			fmt.Fprintln(tui.Output(), "✓ You'll grind these beans yourself")
 		}
 
 		// Prompt for brewing method (ALWAYS, regardless of grind type)
@@ -130,16 +148,16 @@ func ConfigureLineItems(totalQuantity int) ([]api.OrderLineItem, error) {
 		})
 
 		// Updated confirmation message
-		fmt.Printf("\n✓ Added: %d ", quantity)
+		fmt.Fprintf(tui.Output(), "\n✓ Added: %d ", quantity)
 		if grindType == "whole_bean" {
-			fmt.Printf("whole beans for %s", BrewingMethodDisplay(brewResult.Method))
+			fmt.Fprintf(tui.Output(), "whole beans for %s", BrewingMethodDisplay(brewResult.Method))
 		} else {
-			fmt.Printf("ground for %s", BrewingMethodDisplay(brewResult.Method))
+			fmt.Fprintf(tui.Output(), "ground for %s", BrewingMethodDisplay(brewResult.Method))
 		}
 		if brewResult.Notes != "" {
-			fmt.Printf("\n  Notes: %s", brewResult.Notes)
+			fmt.Fprintf(tui.Output(), "\n  Notes: %s", brewResult.Notes)
 		}
-		fmt.Println()
+		fmt.Fprintln(tui.Output())
 
 		remaining -= quantity
 
@@ -155,8 +173,8 @@ func ConfigureLineItems(totalQuantity int) ([]api.OrderLineItem, error) {
 	}
 
 	// Success message
-	fmt.Println("\n" + strings.Repeat("─", 60) + "\n")
-	fmt.Printf("🎉 Perfect! You've allocated all %d!\n\n", totalQuantity)
+	fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("─", 60)+"\n")
+	fmt.Fprintf(tui.Output(), "🎉 Perfect! You've allocated all %d!\n\n", totalQuantity)
 
 	return lineItems, nil
 }
@@ -169,9 +187,9 @@ func SelectGrindType() (string, error) {
 // SelectBrewingMethod prompts the user to select a brewing method and add notes
 func SelectBrewingMethod(grindType string) (*models.BrewingMethodResult, error) {
 	// Show helpful message first
-	fmt.Println("  What is your preferred brewing method?")
-	fmt.Println("  This helps us understand the best profiles to ensure the best tasting experience!")
-	fmt.Println()
+	fmt.Fprintln(tui.Output(), "  What is your preferred brewing method?")
+	fmt.Fprintln(tui.Output(), "  This helps us understand the best profiles to ensure the best tasting experience!")
+	fmt.Fprintln(tui.Output())
 
 	return models.SelectBrewingMethod(grindType)
 }
@@ -212,5 +230,22 @@ func GetGrindDescription(method string) string {
 
 // ShowProgressBar displays a progress bar
 func ShowProgressBar(current, total int) {
-	fmt.Println(templates.RenderProgressBar(current, total))
+	fmt.Fprintln(tui.Output(), templates.RenderProgressBar(current, total))
+}
+
+// FormatPreparation describes how a line item will be prepared, e.g. "Ground for Espresso (very fine)"
+func FormatPreparation(grindType, method string) string {
+	if grindType == "whole_bean" {
+		return fmt.Sprintf("Whole beans for %s", BrewingMethodDisplay(method))
+	}
+	return fmt.Sprintf("Ground for %s (%s)", BrewingMethodDisplay(method), GetGrindDescription(method))
+}
+
+// FormatLineItems formats line items for display in order summaries
+func FormatLineItems(lineItems []api.OrderLineItem) []string {
+	formattedItems := make([]string, len(lineItems))
+	for i, item := range lineItems {
+		formattedItems[i] = fmt.Sprintf("%d → %s", item.Quantity, FormatPreparation(item.GrindType, item.BrewingMethod))
+	}
+	return formattedItems
 }
@@ -0,0 +1,131 @@
+package order
+
+import (
+	"fmt"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/templates"
+)
+
+// ChangeKind describes how a preference changed between the current and proposed configuration
+type ChangeKind int
+
+const (
+	ChangeUnchanged ChangeKind = iota
+	ChangeAdded
+	ChangeRemoved
+	ChangeModified
+)
+
+// PreferenceChange pairs a current preference with its proposed replacement.
+// Before is nil for added preferences and After is nil for removed ones.
+type PreferenceChange struct {
+	Kind   ChangeKind
+	Before *api.OrderLineItem
+	After  *api.OrderLineItem
+}
+
+// DiffPreferences compares the current subscription preferences with proposed line items.
+// Preferences are matched on grind type and brewing method; a match with a different
+// quantity or notes is reported as modified. Current preferences keep their order and
+// added preferences are listed last.
+func DiffPreferences(current []api.SubscriptionPreference, proposed []api.OrderLineItem) []PreferenceChange {
+	var changes []PreferenceChange
+	matched := make([]bool, len(proposed))
+
+	for _, pref := range current {
+		before := &api.OrderLineItem{
+			Quantity:      pref.GetQuantity(),
+			GrindType:     pref.GrindType,
+			BrewingMethod: pref.BrewingMethod,
+			Notes:         pref.Notes,
+		}
+
+		change := PreferenceChange{Kind: ChangeRemoved, Before: before}
+		for i := range proposed {
+			if matched[i] || proposed[i].GrindType != pref.GrindType || proposed[i].BrewingMethod != pref.BrewingMethod {
+				continue
+			}
+			matched[i] = true
+			change.After = &proposed[i]
+			if proposed[i].Quantity == before.Quantity && proposed[i].Notes == before.Notes {
+				change.Kind = ChangeUnchanged
+			} else {
+				change.Kind = ChangeModified
+			}
+			break
+		}
+		changes = append(changes, change)
+	}
+
+	for i := range proposed {
+		if !matched[i] {
+			changes = append(changes, PreferenceChange{Kind: ChangeAdded, After: &proposed[i]})
+		}
+	}
+
+	return changes
+}
+
+// HasChanges reports whether any preference differs
+func HasChanges(changes []PreferenceChange) bool {
+	for _, change := range changes {
+		if change.Kind != ChangeUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderPreferenceDiff renders a side-by-side diff of the current and proposed preferences,
+// including quantity and (when pricing is known) price deltas.
+func RenderPreferenceDiff(subscription *api.Subscription, pricing *api.AvailablePlan, totalQuantity int, lineItems []api.OrderLineItem) string {
+	changes := DiffPreferences(subscription.DefaultPreferences, lineItems)
+
+	view := templates.PreferenceDiffView{
+		QuantityBefore: subscription.GetTotalQuantity(),
+		QuantityAfter:  totalQuantity,
+	}
+
+	for _, change := range changes {
+		row := templates.DiffRow{}
+		switch change.Kind {
+		case ChangeAdded:
+			row.Op = templates.DiffAdded
+		case ChangeRemoved:
+			row.Op = templates.DiffRemoved
+		case ChangeModified:
+			row.Op = templates.DiffModified
+		default:
+			row.Op = templates.DiffUnchanged
+		}
+		if change.Before != nil {
+			row.Before = formatDiffItem(*change.Before)
+		}
+		if change.After != nil {
+			row.After = formatDiffItem(*change.After)
+		}
+		view.Rows = append(view.Rows, row)
+	}
+
+	if pricing != nil {
+		view.BillingPeriod = pricing.BillingPeriod
+		var basePrice float64
+		if _, err := fmt.Sscanf(pricing.Price, "%f", &basePrice); err == nil {
+			view.HasPricing = true
+			view.Currency = pricing.Currency
+			view.PriceBefore = basePrice * float64(view.QuantityBefore)
+			view.PriceAfter = basePrice * float64(view.QuantityAfter)
+		}
+	}
+
+	return templates.RenderPreferenceDiff(view)
+}
+
+func formatDiffItem(item api.OrderLineItem) string {
+	text := fmt.Sprintf("%d → %s", item.Quantity, FormatPreparation(item.GrindType, item.BrewingMethod))
+	if item.Notes != "" {
+		text += fmt.Sprintf("\nNotes: %s", item.Notes)
+	}
+	return text
+}
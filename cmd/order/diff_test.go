@@ -0,0 +1,116 @@
+package order
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+func TestDiffPreferences(t *testing.T) {
+	current := []api.SubscriptionPreference{
+		{ID: "1", Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"},
+		{ID: "2", Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60"},
+		{ID: "3", Quantity: 1, GrindType: "ground", BrewingMethod: "drip", Notes: "light"},
+	}
+	proposed := []api.OrderLineItem{
+		{Quantity: 3, GrindType: "ground", BrewingMethod: "espresso"},
+		{Quantity: 1, GrindType: "ground", BrewingMethod: "drip", Notes: "light"},
+		{Quantity: 2, GrindType: "ground", BrewingMethod: "cold_brew"},
+	}
+
+	changes := DiffPreferences(current, proposed)
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(changes))
+	}
+
+	want := []ChangeKind{ChangeModified, ChangeRemoved, ChangeUnchanged, ChangeAdded}
+	for i, kind := range want {
+		if changes[i].Kind != kind {
+			t.Errorf("change %d: expected kind %d, got %d", i, kind, changes[i].Kind)
+		}
+	}
+
+	if changes[0].Before.Quantity != 2 || changes[0].After.Quantity != 3 {
+		t.Errorf("expected espresso quantity 2 → 3, got %d → %d", changes[0].Before.Quantity, changes[0].After.Quantity)
+	}
+	if changes[1].After != nil {
+		t.Errorf("expected removed change to have no After")
+	}
+	if changes[3].Before != nil || changes[3].After.BrewingMethod != "cold_brew" {
+		t.Errorf("expected added cold brew, got %+v", changes[3])
+	}
+
+	if !HasChanges(changes) {
+		t.Error("expected HasChanges to be true")
+	}
+}
+
+func TestDiffPreferencesNotesChange(t *testing.T) {
+	current := []api.SubscriptionPreference{{Quantity: 2, GrindType: "ground", BrewingMethod: "moka"}}
+	proposed := []api.OrderLineItem{{Quantity: 2, GrindType: "ground", BrewingMethod: "moka", Notes: "darker"}}
+
+	changes := DiffPreferences(current, proposed)
+	if len(changes) != 1 || changes[0].Kind != ChangeModified {
+		t.Fatalf("expected a single modified change, got %+v", changes)
+	}
+}
+
+func TestDiffPreferencesUnchanged(t *testing.T) {
+	current := []api.SubscriptionPreference{{Quantity: 2, GrindType: "ground", BrewingMethod: "moka"}}
+	proposed := []api.OrderLineItem{{Quantity: 2, GrindType: "ground", BrewingMethod: "moka"}}
+
+	if HasChanges(DiffPreferences(current, proposed)) {
+		t.Error("expected no changes")
+	}
+}
+
+func TestRenderPreferenceDiff(t *testing.T) {
+	subscription := &api.Subscription{
+		DefaultQuantity: 2,
+		DefaultPreferences: []api.SubscriptionPreference{
+			{Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"},
+		},
+	}
+	pricing := &api.AvailablePlan{Price: "10.00", Currency: "EUR", BillingPeriod: "month"}
+	proposed := []api.OrderLineItem{{Quantity: 3, GrindType: "whole_bean", BrewingMethod: "v60"}}
+
+	output := RenderPreferenceDiff(subscription, pricing, 3, proposed)
+
+	for _, want := range []string{"Current", "Proposed", "Ground for Espresso", "Whole beans for V60", "2 → 3", "(+1)", "20.00 → 30.00", "(+10.00)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected diff to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRenderPreferenceDiffBillingPeriod(t *testing.T) {
+	subscription := &api.Subscription{
+		DefaultQuantity:    2,
+		DefaultPreferences: []api.SubscriptionPreference{{Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"}},
+	}
+	proposed := []api.OrderLineItem{{Quantity: 3, GrindType: "ground", BrewingMethod: "espresso"}}
+
+	tests := []struct {
+		name    string
+		pricing *api.AvailablePlan
+		want    string
+		notWant string
+	}{
+		{name: "Weekly plan", pricing: &api.AvailablePlan{Price: "10.00", Currency: "EUR", BillingPeriod: "week"}, want: "2 → 3 per week", notWant: "per month"},
+		{name: "Unparsable price", pricing: &api.AvailablePlan{Price: "free", BillingPeriod: "quarter"}, want: "2 → 3 per quarter", notWant: "per month"},
+		{name: "No pricing", want: "2 → 3", notWant: " per "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := RenderPreferenceDiff(subscription, tt.pricing, 3, proposed)
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("expected diff to contain %q, got:\n%s", tt.want, output)
+			}
+			if strings.Contains(output, tt.notWant) {
+				t.Errorf("expected diff not to contain %q, got:\n%s", tt.notWant, output)
+			}
+		})
+	}
+}
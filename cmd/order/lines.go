@@ -0,0 +1,111 @@
+package order
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+// ParseLineItem parses a line item in the form method:grind:quantity[:notes],
+// e.g. "espresso:ground:2" or "v60:whole_bean:1:light roast".
+func ParseLineItem(value string) (api.OrderLineItem, error) {
+	parts := strings.SplitN(value, ":", 4)
+	if len(parts) < 3 {
+		return api.OrderLineItem{}, fmt.Errorf("invalid line %q (expected method:grind:quantity[:notes])", value)
+	}
+
+	quantity, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return api.OrderLineItem{}, fmt.Errorf("invalid line %q: quantity must be a number", value)
+	}
+
+	item := api.OrderLineItem{
+		Quantity:      quantity,
+		GrindType:     parts[1],
+		BrewingMethod: parts[0],
+	}
+	if len(parts) == 4 {
+		item.Notes = parts[3]
+	}
+	return item, nil
+}
+
+// ValidateLineItems checks line items against the quantity limits and the supported
+// grind types and brewing methods. A zero total is derived from the line items.
+func ValidateLineItems(total int, items []api.OrderLineItem, minQty, maxQty int) (int, error) {
+	specs := make([]LineItemSpec, len(items))
+	for i, item := range items {
+		specs[i] = LineItemSpec{
+			Quantity:      item.Quantity,
+			GrindType:     item.GrindType,
+			BrewingMethod: item.BrewingMethod,
+			Notes:         item.Notes,
+		}
+	}
+	return validateQuantities(total, specs, minQty, maxQty)
+}
+
+// ScalePreferences keeps the existing preferences but adjusts their quantities
+// proportionally to a new total, keeping each at least 1. Without existing
+// preferences a uniform whole bean espresso order is returned.
+func ScalePreferences(preferences []api.SubscriptionPreference, currentTotal, newTotal int) ([]api.OrderLineItem, error) {
+	if len(preferences) == 0 || currentTotal <= 0 {
+		return []api.OrderLineItem{
+			{
+				Quantity:      newTotal,
+				GrindType:     "whole_bean",
+				BrewingMethod: "espresso",
+			},
+		}, nil
+	}
+	if newTotal < len(preferences) {
+		return nil, fmt.Errorf("a quantity of %d is too small for %d preferences, each needs at least 1", newTotal, len(preferences))
+	}
+
+	// Round down, but keep at least 1, and remember what rounding left over
+	lineItems := make([]api.OrderLineItem, len(preferences))
+	remainders := make([]float64, len(preferences))
+	sumQty := 0
+	for i, pref := range preferences {
+		exact := float64(pref.GetQuantity()) * float64(newTotal) / float64(currentTotal)
+		lineItems[i] = api.OrderLineItem{
+			Quantity:      max(int(exact), 1),
+			GrindType:     pref.GrindType,
+			BrewingMethod: pref.BrewingMethod,
+			Notes:         pref.Notes,
+		}
+		remainders[i] = exact - float64(lineItems[i].Quantity)
+		sumQty += lineItems[i].Quantity
+	}
+
+	// Spread the rounding difference: missing units go to the items that lost the
+	// most to rounding, extra ones come off the items that gained the most
+	order := make([]int, len(lineItems))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[b], remainders[a])
+	})
+	for i := 0; sumQty < newTotal; i++ {
+		lineItems[order[i%len(order)]].Quantity++
+		sumQty++
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[a], remainders[b])
+	})
+	for sumQty > newTotal {
+		for _, i := range order {
+			if sumQty > newTotal && lineItems[i].Quantity > 1 {
+				lineItems[i].Quantity--
+				sumQty--
+			}
+		}
+	}
+
+	return lineItems, nil
+}
@@ -0,0 +1,186 @@
+package order
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+func TestParseLineItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    api.OrderLineItem
+		wantErr string
+	}{
+		{
+			name:  "without notes",
+			value: "espresso:ground:2",
+			want:  api.OrderLineItem{Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"},
+		},
+		{
+			name:  "notes may contain colons",
+			value: "v60:whole_bean:1:light roast: please",
+			want:  api.OrderLineItem{Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60", Notes: "light roast: please"},
+		},
+		{
+			name:    "missing quantity",
+			value:   "espresso:ground",
+			wantErr: "expected method:grind:quantity",
+		},
+		{
+			name:    "non-numeric quantity",
+			value:   "espresso:ground:two",
+			wantErr: "quantity must be a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLineItem(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateLineItems(t *testing.T) {
+	items := []api.OrderLineItem{
+		{Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"},
+		{Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60"},
+	}
+
+	total, err := ValidateLineItems(0, items, 2, 10)
+	if err != nil || total != 3 {
+		t.Fatalf("expected derived total 3, got %d (%v)", total, err)
+	}
+
+	if _, err := ValidateLineItems(4, items, 2, 10); err == nil {
+		t.Error("expected error for mismatched total")
+	}
+}
+
+func TestScalePreferences(t *testing.T) {
+	tests := []struct {
+		name         string
+		preferences  []api.SubscriptionPreference
+		currentTotal int
+		newTotal     int
+		want         []int
+		wantErr      bool
+	}{
+		{
+			name: "doubles proportionally",
+			preferences: []api.SubscriptionPreference{
+				{Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"},
+				{Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60"},
+			},
+			currentTotal: 3,
+			newTotal:     6,
+			want:         []int{4, 2},
+		},
+		{
+			name: "rounding difference goes to the first item",
+			preferences: []api.SubscriptionPreference{
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "espresso"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "moka"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "drip"},
+			},
+			currentTotal: 3,
+			newTotal:     4,
+			want:         []int{2, 1, 1},
+		},
+		{
+			name: "every item keeps at least 1",
+			preferences: []api.SubscriptionPreference{
+				{Quantity: 7, GrindType: "ground", BrewingMethod: "espresso"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "moka"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "drip"},
+				{Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60"},
+			},
+			currentTotal: 10,
+			newTotal:     4,
+			want:         []int{1, 1, 1, 1},
+		},
+		{
+			name: "rounding difference is spread across the items",
+			preferences: []api.SubscriptionPreference{
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "espresso"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "moka"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "drip"},
+			},
+			currentTotal: 3,
+			newTotal:     5,
+			want:         []int{2, 2, 1},
+		},
+		{
+			name: "extra units come off the items that can spare them",
+			preferences: []api.SubscriptionPreference{
+				{Quantity: 5, GrindType: "ground", BrewingMethod: "espresso"},
+				{Quantity: 5, GrindType: "ground", BrewingMethod: "moka"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "drip"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "cold_brew"},
+				{Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60"},
+			},
+			currentTotal: 13,
+			newTotal:     6,
+			want:         []int{1, 2, 1, 1, 1},
+		},
+		{
+			name: "too few for the preferences",
+			preferences: []api.SubscriptionPreference{
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "espresso"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "moka"},
+				{Quantity: 1, GrindType: "ground", BrewingMethod: "drip"},
+				{Quantity: 1, GrindType: "whole_bean", BrewingMethod: "v60"},
+			},
+			currentTotal: 4,
+			newTotal:     2,
+			wantErr:      true,
+		},
+		{
+			name:         "no preferences",
+			currentTotal: 0,
+			newTotal:     3,
+			want:         []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ScalePreferences(tt.preferences, tt.currentTotal, tt.newTotal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ScalePreferences() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			sum := 0
+			for _, item := range items {
+				if item.Quantity < 1 {
+					t.Errorf("item quantity %d, want at least 1", item.Quantity)
+				}
+				sum += item.Quantity
+			}
+			if !tt.wantErr && sum != tt.newTotal {
+				t.Errorf("quantities add up to %d, want %d", sum, tt.newTotal)
+			}
+			if len(items) != len(tt.want) {
+				t.Fatalf("expected %d items, got %d", len(tt.want), len(items))
+			}
+			for i, qty := range tt.want {
+				if items[i].Quantity != qty {
+					t.Errorf("item %d: expected quantity %d, got %d", i, qty, items[i].Quantity)
+				}
+			}
+		})
+	}
+}
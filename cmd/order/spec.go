@@ -0,0 +1,236 @@
+package order
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hassek/bc-cli/api"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxNotesLength matches the character limit of the interactive notes prompt
+const MaxNotesLength = 500
+
+// OrderSpec is a declarative description of an order, loaded from a YAML or JSON file.
+// Exactly one of Tier (subscription) or ProductID (one-time purchase) must be set.
+type OrderSpec struct {
+	Tier          string         `json:"tier,omitempty" yaml:"tier,omitempty"`
+	ProductID     string         `json:"product_id,omitempty" yaml:"product_id,omitempty"`
+	TotalQuantity int            `json:"total_quantity,omitempty" yaml:"total_quantity,omitempty"`
+	LineItems     []LineItemSpec `json:"line_items" yaml:"line_items"`
+}
+
+// LineItemSpec describes how part of an order should be prepared
+type LineItemSpec struct {
+	Quantity      int    `json:"quantity" yaml:"quantity"`
+	GrindType     string `json:"grind_type" yaml:"grind_type"`
+	BrewingMethod string `json:"brewing_method" yaml:"brewing_method"`
+	Notes         string `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+// IsSubscription reports whether the spec describes a subscription order
+func (s *OrderSpec) IsSubscription() bool {
+	return s.Tier != ""
+}
+
+// LoadSpec reads an order spec from a file. Files ending in .json are parsed as JSON,
+// everything else as YAML. A path of "-" reads from stdin.
+func LoadSpec(path string) (*OrderSpec, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order file: %w", err)
+	}
+
+	spec, err := ParseSpec(data, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// ParseSpec decodes an order spec, rejecting unknown fields so typos don't go unnoticed
+func ParseSpec(data []byte, isJSON bool) (*OrderSpec, error) {
+	var spec OrderSpec
+	if err := decodeStrict(data, isJSON, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// decodeStrict decodes JSON or YAML into v, rejecting unknown fields
+func decodeStrict(data []byte, isJSON bool, v any) error {
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			return fmt.Errorf("file is empty")
+		}
+		return err
+	}
+	return nil
+}
+
+// Validate checks the spec against the plan's quantity limits and the supported
+// grind types and brewing methods. A missing total quantity is derived from the line items.
+func (s *OrderSpec) Validate(minQty, maxQty int) error {
+	if err := s.validatePlan(); err != nil {
+		return err
+	}
+
+	total, err := validateQuantities(s.TotalQuantity, s.LineItems, minQty, maxQty)
+	if err != nil {
+		return err
+	}
+	s.TotalQuantity = total
+	return nil
+}
+
+// ValidateWithoutLimits checks everything Validate does except the plan's quantity
+// limits, so a broken spec is rejected before the plan is fetched
+func (s *OrderSpec) ValidateWithoutLimits() error {
+	if err := s.validatePlan(); err != nil {
+		return err
+	}
+	_, err := validateQuantities(s.TotalQuantity, s.LineItems, 1, math.MaxInt)
+	return err
+}
+
+// validatePlan checks that exactly one of the tier and the product is set
+func (s *OrderSpec) validatePlan() error {
+	if s.Tier == "" && s.ProductID == "" {
+		return fmt.Errorf("either tier or product_id must be set")
+	}
+	if s.Tier != "" && s.ProductID != "" {
+		return fmt.Errorf("tier and product_id are mutually exclusive")
+	}
+	return nil
+}
+
+// validateQuantities validates the line items and checks that they add up to a total
+// within [minQty, maxQty]. A zero total is derived from the line items.
+func validateQuantities(total int, items []LineItemSpec, minQty, maxQty int) (int, error) {
+	if err := validateLineItems(items); err != nil {
+		return 0, err
+	}
+
+	sum := 0
+	for _, item := range items {
+		sum += item.Quantity
+	}
+	if total == 0 {
+		total = sum
+	}
+
+	if total < minQty || total > maxQty {
+		return 0, fmt.Errorf("total_quantity %d is out of range (must be between %d and %d)", total, minQty, maxQty)
+	}
+	if sum != total {
+		return 0, fmt.Errorf("line item quantities add up to %d but total_quantity is %d", sum, total)
+	}
+
+	return total, nil
+}
+
+// validateLineItems checks each line item on its own
+func validateLineItems(items []LineItemSpec) error {
+	if len(items) == 0 {
+		return fmt.Errorf("at least one line item is required")
+	}
+
+	for i, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("line item %d: quantity must be greater than 0", i+1)
+		}
+		if !IsValidGrindType(item.GrindType) {
+			return fmt.Errorf("line item %d: unknown grind_type %q (expected one of: %s)", i+1, item.GrindType, strings.Join(GrindTypes, ", "))
+		}
+		if !IsValidBrewingMethod(item.BrewingMethod) {
+			return fmt.Errorf("line item %d: unknown brewing_method %q (expected one of: %s)", i+1, item.BrewingMethod, strings.Join(BrewingMethods, ", "))
+		}
+		if utf8.RuneCountInString(item.Notes) > MaxNotesLength {
+			return fmt.Errorf("line item %d: notes exceed maximum length of %d characters", i+1, MaxNotesLength)
+		}
+	}
+
+	return nil
+}
+
+// OrderLineItems converts the spec's line items into API line items
+func (s *OrderSpec) OrderLineItems() []api.OrderLineItem {
+	return toOrderLineItems(s.LineItems)
+}
+
+func toOrderLineItems(items []LineItemSpec) []api.OrderLineItem {
+	lineItems := make([]api.OrderLineItem, len(items))
+	for i, item := range items {
+		lineItems[i] = api.OrderLineItem{
+			Quantity:      item.Quantity,
+			GrindType:     item.GrindType,
+			BrewingMethod: item.BrewingMethod,
+			Notes:         item.Notes,
+		}
+	}
+	return lineItems
+}
+
+// PreferencesSpec describes new subscription preferences, loaded from a YAML or JSON file
+type PreferencesSpec struct {
+	TotalQuantity int            `json:"total_quantity,omitempty" yaml:"total_quantity,omitempty"`
+	LineItems     []LineItemSpec `json:"line_items" yaml:"line_items"`
+}
+
+// LoadPreferencesSpec reads subscription preferences from a file. Files ending in .json
+// are parsed as JSON, everything else as YAML. A path of "-" reads from stdin.
+func LoadPreferencesSpec(path string) (*PreferencesSpec, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read preferences file: %w", err)
+	}
+
+	var spec PreferencesSpec
+	if err := decodeStrict(data, strings.EqualFold(filepath.Ext(path), ".json"), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks the preferences against the tier's quantity limits.
+// A missing total quantity is derived from the line items.
+func (s *PreferencesSpec) Validate(minQty, maxQty int) error {
+	total, err := validateQuantities(s.TotalQuantity, s.LineItems, minQty, maxQty)
+	if err != nil {
+		return err
+	}
+	s.TotalQuantity = total
+	return nil
+}
+
+// OrderLineItems converts the preferences into API line items
+func (s *PreferencesSpec) OrderLineItems() []api.OrderLineItem {
+	return toOrderLineItems(s.LineItems)
+}
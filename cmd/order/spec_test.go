@@ -0,0 +1,222 @@
+package order
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		isJSON  bool
+		wantErr string
+		check   func(t *testing.T, spec *OrderSpec)
+	}{
+		{
+			name: "yaml subscription",
+			data: `tier: explorer
+total_quantity: 3
+line_items:
+  - quantity: 2
+    grind_type: ground
+    brewing_method: espresso
+    notes: Italian style
+  - quantity: 1
+    grind_type: whole_bean
+    brewing_method: v60
+`,
+			check: func(t *testing.T, spec *OrderSpec) {
+				if spec.Tier != "explorer" || !spec.IsSubscription() {
+					t.Errorf("expected explorer subscription, got tier %q", spec.Tier)
+				}
+				if len(spec.LineItems) != 2 {
+					t.Fatalf("expected 2 line items, got %d", len(spec.LineItems))
+				}
+				if spec.LineItems[0].Notes != "Italian style" {
+					t.Errorf("expected notes to be parsed, got %q", spec.LineItems[0].Notes)
+				}
+			},
+		},
+		{
+			name:   "json product",
+			data:   `{"product_id": "prod-1", "line_items": [{"quantity": 2, "grind_type": "ground", "brewing_method": "drip"}]}`,
+			isJSON: true,
+			check: func(t *testing.T, spec *OrderSpec) {
+				if spec.ProductID != "prod-1" || spec.IsSubscription() {
+					t.Errorf("expected product prod-1, got %+v", spec)
+				}
+			},
+		},
+		{
+			name:    "unknown yaml field",
+			data:    "tier: explorer\nquantity: 3\n",
+			wantErr: "field quantity not found",
+		},
+		{
+			name:    "unknown json field",
+			data:    `{"tier": "explorer", "grind": "ground"}`,
+			isJSON:  true,
+			wantErr: "unknown field",
+		},
+		{
+			name:    "empty yaml",
+			data:    "",
+			wantErr: "file is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec([]byte(tt.data), tt.isJSON)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, spec)
+		})
+	}
+}
+
+func TestOrderSpecValidate(t *testing.T) {
+	item := func(qty int) LineItemSpec {
+		return LineItemSpec{Quantity: qty, GrindType: "ground", BrewingMethod: "espresso"}
+	}
+
+	tests := []struct {
+		name      string
+		spec      OrderSpec
+		wantErr   string
+		wantTotal int
+		limits    bool // the error comes from the quantity limits, which need the plan
+	}{
+		{
+			name:      "valid with explicit total",
+			spec:      OrderSpec{Tier: "explorer", TotalQuantity: 3, LineItems: []LineItemSpec{item(2), item(1)}},
+			wantTotal: 3,
+		},
+		{
+			name:      "total derived from line items",
+			spec:      OrderSpec{ProductID: "prod-1", LineItems: []LineItemSpec{item(2), item(2)}},
+			wantTotal: 4,
+		},
+		{
+			name:    "missing plan",
+			spec:    OrderSpec{LineItems: []LineItemSpec{item(1)}},
+			wantErr: "either tier or product_id",
+		},
+		{
+			name:    "both tier and product",
+			spec:    OrderSpec{Tier: "explorer", ProductID: "prod-1", LineItems: []LineItemSpec{item(1)}},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "no line items",
+			spec:    OrderSpec{Tier: "explorer", TotalQuantity: 2},
+			wantErr: "at least one line item",
+		},
+		{
+			name:    "total above max",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{item(11)}},
+			wantErr: "out of range",
+			limits:  true,
+		},
+		{
+			name:    "total below min",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{item(1)}},
+			wantErr: "out of range",
+			limits:  true,
+		},
+		{
+			name:    "mismatched total",
+			spec:    OrderSpec{Tier: "explorer", TotalQuantity: 5, LineItems: []LineItemSpec{item(2), item(2)}},
+			wantErr: "add up to 4",
+		},
+		{
+			name:    "zero quantity",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{item(2), item(0)}},
+			wantErr: "line item 2: quantity",
+		},
+		{
+			name:    "unknown grind type",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{{Quantity: 2, GrindType: "fine", BrewingMethod: "espresso"}}},
+			wantErr: "unknown grind_type",
+		},
+		{
+			name:    "unknown brewing method",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{{Quantity: 2, GrindType: "ground", BrewingMethod: "aeropress"}}},
+			wantErr: "unknown brewing_method",
+		},
+		{
+			name:    "notes too long",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{{Quantity: 2, GrindType: "ground", BrewingMethod: "drip", Notes: strings.Repeat("x", MaxNotesLength+1)}}},
+			wantErr: "notes exceed",
+		},
+		{
+			name:      "multibyte notes at the limit",
+			spec:      OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{{Quantity: 2, GrindType: "ground", BrewingMethod: "drip", Notes: strings.Repeat("é", MaxNotesLength)}}},
+			wantTotal: 2,
+		},
+		{
+			name:    "multibyte notes too long",
+			spec:    OrderSpec{Tier: "explorer", LineItems: []LineItemSpec{{Quantity: 2, GrindType: "ground", BrewingMethod: "drip", Notes: strings.Repeat("☕", MaxNotesLength+1)}}},
+			wantErr: "notes exceed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			early := tt.spec.ValidateWithoutLimits()
+			if wantEarly := tt.wantErr != "" && !tt.limits; (early != nil) != wantEarly {
+				t.Errorf("ValidateWithoutLimits() error = %v, want an error: %v", early, wantEarly)
+			}
+
+			err := tt.spec.Validate(2, 10)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.spec.TotalQuantity != tt.wantTotal {
+				t.Errorf("expected total %d, got %d", tt.wantTotal, tt.spec.TotalQuantity)
+			}
+		})
+	}
+}
+
+func TestLoadSpecFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "order.json")
+	data := `{"tier": "alpine", "line_items": [{"quantity": 2, "grind_type": "whole_bean", "brewing_method": "moka", "notes": "dark"}]}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := LoadSpec(path)
+	if err != nil {
+		t.Fatalf("LoadSpec failed: %v", err)
+	}
+
+	items := spec.OrderLineItems()
+	if len(items) != 1 {
+		t.Fatalf("expected 1 line item, got %d", len(items))
+	}
+	if items[0].Quantity != 2 || items[0].GrindType != "whole_bean" || items[0].BrewingMethod != "moka" || items[0].Notes != "dark" {
+		t.Errorf("unexpected line item: %+v", items[0])
+	}
+
+	if _, err := LoadSpec(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/cmd/order"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/prompts"
+	"github.com/hassek/bc-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var orderCmd = &cobra.Command{
+	Use:     "order",
+	Aliases: []string{"orders"},
+	Short:   "Work with coffee orders",
+	Long:    `List your coffee orders and create new ones from declarative order files.`,
+}
+
+var orderListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your orders",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runOrderList,
+}
+
+var orderShowCmd = &cobra.Command{
+	Use:   "show <order-id>",
+	Short: "Show an order's details",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runOrderShow,
+}
+
+var orderApplyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Create an order from a YAML or JSON file",
+	Long: `Create a subscription or product order from a YAML or JSON file.
+
+The file is validated against the plan's quantity limits, the order summary is
+shown, and checkout opens in your browser once you confirm. Use "-f -" to read
+the file from stdin. --tier or --product-id orders another plan with the same
+line items, in place of the file's tier or product_id.
+
+Example order.yaml:
+
+  tier: explorer          # or product_id: <id> for a one-time purchase
+  total_quantity: 4       # optional, defaults to the sum of the line items
+  line_items:
+    - quantity: 2
+      grind_type: ground
+      brewing_method: espresso
+      notes: Italian style please
+    - quantity: 2
+      grind_type: whole_bean
+      brewing_method: v60`,
+	Args: cobra.NoArgs,
+	RunE: runOrderApply,
+}
+
+func init() {
+	rootCmd.AddCommand(orderCmd)
+	orderCmd.AddCommand(orderApplyCmd)
+	orderCmd.AddCommand(orderListCmd)
+	orderCmd.AddCommand(orderShowCmd)
+
+	orderApplyCmd.Flags().StringP("file", "f", "", "Order file (YAML or JSON, \"-\" for stdin)")
+	orderApplyCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation and go straight to checkout")
+	orderApplyCmd.Flags().String("tier", "", "Subscription tier to order instead of the file's")
+	orderApplyCmd.Flags().String("product-id", "", "Product to purchase instead of the file's")
+	_ = orderApplyCmd.MarkFlagRequired("file")
+	_ = orderApplyCmd.RegisterFlagCompletionFunc("tier", completeTiers)
+	_ = orderApplyCmd.RegisterFlagCompletionFunc("product-id", completeProductIDs)
+}
+
+func runOrderApply(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("file")
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	tier, _ := cmd.Flags().GetString("tier")
+	productID, _ := cmd.Flags().GetString("product-id")
+	if tier != "" && productID != "" {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("--tier and --product-id are mutually exclusive"))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if !cfg.IsAuthenticated() {
+		return fmt.Errorf("you must be logged in to place an order. Please run 'bc-cli login' first")
+	}
+
+	spec, err := order.LoadSpec(path)
+	if err != nil {
+		return err
+	}
+	switch {
+	case tier != "":
+		spec.Tier, spec.ProductID = tier, ""
+	case productID != "":
+		spec.Tier, spec.ProductID = "", productID
+	}
+	// A broken file fails before anything is fetched
+	if err := spec.ValidateWithoutLimits(); err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("invalid order file: %w", err))
+	}
+
+	client := api.NewClient(cfg)
+
+	plan, err := resolveOrderPlan(client, spec)
+	if err != nil {
+		return err
+	}
+
+	minQty, maxQty := planQuantityLimits(cfg, *plan, spec.IsSubscription())
+	if err := spec.Validate(minQty, maxQty); err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("invalid order file: %w", err))
+	}
+
+	lineItems := spec.OrderLineItems()
+
+	if spec.IsSubscription() {
+		err = showOrderSummary(*plan, spec.TotalQuantity, lineItems)
+	} else {
+		err = showProductOrderSummary(*plan, spec.TotalQuantity, lineItems)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !skipConfirm {
+		confirmed, err := prompts.PromptConfirm("Looks good! Proceed to checkout?")
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(tui.Output(), "\nOrder cancelled.")
+			return nil
+		}
+	}
+
+	if spec.IsSubscription() {
+		return checkoutSubscriptionOrder(client, *plan, spec.TotalQuantity, lineItems)
+	}
+	return checkoutProductOrder(client, *plan, spec.TotalQuantity, lineItems)
+}
+
+func runOrderList(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	orders, err := client.ListOrders()
+	if err != nil {
+		return fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	if format.IsStructured() {
+		items := make([]output.Order, len(orders))
+		for i, o := range orders {
+			items[i] = output.FromOrder(o)
+		}
+		return writeOutput(format, items, output.OrdersTable(items))
+	}
+
+	if len(orders) == 0 {
+		return templates.RenderToStdout(templates.NoOrdersTemplate, nil)
+	}
+
+	return templates.RenderToStdout(templates.OrderListTemplate, struct {
+		Orders []api.Order
+	}{
+		Orders: orders,
+	})
+}
+
+func runOrderShow(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	o, err := client.GetOrder(args[0])
+	if err != nil {
+		if api.IsNotFound(err) {
+			return withExitCode(ExitCodeNotFound, fmt.Errorf("order %s not found", args[0]))
+		}
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if format.IsStructured() {
+		item := output.FromOrder(*o)
+		return writeOutput(format, item, output.OrdersTable([]output.Order{item}))
+	}
+
+	lineItems := make([]string, len(o.LineItems))
+	for i, item := range o.LineItems {
+		lineItems[i] = fmt.Sprintf("%d → %s", item.GetQuantity(), order.FormatPreparation(item.GrindType, item.BrewingMethod))
+	}
+
+	data := struct {
+		ID               string
+		Tier             string
+		Status           string
+		CreatedOn        string
+		ExpectedShipment string
+		TotalQuantity    int
+		LineItems        []string
+	}{
+		ID:            o.ID,
+		Tier:          o.Tier,
+		Status:        o.Status,
+		CreatedOn:     utils.FormatTimestamp(o.CreatedOn),
+		TotalQuantity: o.GetTotalQuantity(),
+		LineItems:     lineItems,
+	}
+	if o.ExpectedShipmentDate != nil {
+		data.ExpectedShipment = utils.FormatTimestamp(*o.ExpectedShipmentDate)
+	}
+
+	return templates.RenderToStdout(templates.OrderShowTemplate, data)
+}
+
+// resolveOrderPlan finds the subscription tier or product referenced by an order spec
+func resolveOrderPlan(client *api.Client, spec *order.OrderSpec) (*api.AvailablePlan, error) {
+	if spec.IsSubscription() {
+		plan, err := client.GetSubscriptionPricing(spec.Tier)
+		if err != nil {
+			return nil, fmt.Errorf("unknown subscription tier %q: %w", spec.Tier, err)
+		}
+		return plan, nil
+	}
+
+	products, err := client.GetAvailableProducts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get available products: %w", err)
+	}
+	for _, product := range products {
+		if product.ID == spec.ProductID {
+			return &product, nil
+		}
+	}
+	return nil, fmt.Errorf("product %q is not available", spec.ProductID)
+}
+
+// planQuantityLimits returns the plan's min/max quantity, falling back to defaults when unset
+func planQuantityLimits(cfg *config.Config, plan api.AvailablePlan, isSubscription bool) (int, int) {
+	if !isSubscription {
+		return productQuantityLimits(plan)
+	}
+
+	minQty := plan.MinQuantity
+	maxQty := plan.MaxQuantity
+	if minQty == 0 {
+		minQty = cfg.MinQuantity
+	}
+	if maxQty == 0 {
+		maxQty = cfg.MaxQuantity
+	}
+	return minQty, maxQty
+}
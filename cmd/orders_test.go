@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hassek/bc-cli/config"
+)
+
+func TestOrderApplyRejectsBadFilesBeforeFetching(t *testing.T) {
+	const lines = `
+line_items:
+  - quantity: 2
+    grind_type: ground
+    brewing_method: espresso
+`
+	tests := []struct {
+		name    string
+		file    string
+		flags   []string
+		wantErr string
+	}{
+		{name: "Tier and product", file: "tier: explorer\nproduct_id: p1\n" + lines, wantErr: "mutually exclusive"},
+		{name: "No plan", file: lines, wantErr: "either tier or product_id"},
+		{name: "Unknown brewing method", file: "tier: explorer\n" + strings.Replace(lines, "espresso", "aeropress", 1), wantErr: "unknown brewing_method"},
+		{name: "Tier and product flags", file: "tier: explorer\n" + lines, flags: []string{"--tier", "alpine", "--product-id", "p1"}, wantErr: "mutually exclusive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempHome(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}))
+			defer server.Close()
+			t.Setenv("BASE_HOSTNAME", server.URL)
+			if err := (&config.Config{APIURL: server.URL, AccessToken: "token"}).Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(t.TempDir(), "order.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			rootCmd.SetArgs(append([]string{"order", "apply", "-f", path, "--yes"}, tt.flags...))
+			rootCmd.SetOut(io.Discard)
+			defer func() {
+				rootCmd.SetArgs(nil)
+				rootCmd.SetOut(nil)
+				for _, name := range []string{"file", "yes", "tier", "product-id"} {
+					_ = orderApplyCmd.Flags().Set(name, orderApplyCmd.Flags().Lookup(name).DefValue)
+				}
+			}()
+
+			err := rootCmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("order apply error = %v, want %q", err, tt.wantErr)
+			}
+			if code := exitCodeFor(err); code != ExitCodeUsage {
+				t.Errorf("exit code = %d, want %d", code, ExitCodeUsage)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// outputFormat returns the format selected with --output. Without the flag a
+// terminal keeps the rich rendering and pipes get an aligned table. Inside the app
+// shell the output is shown on the terminal.
+func outputFormat(cmd *cobra.Command) (output.Format, error) {
+	value, _ := cmd.Flags().GetString("output")
+	format, err := output.ParseFormat(value)
+	if err != nil {
+		return output.FormatRich, withExitCode(ExitCodeUsage, err)
+	}
+
+	if !cmd.Flags().Changed("output") && tui.ActiveShell() == nil && !term.IsTerminal(int(os.Stdout.Fd())) {
+		return output.FormatTable, nil
+	}
+	return format, nil
+}
+
+// writeOutput prints v in a structured format to stdout, or to the app shell while
+// one runs
+func writeOutput(format output.Format, v any, table output.Table) error {
+	return output.Write(tui.Output(), format, v, table)
+}
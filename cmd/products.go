@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -12,6 +13,8 @@ import (
 	"github.com/hassek/bc-cli/cmd/order"
 	"github.com/hassek/bc-cli/config"
 	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
 	"github.com/hassek/bc-cli/tui/models"
 	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/spf13/cobra"
@@ -24,8 +27,35 @@ var productsCmd = &cobra.Command{
 	RunE:  runProducts,
 }
 
+var productsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available products",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runProductsList,
+}
+
 func init() {
 	rootCmd.AddCommand(productsCmd)
+	productsCmd.AddCommand(productsListCmd)
+}
+
+func runProductsList(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	available, err := api.NewClient(cfg).GetAvailableProducts()
+	if err != nil {
+		return fmt.Errorf("failed to get available products: %w", err)
+	}
+
+	return renderPlans(format, available)
 }
 
 func runProducts(cmd *cobra.Command, args []string) error {
@@ -42,39 +72,75 @@ func runProducts(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get available products: %w", err)
 	}
 
-	if len(available) == 0 {
-		fmt.Println("No products available at this time.")
-		return nil
+	// An explicit --output lists the plans instead of opening the picker
+	if cmd.Flags().Changed("output") {
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		return renderPlans(format, available)
 	}
 
-	// Use new product picker with duck animation
-	selectedProduct, err := models.PickProduct(available)
-	if err != nil {
-		fmt.Println("\nExiting...")
-		return nil
-	}
+	return browseProducts(cfg, client, available)
+}
 
-	// User cancelled or selected exit
-	if selectedProduct == nil {
+// browseProducts lets the user pick products from available and purchase them
+func browseProducts(cfg *config.Config, client *api.Client, available []api.AvailableSubscription) error {
+	if len(available) == 0 {
+		fmt.Fprintln(tui.Output(), "No products available at this time.")
 		return nil
 	}
 
-	displayProductDetails(*selectedProduct)
+	return app.Run("Products", func() error {
+		for {
+			// Use new product picker with duck animation
+			product, err := models.PickProduct(available, cfg.IsAuthenticated() && len(available) > 1)
+			if errors.Is(err, models.ErrBatchSelected) {
+				done, err := buySeveralProducts(client, available)
+				if err != nil || done {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				fmt.Fprintln(tui.Output(), "\nExiting...")
+				return nil
+			}
 
-	// Ask if user wants to purchase (if authenticated)
-	if cfg.IsAuthenticated() {
-		fmt.Println()
-		confirmed, err := prompts.PromptConfirm(fmt.Sprintf("Would you like to purchase %s now", selectedProduct.Name))
-		if err == nil && confirmed {
-			// User wants to purchase - start order configuration flow
-			return createProductOrder(cfg, client, *selectedProduct)
+			// User cancelled or selected exit
+			if product == nil {
+				return nil
+			}
+
+			done, err := offerProduct(cfg, client, *product)
+			// Declining goes back to the picker
+			if err != nil || done {
+				return err
+			}
 		}
-	} else if !cfg.IsAuthenticated() {
-		fmt.Println("\nPlease login first to purchase:")
-		fmt.Println("  bc-cli login")
+	})
+}
+
+// offerProduct shows the product's details and offers to purchase it. It reports
+// whether the flow is done, which it isn't when the user declines.
+func offerProduct(cfg *config.Config, client *api.Client, product api.AvailableSubscription) (bool, error) {
+	displayProductDetails(product)
+
+	// Ask if user wants to purchase (if authenticated)
+	if !cfg.IsAuthenticated() {
+		fmt.Fprintln(tui.Output(), "\nPlease login first to purchase:")
+		fmt.Fprintln(tui.Output(), "  bc-cli login")
+		return false, nil
 	}
 
-	return nil
+	fmt.Fprintln(tui.Output())
+	confirmed, err := prompts.PromptConfirm(fmt.Sprintf("Would you like to purchase %s now", product.Name))
+	if err != nil || !confirmed {
+		return false, err
+	}
+
+	// User wants to purchase - start order configuration flow
+	return true, createProductOrder(cfg, client, product)
 }
 
 func displayProductDetails(product api.AvailableSubscription) {
@@ -93,7 +159,7 @@ func displayProductDetails(product api.AvailableSubscription) {
 		Price:       product.Price,
 		Description: renderedDescription,
 	}); err != nil {
-		fmt.Printf("Error displaying product details: %v\n", err)
+		fmt.Fprintf(tui.Output(), "Error displaying product details: %v\n", err)
 	}
 }
 
@@ -102,163 +168,241 @@ func createProductOrder(cfg *config.Config, client *api.Client, product api.Avai
 		return fmt.Errorf("you must be logged in to purchase. Please run 'bc-cli login' first")
 	}
 
-	fmt.Println("\n" + strings.Repeat("═", 60))
-	fmt.Println("\n  Configure Your Order")
-	fmt.Println("\n" + strings.Repeat("─", 60) + "\n")
+	fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("═", 60))
+	fmt.Fprintln(tui.Output(), "\n  Configure Your Order")
+	fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("─", 60)+"\n")
+
+	quantity, lineItems, err := configureProductOrder(product)
+	if err != nil {
+		return err
+	}
+
+	// Step 4: Show order summary
+	if err := showProductOrderSummary(product, quantity, lineItems); err != nil {
+		return err
+	}
+
+	confirmed, err := prompts.PromptConfirm("Looks good! Proceed to checkout?")
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Fprintln(tui.Output(), "\nOrder cancelled.")
+		return nil
+	}
+
+	return checkoutProductOrder(client, product, quantity, lineItems)
+}
 
-	// Use min/max quantity from backend, fallback to defaults if not set
+// productQuantityLimits returns the product's min/max quantity, falling back to the
+// defaults when the backend doesn't set them
+func productQuantityLimits(product api.AvailablePlan) (int, int) {
 	minQty := product.MinQuantity
 	maxQty := product.MaxQuantity
 	if minQty == 0 {
-		minQty = 1
+		minQty = DefaultProductMinQuantity
 	}
 	if maxQty == 0 {
-		maxQty = 10
+		maxQty = DefaultProductMaxQuantity
 	}
+	return minQty, maxQty
+}
+
+// configureProductOrder asks for the quantity and preparation of product
+func configureProductOrder(product api.AvailableSubscription) (int, []api.OrderLineItem, error) {
+	minQty, maxQty := productQuantityLimits(product)
 
 	// Step 1: Ask for quantity (number of items, not kg)
 	quantity, err := prompts.PromptQuantityInt("How many would you like to purchase?", minQty, maxQty, minQty)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
-	fmt.Printf("\n✓ Quantity: %d\n", quantity)
+	fmt.Fprintf(tui.Output(), "\n✓ Quantity: %d\n", quantity)
 
 	// Step 2: Ask for grind type
-	fmt.Println("\n  How would you like your coffee prepared?")
-	fmt.Println()
+	fmt.Fprintln(tui.Output(), "\n  How would you like your coffee prepared?")
+	fmt.Fprintln(tui.Output())
 	grindType, err := order.SelectGrindType()
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	// Show grind type confirmation
-	fmt.Println()
+	fmt.Fprintln(tui.Output())
 	if grindType == "ground" {
-		fmt.Println("✓ We'll grind these beans for you!")
+		fmt.Fprintln(tui.Output(), "✓ We'll grind these beans for you!")
 	} else {
-		fmt.Println("✓ You'll grind these beans yourself")
+		fmt.Fprintln(tui.Output(), "✓ You'll grind these beans yourself")
 	}
 
 	// Step 3: Ask for brewing method (ALWAYS)
 	brewResult, err := order.SelectBrewingMethod(grindType)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	// Confirmation message
-	fmt.Printf("\n✓ Perfect! Your order: %d x %s - ", quantity, product.Name)
+	fmt.Fprintf(tui.Output(), "\n✓ Perfect! Your order: %d x %s - ", quantity, product.Name)
 	if grindType == "whole_bean" {
-		fmt.Printf("whole beans for %s.\n", order.BrewingMethodDisplay(brewResult.Method))
+		fmt.Fprintf(tui.Output(), "whole beans for %s.\n", order.BrewingMethodDisplay(brewResult.Method))
 	} else {
 		grindDesc := order.GetGrindDescription(brewResult.Method)
-		fmt.Printf("ground for %s (%s).\n", order.BrewingMethodDisplay(brewResult.Method), grindDesc)
+		fmt.Fprintf(tui.Output(), "ground for %s (%s).\n", order.BrewingMethodDisplay(brewResult.Method), grindDesc)
 	}
 	if brewResult.Notes != "" {
-		fmt.Printf("  Notes: %s\n", brewResult.Notes)
+		fmt.Fprintf(tui.Output(), "  Notes: %s\n", brewResult.Notes)
+	}
+	fmt.Fprintln(tui.Output())
+
+	lineItems := []api.OrderLineItem{
+		{
+			Quantity:      quantity,
+			GrindType:     grindType,
+			BrewingMethod: brewResult.Method,
+			Notes:         brewResult.Notes,
+		},
 	}
-	fmt.Println()
 
-	// Step 4: Show order summary
-	if err := showProductOrderSummary(product, quantity, grindType, brewResult.Method, brewResult.Notes); err != nil {
-		return err
+	return quantity, lineItems, nil
+}
+
+// buySeveralProducts lets the user check several products, configures each of them and
+// checks them out one after another. It reports whether the flow is done, which it
+// isn't when the user cancels before checking out.
+func buySeveralProducts(client *api.Client, products []api.AvailableSubscription) (bool, error) {
+	picked, err := models.PickProducts("Check the products to buy", products)
+	if errors.Is(err, prompts.ErrUserCancelled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(picked) == 0 {
+		fmt.Fprintln(tui.Output(), "No products selected.")
+		return false, nil
 	}
 
-	confirmed, err := prompts.PromptConfirm("Looks good! Proceed to checkout?")
+	quantities := make([]int, len(picked))
+	lineItems := make([][]api.OrderLineItem, len(picked))
+	for i, product := range picked {
+		fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("═", 60))
+		fmt.Fprintf(tui.Output(), "\n  Configure %s (%d of %d)\n", product.Name, i+1, len(picked))
+		fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("─", 60)+"\n")
+
+		quantities[i], lineItems[i], err = configureProductOrder(product)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for i, product := range picked {
+		if err := showProductOrderSummary(product, quantities[i], lineItems[i]); err != nil {
+			return false, err
+		}
+	}
+
+	// Every order holds a single product, so each one gets its own checkout
+	confirmed, err := prompts.PromptConfirm(fmt.Sprintf("Looks good! Proceed to checkout for %d orders?", len(picked)))
 	if err != nil {
-		return err
+		return false, err
 	}
 	if !confirmed {
-		fmt.Println("\nOrder cancelled.")
-		return nil
+		fmt.Fprintln(tui.Output(), "\nOrders cancelled.")
+		return false, nil
+	}
+
+	for i, product := range picked {
+		fmt.Fprintf(tui.Output(), "\nOrder %d of %d: %s\n", i+1, len(picked), product.Name)
+		if err := checkoutProductOrder(client, product, quantities[i], lineItems[i]); err != nil {
+			fmt.Fprintf(tui.Output(), "✗ %v\n", err)
+		}
 	}
+	return true, nil
+}
 
-	// Step 5: Create order via API
+// checkoutProductOrder creates the order, opens checkout and waits for payment
+func checkoutProductOrder(client *api.Client, product api.AvailableSubscription, quantity int, lineItems []api.OrderLineItem) error {
+	// Create order via API
 	// For products, we use ProductID instead of Tier
-	fmt.Print("\nCreating order... ")
+	fmt.Fprint(tui.Output(), "\nCreating order... ")
 	order, err := client.CreateOrder(api.CreateOrderRequest{
 		ProductID:     product.ID,
 		TotalQuantity: quantity,
-		LineItems: []api.OrderLineItem{
-			{
-				Quantity:      quantity,
-				GrindType:     grindType,
-				BrewingMethod: brewResult.Method,
-				Notes:         brewResult.Notes,
-			},
-		},
+		LineItems:     lineItems,
 	})
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return fmt.Errorf("failed to create order: %w", err)
 	}
-	fmt.Println("✓")
+	fmt.Fprintln(tui.Output(), "✓")
 
-	// Step 6: Create checkout session
-	fmt.Print("Opening checkout in your browser... ")
+	// Create checkout session
+	fmt.Fprint(tui.Output(), "Opening checkout in your browser... ")
 	checkout, err := client.CreateCheckoutSession(order.ID)
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return fmt.Errorf("failed to create checkout session: %w", err)
 	}
-	fmt.Println("✓")
+	fmt.Fprintln(tui.Output(), "✓")
 
-	// Step 7: Open browser
+	// Open browser
 	if err := openProductBrowser(checkout.CheckoutURL); err != nil {
-		fmt.Printf("\nCouldn't open browser automatically. Please visit:\n%s\n", checkout.CheckoutURL)
+		fmt.Fprintf(tui.Output(), "\nCouldn't open browser automatically. Please visit:\n%s\n", checkout.CheckoutURL)
 	}
 
-	fmt.Printf("\nOrder created successfully!\n")
-	fmt.Printf("Order ID: %s\n\n", order.ID)
+	fmt.Fprintf(tui.Output(), "\nOrder created successfully!\n")
+	fmt.Fprintf(tui.Output(), "Order ID: %s\n\n", order.ID)
 
-	// Step 8: Wait for payment completion
-	fmt.Println("Waiting for payment confirmation...")
-	fmt.Println("(You have 5 minutes to complete the payment)")
+	// Wait for payment completion
+	fmt.Fprintln(tui.Output(), "Waiting for payment confirmation...")
+	fmt.Fprintln(tui.Output(), "(You have 5 minutes to complete the payment)")
 
 	completed := waitForProductPayment(client, order.ID, 5*60) // 5 minutes
 
 	if completed {
 		// Payment successful!
-		fmt.Println("\n" + strings.Repeat("═", 60))
-		fmt.Println("\n  🎉 Payment Successful!")
-		fmt.Println("\n" + strings.Repeat("─", 60) + "\n")
-		fmt.Printf("  Your order for %d x %s has been confirmed!\n", quantity, product.Name)
-		fmt.Println("  We'll start preparing your coffee right away.")
-		fmt.Println("\n" + strings.Repeat("═", 60) + "\n")
+		fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("═", 60))
+		fmt.Fprintln(tui.Output(), "\n  🎉 Payment Successful!")
+		fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("─", 60)+"\n")
+		fmt.Fprintf(tui.Output(), "  Your order for %d x %s has been confirmed!\n", quantity, product.Name)
+		fmt.Fprintln(tui.Output(), "  We'll start preparing your coffee right away.")
+		fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("═", 60)+"\n")
 	} else {
 		// Timeout or user didn't complete payment
-		fmt.Println("\nComplete your payment to confirm your order.")
-		fmt.Println("Your order will be processed once payment is received.")
+		fmt.Fprintln(tui.Output(), "\nComplete your payment to confirm your order.")
+		fmt.Fprintln(tui.Output(), "Your order will be processed once payment is received.")
 	}
 
 	return nil
 }
 
-func showProductOrderSummary(product api.AvailableSubscription, quantity int, grindType, brewingMethod, notes string) error {
+func showProductOrderSummary(product api.AvailableSubscription, quantity int, lineItems []api.OrderLineItem) error {
 	// Calculate total price
 	pricePerUnit, _ := strconv.ParseFloat(product.Price, 64)
 	totalPrice := pricePerUnit * float64(quantity)
 
-	fmt.Println("\n" + strings.Repeat("═", 60))
-	fmt.Println("\n  Order Summary")
-	fmt.Println("\n" + strings.Repeat("─", 60) + "\n")
+	fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("═", 60))
+	fmt.Fprintln(tui.Output(), "\n  Order Summary")
+	fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("─", 60)+"\n")
 
-	fmt.Printf("  Product:  %s\n", product.Name)
-	fmt.Printf("  Quantity: %d\n", quantity)
-	fmt.Printf("  Price:    %s %.2f\n\n", product.Currency, totalPrice)
+	fmt.Fprintf(tui.Output(), "  Product:  %s\n", product.Name)
+	fmt.Fprintf(tui.Output(), "  Quantity: %d\n", quantity)
+	fmt.Fprintf(tui.Output(), "  Price:    %s %.2f\n\n", product.Currency, totalPrice)
 
-	fmt.Println("  Preparation:")
-	if grindType == "whole_bean" {
-		fmt.Printf("    • Whole beans for %s\n", order.BrewingMethodDisplay(brewingMethod))
-	} else {
-		grindDesc := order.GetGrindDescription(brewingMethod)
-		fmt.Printf("    • Ground for %s (%s)\n", order.BrewingMethodDisplay(brewingMethod), grindDesc)
-	}
-	if notes != "" {
-		fmt.Printf("    • Notes: %s\n", notes)
+	fmt.Fprintln(tui.Output(), "  Preparation:")
+	for _, item := range lineItems {
+		if len(lineItems) > 1 {
+			fmt.Fprintf(tui.Output(), "    • %d → %s\n", item.Quantity, order.FormatPreparation(item.GrindType, item.BrewingMethod))
+		} else {
+			fmt.Fprintf(tui.Output(), "    • %s\n", order.FormatPreparation(item.GrindType, item.BrewingMethod))
+		}
+		if item.Notes != "" {
+			fmt.Fprintf(tui.Output(), "    • Notes: %s\n", item.Notes)
+		}
 	}
 
-	fmt.Println("\n" + strings.Repeat("═", 60) + "\n")
+	fmt.Fprintln(tui.Output(), "\n"+strings.Repeat("═", 60)+"\n")
 
 	return nil
 }
@@ -303,10 +447,10 @@ func waitForProductPayment(client *api.Client, orderID string, timeoutSeconds in
 			if dots > 3 {
 				dots = 1
 			}
-			fmt.Printf("\rChecking payment status%s   ", strings.Repeat(".", dots))
+			fmt.Fprintf(tui.Output(), "\rChecking payment status%s   ", strings.Repeat(".", dots))
 
 		case <-timeout:
-			fmt.Println("\r" + strings.Repeat(" ", 50)) // Clear the line
+			fmt.Fprintln(tui.Output(), "\r"+strings.Repeat(" ", 50)) // Clear the line
 			return false
 		}
 	}
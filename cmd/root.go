@@ -3,7 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
 	"github.com/spf13/cobra"
 )
 
@@ -14,32 +21,101 @@ var (
 	BuildDate = "unknown"
 )
 
+// httpCacheDir holds the cached API responses in the cache directory
+const httpCacheDir = "http"
+
 var rootCmd = &cobra.Command{
 	Use:   "bc-cli",
 	Short: "Butler Coffee CLI tool",
 	Long: `Butler Coffee CLI tool - A command line interface for managing your coffee operations.
 
 Complete documentation is available at https://github.com/hassek/bc-cli`,
-	Run: func(cmd *cobra.Command, args []string) {
+	// Execute prints the error itself so it isn't reported twice
+	SilenceErrors: true,
+	// Flags and arguments are valid by now, so later errors don't need the usage text
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+
+		if plain, _ := cmd.Flags().GetBool("plain"); plain {
+			tui.SetPlain(true)
+		}
+
+		// Broken settings shouldn't block the command, the defaults still work
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		tui.SetMouse(cfg.Mouse)
+		if err := keys.Configure(cfg.Keymap, cfg.KeyBindings); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the default keymap\n", err)
+		}
+		if err := applyTheme(cmd, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the dark theme\n", err)
+		}
+		if dir, err := config.GetTemplatesDir(); err == nil {
+			for _, err := range templates.LoadOverrides(dir) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		// Articles are indexed for search as they are fetched
+		api.OnArticlesFetched(indexArticles)
+		if noCache, _ := cmd.Flags().GetBool("no-cache"); !noCache {
+			if dir, err := config.GetCacheDir(); err == nil {
+				api.SetCache(api.NewCache(filepath.Join(dir, httpCacheDir)))
+			}
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if version flag is set
 		version, _ := cmd.Flags().GetBool("version")
 		if version {
-			fmt.Printf("bc-cli version %s\n", Version)
-			fmt.Printf("  git commit: %s\n", GitCommit)
-			fmt.Printf("  built: %s\n", BuildDate)
-			return
+			fmt.Fprintf(tui.Output(), "bc-cli version %s\n", Version)
+			fmt.Fprintf(tui.Output(), "  git commit: %s\n", GitCommit)
+			fmt.Fprintf(tui.Output(), "  built: %s\n", BuildDate)
+			return nil
 		}
-		_ = cmd.Help()
+		return runDashboard(cmd, args)
 	},
 }
 
+// applyTheme activates the theme from the config, or no colors at all with --no-color or
+// NO_COLOR (https://no-color.org)
+func applyTheme(cmd *cobra.Command, cfg *config.Config) error {
+	if noColor, _ := cmd.Flags().GetBool("no-color"); noColor || os.Getenv("NO_COLOR") != "" {
+		styles.Apply(styles.NoColor)
+		return nil
+	}
+
+	themesDir, err := config.GetThemesDir()
+	if err != nil {
+		return err
+	}
+	theme, err := styles.LoadTheme(cfg.Theme, themesDir)
+	if err != nil {
+		return err
+	}
+	styles.Apply(theme)
+	return nil
+}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	// Saving is best effort, the index is rebuilt when it's lost
+	_ = saveSearchIndex()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCodeFor(err))
 	}
 }
 
 func init() {
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information")
+	rootCmd.PersistentFlags().Bool("plain", false, "Use plain line-based prompts instead of the full-screen interface")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colors (also set by the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Fetch fresh content instead of using cached responses")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "Output format: json, yaml or table (default: rich on a terminal, table otherwise)")
+	_ = rootCmd.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(ExitCodeUsage, err)
+	})
 }
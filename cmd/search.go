@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/offline"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/search"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
+	"github.com/hassek/bc-cli/tui/models"
+	"github.com/hassek/bc-cli/tui/prompts"
+	"github.com/spf13/cobra"
+)
+
+// searchIndexFile is the search index in the cache directory. It holds the text of
+// the articles fetched so far and is updated whenever more are fetched.
+const searchIndexFile = "search-index.json"
+
+// maxSearchResults is the number of results listed by 'learn search' with --output
+const maxSearchResults = 20
+
+var learnSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search the articles",
+	Long: `Search the titles, summaries, tags and content of the articles, best matches first.
+
+On a terminal a search screen opens, starting from the query, where the results update
+as you type. With --output, or when the output isn't a terminal, the best matches are
+listed instead. The search runs on an index kept on this computer, built on first use
+and updated as you browse; use --refresh to rebuild it with the latest articles.`,
+	Args: usageArgs(cobra.ArbitraryArgs),
+	RunE: runLearnSearch,
+}
+
+func init() {
+	learnCmd.AddCommand(learnSearchCmd)
+	learnSearchCmd.Flags().Bool("refresh", false, "Rebuild the search index from the latest articles")
+}
+
+var (
+	searchIndexOnce sync.Once
+	searchIndex     *search.Index
+	searchIndexPath string
+	// searchIndexDirty is set when the index changed since it was saved
+	searchIndexDirty atomic.Bool
+)
+
+// loadSearchIndex returns the search index, loading it from the cache on first use.
+// An index that can't be loaded starts out empty.
+func loadSearchIndex() *search.Index {
+	searchIndexOnce.Do(func() {
+		searchIndex = search.NewIndex()
+		dir, err := config.GetCacheDir()
+		if err != nil {
+			return
+		}
+		searchIndexPath = filepath.Join(dir, searchIndexFile)
+		if idx, err := search.Load(searchIndexPath); err == nil {
+			searchIndex = idx
+		}
+	})
+	return searchIndex
+}
+
+// indexArticles adds fetched articles to the search index. It is called for every
+// response with articles, so the index grows as the user browses. The index is saved
+// once the articles are all fetched, by saveSearchIndex, not on every response.
+func indexArticles(articles []api.Article) {
+	docs := make([]search.Document, len(articles))
+	for i, article := range articles {
+		docs[i] = search.Document{
+			ID:      article.ID,
+			Title:   article.Title,
+			Summary: templates.MarkdownText(article.Summary),
+			Tags:    article.Tags,
+			Content: templates.MarkdownText(article.Content),
+		}
+	}
+
+	if loadSearchIndex().Add(docs...) {
+		searchIndexDirty.Store(true)
+	}
+}
+
+// saveSearchIndex saves the search index if it changed since it was last saved
+func saveSearchIndex() error {
+	if searchIndexPath == "" || !searchIndexDirty.Swap(false) {
+		return nil
+	}
+	if err := searchIndex.Save(searchIndexPath); err != nil {
+		searchIndexDirty.Store(true)
+		return err
+	}
+	return nil
+}
+
+// ensureSearchIndex builds the search index if it is empty: it indexes every article,
+// fetching the ones whose content isn't indexed yet. With refresh set, the index is
+// rebuilt, every article fetched again and the ones that are gone dropped.
+func ensureSearchIndex(kb knowledgeBase, refresh bool) error {
+	idx := loadSearchIndex()
+	if idx.Len() > 0 && !refresh {
+		return nil
+	}
+
+	if store, ok := kb.(*offline.Store); ok {
+		// The offline copy has the content of every article, so nothing is fetched
+		indexArticles(slices.Collect(maps.Values(store.Articles)))
+		return nil
+	}
+
+	// Fetching the lists indexes them through indexArticles
+	articles, err := listAllArticles(kb)
+	if err != nil {
+		return fmt.Errorf("failed to fetch articles: %w", err)
+	}
+
+	if refresh {
+		listed := make(map[string]bool, len(articles))
+		for _, article := range articles {
+			listed[article.ID] = true
+		}
+		var gone []string
+		for _, id := range idx.IDs() {
+			if !listed[id] {
+				gone = append(gone, id)
+			}
+		}
+		if len(gone) > 0 {
+			idx.Remove(gone...)
+			searchIndexDirty.Store(true)
+		}
+	}
+
+	var missing []string
+	for _, article := range articles {
+		if _, withContent := idx.Has(article.ID); refresh || !withContent {
+			missing = append(missing, article.ID)
+		}
+	}
+	for i, id := range missing {
+		fmt.Fprintf(os.Stderr, "\rIndexing articles... %d/%d", i+1, len(missing))
+		if _, err := kb.GetArticle(id); err != nil {
+			fmt.Fprintln(os.Stderr)
+			return fmt.Errorf("failed to fetch article %s: %w", id, err)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	return saveSearchIndex()
+}
+
+// listAllArticles collects the articles of every category, including those in sections
+func listAllArticles(kb knowledgeBase) ([]api.Article, error) {
+	categories, err := kb.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	var articles []api.Article
+	for _, category := range categories {
+		categoryArticles, err := kb.ListCategoryArticles(category.Slug)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, categoryArticles...)
+
+		sections, err := kb.ListCategorySections(category.Slug)
+		if err != nil {
+			return nil, err
+		}
+		for _, section := range sections {
+			sectionArticles, err := kb.ListSectionArticles(section.ID)
+			if err != nil {
+				return nil, err
+			}
+			articles = append(articles, sectionArticles...)
+		}
+	}
+
+	return articles, nil
+}
+
+func runLearnSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsStructured() && strings.TrimSpace(query) == "" {
+		return withExitCode(ExitCodeUsage, errors.New("a query is required with --output or when the output isn't a terminal"))
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := api.NewClient(cfg)
+
+	kb, err := openKnowledgeBase(cmd, client)
+	if err != nil {
+		return err
+	}
+	defer app.SetBadge("")
+
+	if err := ensureSearchIndex(kb, refresh); err != nil {
+		return err
+	}
+
+	if format.IsStructured() {
+		// A complete query, so its last word isn't taken as a prefix
+		results := loadSearchIndex().Search(query+" ", maxSearchResults)
+		items := make([]output.SearchResult, len(results))
+		for i, result := range results {
+			items[i] = output.FromSearchResult(result)
+		}
+		return writeOutput(format, items, output.SearchResultsTable(items))
+	}
+
+	return app.Run("Search", func() error {
+		return searchArticles(cfg, client, kb, query)
+	})
+}
+
+// searchArticles shows the search screen and opens the results picked until the user
+// leaves it
+func searchArticles(cfg *config.Config, client *api.Client, kb knowledgeBase, query string) error {
+	for {
+		result, lastQuery, err := models.SearchArticles(loadSearchIndex(), query)
+		if err != nil || result == nil {
+			return err
+		}
+		query = lastQuery
+
+		article, err := kb.GetArticle(result.Document.ID)
+		if err != nil {
+			fmt.Fprintf(tui.Output(), "\nError loading article: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
+			continue
+		}
+
+		action, err := viewArticleWithActions(cfg, client, kb, article)
+		if err != nil {
+			fmt.Fprintf(tui.Output(), "\nError: %v\n", err)
+			prompts.WaitForEnter("Press Enter to continue...")
+			continue
+		}
+		if action == models.ArticleActionQuit {
+			return ErrUserQuit
+		}
+	}
+}
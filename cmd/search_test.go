@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/search"
+)
+
+func TestSearchIndexIsSavedOncePerBatch(t *testing.T) {
+	searchIndexOnce.Do(func() {})
+	searchIndex = search.NewIndex()
+	searchIndexPath = filepath.Join(t.TempDir(), searchIndexFile)
+
+	for _, id := range []string{"a1", "a2", "a3"} {
+		indexArticles([]api.Article{{ID: id, Title: "Espresso " + id}})
+	}
+	if _, err := os.Stat(searchIndexPath); !os.IsNotExist(err) {
+		t.Fatalf("the index was saved while fetching articles: %v", err)
+	}
+
+	if err := saveSearchIndex(); err != nil {
+		t.Fatalf("saveSearchIndex failed: %v", err)
+	}
+	saved, err := search.Load(searchIndexPath)
+	if err != nil || saved.Len() != 3 {
+		t.Fatalf("saved index has %d articles (%v), want 3", saved.Len(), err)
+	}
+
+	// Nothing changed, so nothing is written
+	if err := os.Remove(searchIndexPath); err != nil {
+		t.Fatal(err)
+	}
+	indexArticles([]api.Article{{ID: "a1", Title: "Espresso a1"}})
+	if err := saveSearchIndex(); err != nil {
+		t.Fatalf("saveSearchIndex failed: %v", err)
+	}
+	if _, err := os.Stat(searchIndexPath); !os.IsNotExist(err) {
+		t.Errorf("an unchanged index was saved again")
+	}
+}
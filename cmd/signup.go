@@ -1,16 +1,15 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
-	"strings"
-	"syscall"
+	"io"
 
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 )
 
 var signupCmd = &cobra.Command{
@@ -25,51 +24,37 @@ func init() {
 }
 
 func runSignup(cmd *cobra.Command, args []string) error {
-	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprintln(tui.Output(), "Welcome to Butler Coffee! Let's create your account.")
+	fmt.Fprintln(tui.Output())
 
-	fmt.Println("Welcome to Butler Coffee! Let's create your account.")
-	fmt.Println()
-
-	fmt.Print("Username: ")
-	username, err := reader.ReadString('\n')
+	username, err := prompts.ReadLine("Username: ")
 	if err != nil {
 		return fmt.Errorf("failed to read username: %w", err)
 	}
-	username = strings.TrimSpace(username)
 
-	fmt.Print("Email: ")
-	email, err := reader.ReadString('\n')
+	email, err := prompts.ReadLine("Email: ")
 	if err != nil {
 		return fmt.Errorf("failed to read email: %w", err)
 	}
-	email = strings.TrimSpace(email)
 
-	fmt.Print("Password: ")
-	passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
+	password, err := prompts.ReadPassword("Password: ")
 	if err != nil {
 		return fmt.Errorf("failed to read password: %w", err)
 	}
-	password := string(passwordBytes)
-	fmt.Println()
 
-	fmt.Print("Confirm Password: ")
-	confirmPasswordBytes, err := term.ReadPassword(int(syscall.Stdin))
+	confirmPassword, err := prompts.ReadPassword("Confirm Password: ")
 	if err != nil {
 		return fmt.Errorf("failed to read password confirmation: %w", err)
 	}
-	confirmPassword := string(confirmPasswordBytes)
-	fmt.Println()
 
 	if password != confirmPassword {
 		return fmt.Errorf("passwords do not match")
 	}
 
-	fmt.Print("Invitation Code (default is empty, press Enter to skip): ")
-	code, err := reader.ReadString('\n')
-	if err != nil {
+	code, err := prompts.ReadLine("Invitation Code (default is empty, press Enter to skip): ")
+	if err != nil && !errors.Is(err, io.EOF) {
 		return fmt.Errorf("failed to read invitation code: %w", err)
 	}
-	code = strings.TrimSpace(code)
 
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -78,7 +63,7 @@ func runSignup(cmd *cobra.Command, args []string) error {
 
 	client := api.NewClient(cfg)
 
-	fmt.Println("\nCreating account...")
+	fmt.Fprintln(tui.Output(), "\nCreating account...")
 	resp, err := client.Register(api.RegisterRequest{
 		Username: username,
 		Email:    email,
@@ -89,9 +74,9 @@ func runSignup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create account: %w", err)
 	}
 
-	fmt.Println("\n✓ Account created successfully!")
-	fmt.Printf("User ID: %s\n", resp.Data.ID)
-	fmt.Println("\nYou are now logged in and ready to use Butler Coffee CLI!")
+	fmt.Fprintln(tui.Output(), "\n✓ Account created successfully!")
+	fmt.Fprintf(tui.Output(), "User ID: %s\n", resp.Data.ID)
+	fmt.Fprintln(tui.Output(), "\nYou are now logged in and ready to use Butler Coffee CLI!")
 
 	return nil
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/hassek/bc-cli/cmd/order"
 	"github.com/hassek/bc-cli/config"
 	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
 	"github.com/hassek/bc-cli/tui/models"
 	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/hassek/bc-cli/utils"
@@ -43,39 +45,68 @@ func runSubscriptions(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get available subscriptions: %w", err)
 	}
 
-	if len(available) == 0 {
-		fmt.Println("No subscription tiers available at this time.")
-		return nil
+	// An explicit --output lists the plans instead of opening the picker
+	if cmd.Flags().Changed("output") {
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		return renderPlans(format, available)
 	}
 
-	// Use new subscription picker with duck animation
-	selectedSub, err := models.PickSubscription(available)
-	if err != nil {
-		fmt.Println("\nExiting...")
-		return nil
-	}
+	return browseSubscriptions(cfg, client, available)
+}
 
-	// User cancelled or selected exit
-	if selectedSub == nil {
+// browseSubscriptions lets the user pick tiers from available and subscribe to one
+func browseSubscriptions(cfg *config.Config, client *api.Client, available []api.AvailableSubscription) error {
+	if len(available) == 0 {
+		fmt.Fprintln(tui.Output(), "No subscription tiers available at this time.")
 		return nil
 	}
 
-	displaySubscriptionDetails(*selectedSub, api.Subscription{}, cfg.IsAuthenticated())
+	return app.Run("Subscriptions", func() error {
+		for {
+			// Use new subscription picker with duck animation
+			sub, err := models.PickSubscription(available)
+			if err != nil {
+				fmt.Fprintln(tui.Output(), "\nExiting...")
+				return nil
+			}
+
+			// User cancelled or selected exit
+			if sub == nil {
+				return nil
+			}
 
-	// Ask if user wants to subscribe (if authenticated)
-	if cfg.IsAuthenticated() {
-		fmt.Println()
-		confirmed, err := prompts.PromptConfirm(fmt.Sprintf("Would you like to subscribe to %s now", selectedSub.Name))
-		if err == nil && confirmed {
-			// User wants to subscribe - start order configuration flow
-			return createOrderAndSubscribe(cfg, client, *selectedSub)
+			done, err := offerSubscription(cfg, client, *sub)
+			// Declining goes back to the picker
+			if err != nil || done {
+				return err
+			}
 		}
-	} else if !cfg.IsAuthenticated() {
-		fmt.Println("\nPlease login first to subscribe:")
-		fmt.Println("  bc-cli login")
+	})
+}
+
+// offerSubscription shows the tier's details and offers to subscribe to it. It reports
+// whether the flow is done, which it isn't when the user declines.
+func offerSubscription(cfg *config.Config, client *api.Client, sub api.AvailableSubscription) (bool, error) {
+	displaySubscriptionDetails(sub, api.Subscription{}, cfg.IsAuthenticated())
+
+	// Ask if user wants to subscribe (if authenticated)
+	if !cfg.IsAuthenticated() {
+		fmt.Fprintln(tui.Output(), "\nPlease login first to subscribe:")
+		fmt.Fprintln(tui.Output(), "  bc-cli login")
+		return false, nil
 	}
 
-	return nil
+	fmt.Fprintln(tui.Output())
+	confirmed, err := prompts.PromptConfirm(fmt.Sprintf("Would you like to subscribe to %s now", sub.Name))
+	if err != nil || !confirmed {
+		return false, err
+	}
+
+	// User wants to subscribe - start order configuration flow
+	return true, createOrderAndSubscribe(cfg, client, sub)
 }
 
 func displaySubscriptionDetails(sub api.AvailableSubscription, activeSub api.Subscription, isAuthenticated bool) {
@@ -117,7 +148,7 @@ func displaySubscriptionDetails(sub api.AvailableSubscription, activeSub api.Sub
 		Description:   renderedDescription,
 		ActiveSub:     activeData,
 	}); err != nil {
-		fmt.Printf("Error rendering template: %v\n", err)
+		fmt.Fprintf(tui.Output(), "Error rendering template: %v\n", err)
 	}
 }
 
@@ -151,7 +182,7 @@ func createOrderAndSubscribe(cfg *config.Config, client *api.Client, tier api.Av
 		return err
 	}
 
-	fmt.Printf("\n✓ Total: %d per month\n", totalQuantity)
+	fmt.Fprintf(tui.Output(), "\n✓ Total: %d per month\n", totalQuantity)
 
 	var lineItems []api.OrderLineItem
 
@@ -198,12 +229,17 @@ func createOrderAndSubscribe(cfg *config.Config, client *api.Client, tier api.Av
 		return err
 	}
 	if !confirmed {
-		fmt.Println("\nOrder cancelled.")
+		fmt.Fprintln(tui.Output(), "\nOrder cancelled.")
 		return nil
 	}
 
-	// Step 4: Create order via API
-	fmt.Print("\nCreating order... ")
+	return checkoutSubscriptionOrder(client, tier, totalQuantity, lineItems)
+}
+
+// checkoutSubscriptionOrder creates the order, opens checkout and waits for the subscription to activate
+func checkoutSubscriptionOrder(client *api.Client, tier api.AvailableSubscription, totalQuantity int, lineItems []api.OrderLineItem) error {
+	// Create order via API
+	fmt.Fprint(tui.Output(), "\nCreating order... ")
 	order, err := client.CreateOrder(api.CreateOrderRequest{
 		Tier:          tier.Tier,
 		ProductID:     tier.ID,
@@ -211,38 +247,38 @@ func createOrderAndSubscribe(cfg *config.Config, client *api.Client, tier api.Av
 		LineItems:     lineItems,
 	})
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return fmt.Errorf("failed to create order: %w", err)
 	}
-	fmt.Println("✓")
+	fmt.Fprintln(tui.Output(), "✓")
 
-	// Step 5: Create checkout session
-	fmt.Print("Opening checkout in your browser... ")
+	// Create checkout session
+	fmt.Fprint(tui.Output(), "Opening checkout in your browser... ")
 	checkout, err := client.CreateCheckoutSession(order.ID)
 	if err != nil {
-		fmt.Println("✗")
+		fmt.Fprintln(tui.Output(), "✗")
 		return fmt.Errorf("failed to create checkout session: %w", err)
 	}
-	fmt.Println("✓")
+	fmt.Fprintln(tui.Output(), "✓")
 
-	// Step 6: Open browser
+	// Open browser
 	if err := openBrowser(checkout.CheckoutURL); err != nil {
-		fmt.Printf("\nCouldn't open browser automatically. Please visit:\n%s\n", checkout.CheckoutURL)
+		fmt.Fprintf(tui.Output(), "\nCouldn't open browser automatically. Please visit:\n%s\n", checkout.CheckoutURL)
 	}
 
-	fmt.Printf("\nOrder created successfully!\n")
-	fmt.Printf("Order ID: %s\n\n", order.ID)
+	fmt.Fprintf(tui.Output(), "\nOrder created successfully!\n")
+	fmt.Fprintf(tui.Output(), "Order ID: %s\n\n", order.ID)
 
-	// Step 7: Wait for payment completion
-	fmt.Println("Waiting for payment confirmation...")
-	fmt.Printf("(You have %d minutes to complete the payment)\n", PaymentTimeoutSeconds/60)
+	// Wait for payment completion
+	fmt.Fprintln(tui.Output(), "Waiting for payment confirmation...")
+	fmt.Fprintf(tui.Output(), "(You have %d minutes to complete the payment)\n", PaymentTimeoutSeconds/60)
 
 	subscription, completed := waitForSubscriptionActivation(client, order.ID, PaymentTimeoutSeconds)
 
 	if completed && subscription != nil {
 		// Payment successful!
 		if err := templates.RenderToStdout(templates.SuccessArtTemplate, nil); err != nil {
-			fmt.Printf("Error rendering template: %v\n", err)
+			fmt.Fprintf(tui.Output(), "Error rendering template: %v\n", err)
 		}
 		if err := templates.RenderToStdout(templates.SuccessMessageTemplate, struct {
 			TotalQuantity int
@@ -251,12 +287,12 @@ func createOrderAndSubscribe(cfg *config.Config, client *api.Client, tier api.Av
 			TotalQuantity: totalQuantity,
 			TierName:      tier.Name,
 		}); err != nil {
-			fmt.Printf("Error rendering template: %v\n", err)
+			fmt.Fprintf(tui.Output(), "Error rendering template: %v\n", err)
 		}
 	} else {
 		// Timeout or user didn't complete payment
-		fmt.Println("Complete your payment to activate your subscription.")
-		fmt.Println("Your order will be processed once payment is received.")
+		fmt.Fprintln(tui.Output(), "Complete your payment to activate your subscription.")
+		fmt.Fprintln(tui.Output(), "Your order will be processed once payment is received.")
 	}
 
 	return nil
@@ -288,29 +324,13 @@ func showOrderSummary(tier api.AvailableSubscription, totalQuantity int, lineIte
 	pricePerKg, _ := strconv.ParseFloat(tier.Price, 64)
 	totalPrice := pricePerKg * float64(totalQuantity)
 
-	// Format line items for display
-	formattedItems := make([]string, len(lineItems))
-	for i, item := range lineItems {
-		if item.GrindType == "whole_bean" {
-			formattedItems[i] = fmt.Sprintf("%d → Whole beans for %s",
-				item.Quantity,
-				order.BrewingMethodDisplay(item.BrewingMethod))
-		} else {
-			grindDesc := order.GetGrindDescription(item.BrewingMethod)
-			formattedItems[i] = fmt.Sprintf("%d → Ground for %s (%s)",
-				item.Quantity,
-				order.BrewingMethodDisplay(item.BrewingMethod),
-				grindDesc)
-		}
-	}
-
-	fmt.Println(templates.RenderOrderSummary(
+	fmt.Fprintln(tui.Output(), templates.RenderOrderSummary(
 		tier.Name,
 		totalQuantity,
 		tier.Currency,
 		totalPrice,
 		tier.BillingPeriod,
-		formattedItems,
+		order.FormatLineItems(lineItems),
 	))
 	return nil
 }
@@ -346,10 +366,10 @@ func waitForSubscriptionActivation(client *api.Client, orderID string, timeoutSe
 			if dots > 3 {
 				dots = 1
 			}
-			fmt.Printf("\rChecking payment status%s   ", strings.Repeat(".", dots))
+			fmt.Fprintf(tui.Output(), "\rChecking payment status%s   ", strings.Repeat(".", dots))
 
 		case <-timeout:
-			fmt.Println("\r" + strings.Repeat(" ", 50)) // Clear the line
+			fmt.Fprintln(tui.Output(), "\r"+strings.Repeat(" ", 50)) // Clear the line
 			return nil, false
 		}
 	}
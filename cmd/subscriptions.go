@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/cmd/order"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/prompts"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var subscriptionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your subscriptions",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runSubscriptionsList,
+}
+
+var subscriptionsShowCmd = &cobra.Command{
+	Use:   "show <subscription-id>",
+	Short: "Show a subscription's details",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runSubscriptionsShow,
+}
+
+var subscriptionsPlansCmd = &cobra.Command{
+	Use:   "plans",
+	Short: "List the available subscription tiers",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runSubscriptionsPlans,
+}
+
+var subscriptionsPauseCmd = &cobra.Command{
+	Use:   "pause <subscription-id>",
+	Short: "Pause an active subscription",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runSubscriptionsPause,
+}
+
+var subscriptionsResumeCmd = &cobra.Command{
+	Use:   "resume <subscription-id>",
+	Short: "Resume a paused subscription",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runSubscriptionsResume,
+}
+
+var subscriptionsCancelCmd = &cobra.Command{
+	Use:   "cancel <subscription-id>",
+	Short: "Cancel a subscription permanently",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runSubscriptionsCancel,
+}
+
+var subscriptionsUpdateCmd = &cobra.Command{
+	Use:   "update <subscription-id>",
+	Short: "Update a subscription's quantity and preferences",
+	Long: `Update a subscription's quantity and preferences.
+
+Each --line takes method:grind:quantity[:notes]. Without --line the existing
+preferences are kept and scaled to the new quantity. Without --quantity the
+total is the sum of the lines.
+
+Examples:
+  bc-cli subscriptions update <id> --quantity 4
+  bc-cli subscriptions update <id> --line espresso:ground:2 --line v60:whole_bean:1 --yes`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: runSubscriptionsUpdate,
+}
+
+func init() {
+	subscriptionsCmd.AddCommand(subscriptionsListCmd)
+	subscriptionsCmd.AddCommand(subscriptionsShowCmd)
+	subscriptionsCmd.AddCommand(subscriptionsPlansCmd)
+	subscriptionsCmd.AddCommand(subscriptionsPauseCmd)
+	subscriptionsCmd.AddCommand(subscriptionsResumeCmd)
+	subscriptionsCmd.AddCommand(subscriptionsCancelCmd)
+	subscriptionsCmd.AddCommand(subscriptionsUpdateCmd)
+
+	for _, cmd := range []*cobra.Command{subscriptionsPauseCmd, subscriptionsResumeCmd, subscriptionsCancelCmd, subscriptionsUpdateCmd} {
+		cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	}
+
+	subscriptionsUpdateCmd.Flags().Int("quantity", 0, "New total quantity per month")
+	subscriptionsUpdateCmd.Flags().StringArray("line", nil, "Line item as method:grind:quantity[:notes] (repeatable)")
+	subscriptionsUpdateCmd.Flags().Bool("dry-run", false, "Show the changes without updating the subscription")
+	_ = subscriptionsUpdateCmd.RegisterFlagCompletionFunc("line", completeLineItem)
+}
+
+func runSubscriptionsList(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	subscriptions, err := client.ListSubscriptions()
+	if err != nil {
+		return fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	if format.IsStructured() {
+		items := make([]output.Subscription, len(subscriptions))
+		for i, subscription := range subscriptions {
+			items[i] = output.FromSubscription(subscription)
+		}
+		return writeOutput(format, items, output.SubscriptionsTable(items))
+	}
+
+	if len(subscriptions) == 0 {
+		return templates.RenderToStdout(templates.NoSubscriptionsTemplate, nil)
+	}
+
+	return templates.RenderToStdout(templates.SubscriptionListTemplate, struct {
+		Subscriptions []api.Subscription
+	}{
+		Subscriptions: subscriptions,
+	})
+}
+
+func runSubscriptionsShow(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	subscription, err := getSubscription(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if format.IsStructured() {
+		item := output.FromSubscription(*subscription)
+		return writeOutput(format, item, output.SubscriptionsTable([]output.Subscription{item}))
+	}
+
+	return templates.RenderToStdout(templates.SubscriptionShowTemplate, buildSubscriptionView(client, subscription))
+}
+
+func runSubscriptionsPlans(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	available, err := api.NewClient(cfg).GetAvailableSubscriptions()
+	if err != nil {
+		return fmt.Errorf("failed to get available subscriptions: %w", err)
+	}
+
+	return renderPlans(format, available)
+}
+
+// renderPlans prints subscription tiers or products in the requested format
+func renderPlans(format output.Format, plans []api.AvailablePlan) error {
+	if format.IsStructured() {
+		items := make([]output.Plan, len(plans))
+		for i, plan := range plans {
+			items[i] = output.FromPlan(plan)
+		}
+		return writeOutput(format, items, output.PlansTable(items))
+	}
+
+	return templates.RenderToStdout(templates.PlanListTemplate, struct {
+		Plans []api.AvailablePlan
+	}{
+		Plans: plans,
+	})
+}
+
+func runSubscriptionsPause(cmd *cobra.Command, args []string) error {
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	subscription, err := getSubscription(client, args[0])
+	if err != nil {
+		return err
+	}
+	if subscription.Status != "active" {
+		return withExitCode(ExitCodeInvalidState, fmt.Errorf("only active subscriptions can be paused (status: %s)", subscription.Status))
+	}
+
+	if err := confirmAction(cmd, "Pause", templates.PauseWarningTemplate, "Pause subscription? (You can resume it anytime)"); err != nil {
+		return err
+	}
+
+	if _, err := client.PauseSubscription(subscription.ID); err != nil {
+		return fmt.Errorf("failed to pause subscription: %w", err)
+	}
+	forgetCompletions("subscriptions")
+
+	return templates.RenderToStdout(templates.SubscriptionPausedTemplate, struct {
+		HasResumeDate bool
+		ResumeDate    string
+	}{})
+}
+
+func runSubscriptionsResume(cmd *cobra.Command, args []string) error {
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	subscription, err := getSubscription(client, args[0])
+	if err != nil {
+		return err
+	}
+	if subscription.Status != "paused" {
+		return withExitCode(ExitCodeInvalidState, fmt.Errorf("only paused subscriptions can be resumed (status: %s)", subscription.Status))
+	}
+
+	if err := confirmAction(cmd, "Resume", templates.ResumeInfoTemplate, "Resume subscription?"); err != nil {
+		return err
+	}
+
+	if _, err := client.ResumeSubscription(subscription.ID); err != nil {
+		return fmt.Errorf("failed to resume subscription: %w", err)
+	}
+	forgetCompletions("subscriptions")
+
+	return templates.RenderToStdout(templates.SubscriptionResumedTemplate, nil)
+}
+
+func runSubscriptionsCancel(cmd *cobra.Command, args []string) error {
+	_, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	subscription, err := getSubscription(client, args[0])
+	if err != nil {
+		return err
+	}
+	if subscription.Status == "cancelled" {
+		return withExitCode(ExitCodeInvalidState, fmt.Errorf("subscription is already cancelled"))
+	}
+
+	if err := confirmAction(cmd, "Cancellation", templates.CancelWarningTemplate, "Permanently cancel this subscription?"); err != nil {
+		return err
+	}
+
+	if _, err := client.CancelSubscription(subscription.ID); err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+	forgetCompletions("subscriptions")
+
+	return templates.RenderToStdout(templates.SubscriptionCancelledTemplate, nil)
+}
+
+func runSubscriptionsUpdate(cmd *cobra.Command, args []string) error {
+	quantity, _ := cmd.Flags().GetInt("quantity")
+	lines, _ := cmd.Flags().GetStringArray("line")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if quantity == 0 && len(lines) == 0 {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("nothing to update: pass --quantity and/or --line"))
+	}
+
+	var lineItems []api.OrderLineItem
+	for _, line := range lines {
+		item, err := order.ParseLineItem(line)
+		if err != nil {
+			return withExitCode(ExitCodeUsage, err)
+		}
+		lineItems = append(lineItems, item)
+	}
+
+	cfg, client, err := authenticatedClient()
+	if err != nil {
+		return err
+	}
+
+	subscription, err := getSubscription(client, args[0])
+	if err != nil {
+		return err
+	}
+	if subscription.Status == "cancelled" {
+		return withExitCode(ExitCodeInvalidState, fmt.Errorf("cancelled subscriptions cannot be updated"))
+	}
+
+	pricing, err := client.GetSubscriptionPricing(subscription.Tier)
+	if err != nil {
+		return fmt.Errorf("failed to get tier information: %w", err)
+	}
+
+	if len(lineItems) == 0 {
+		lineItems, err = order.ScalePreferences(subscription.DefaultPreferences, subscription.GetTotalQuantity(), quantity)
+		if err != nil {
+			return withExitCode(ExitCodeUsage, fmt.Errorf("invalid update: %w", err))
+		}
+	}
+
+	minQty, maxQty := planQuantityLimits(cfg, *pricing, true)
+	totalQuantity, err := order.ValidateLineItems(quantity, lineItems, minQty, maxQty)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("invalid update: %w", err))
+	}
+
+	fmt.Fprintln(tui.Output(), order.RenderPreferenceDiff(subscription, pricing, totalQuantity, lineItems))
+	fmt.Fprintln(tui.Output())
+
+	if !order.HasChanges(order.DiffPreferences(subscription.DefaultPreferences, lineItems)) && totalQuantity == subscription.GetTotalQuantity() {
+		fmt.Fprintln(tui.Output(), "Nothing to update, your preferences already match.")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Fprintln(tui.Output(), "Dry run: no changes were sent.")
+		return nil
+	}
+
+	if err := confirmAction(cmd, "Update", "", "Update subscription with these preferences?"); err != nil {
+		return err
+	}
+
+	_, err = applyPreferenceUpdate(client, subscription.ID, totalQuantity, lineItems)
+	return err
+}
+
+// authenticatedClient loads the config and returns an API client, failing with
+// ExitCodeNotAuthenticated when the user is not logged in
+func authenticatedClient() (*config.Config, *api.Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if !cfg.IsAuthenticated() {
+		return nil, nil, withExitCode(ExitCodeNotAuthenticated, errors.New("not logged in, please run: bc-cli login"))
+	}
+
+	return cfg, api.NewClient(cfg), nil
+}
+
+// getSubscription fetches a subscription, failing with ExitCodeNotFound when it doesn't exist
+func getSubscription(client *api.Client, subscriptionID string) (*api.Subscription, error) {
+	subscription, err := client.GetSubscription(subscriptionID)
+	if err != nil {
+		if api.IsNotFound(err) {
+			return nil, withExitCode(ExitCodeNotFound, fmt.Errorf("subscription %s not found", subscriptionID))
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+// confirmAction asks the user to confirm an action unless --yes was given. Without a
+// terminal to prompt on, --yes is required. A declined prompt returns ExitCodeCancelled.
+func confirmAction(cmd *cobra.Command, action, warningTemplate, label string) error {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("%s requires confirmation, pass --yes to run non-interactively", cmd.CommandPath()))
+	}
+
+	if warningTemplate != "" {
+		if err := templates.RenderToStdout(warningTemplate, nil); err != nil {
+			return err
+		}
+	}
+
+	confirmed, err := prompts.PromptConfirm(label)
+	if err != nil || !confirmed {
+		return withExitCode(ExitCodeCancelled, fmt.Errorf("%s cancelled", action))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/offline"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/app"
+	"github.com/hassek/bc-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var learnSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download the knowledge base for offline reading",
+	Long: `Download every category, section and article, so they can be read without a network
+connection. Only the articles published since the last sync are downloaded again.
+
+When the server can't be reached, 'bc-cli learn' reads the downloaded copy instead; use
+--offline to read it anyway. Bookmarks need a connection and aren't available offline.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runLearnSync,
+}
+
+func init() {
+	learnCmd.AddCommand(learnSyncCmd)
+	learnCmd.PersistentFlags().Bool("offline", false, "Read the knowledge base downloaded with 'bc-cli learn sync'")
+}
+
+// knowledgeBase is where the articles are read from: the API, or the offline copy
+type knowledgeBase interface {
+	ListCategories() ([]api.Category, error)
+	CategoryHasSections(categorySlug string) (bool, error)
+	ListCategorySections(categorySlug string) ([]api.Section, error)
+	ListCategoryArticles(categorySlug string) ([]api.Article, error)
+	ListSectionArticles(sectionID string) ([]api.Article, error)
+	GetArticle(articleID string) (*api.Article, error)
+}
+
+// readingOffline reports whether kb is the offline copy
+func readingOffline(kb knowledgeBase) bool {
+	_, ok := kb.(*offline.Store)
+	return ok
+}
+
+// openKnowledgeBase returns where the learn commands read the articles from: the API,
+// or the offline copy with --offline or when the server can't be reached and a copy
+// was synced
+func openKnowledgeBase(cmd *cobra.Command, client *api.Client) (knowledgeBase, error) {
+	forced, _ := cmd.Flags().GetBool("offline")
+	return knowledgeBaseFor(client, forced)
+}
+
+// knowledgeBaseFor returns where articles are read from, the offline copy if forced
+func knowledgeBaseFor(client *api.Client, forced bool) (knowledgeBase, error) {
+	var reachErr error
+	if !forced {
+		// The categories are needed anyway, so the check costs nothing while they are
+		// cached. Once the server is asked, only an unreachable one means reading offline.
+		_, err := client.ListCategories()
+		if err == nil || !api.IsUnreachable(err) {
+			// Other errors are left for the command to report as usual
+			return client, nil
+		}
+		reachErr = err
+	}
+
+	dir, err := config.GetOfflineDir()
+	if err != nil {
+		return nil, err
+	}
+	store, err := offline.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	if store.IsEmpty() {
+		if forced {
+			return nil, errors.New("nothing to read offline yet, run 'bc-cli learn sync' while online")
+		}
+		return nil, fmt.Errorf("failed to fetch categories: %w", reachErr)
+	}
+
+	if forced {
+		fmt.Fprintf(os.Stderr, "Reading offline, synced %s\n", utils.FormatDate(store.SyncedAt))
+	} else {
+		fmt.Fprintf(os.Stderr, "Can't reach the server, reading offline, synced %s\n", utils.FormatDate(store.SyncedAt))
+	}
+	app.SetBadge("offline")
+	return store, nil
+}
+
+func runLearnSync(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dir, err := config.GetOfflineDir()
+	if err != nil {
+		return err
+	}
+	previous, err := offline.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	// The copy is made from the latest content, not from cached responses
+	client := api.NewClient(cfg)
+	client.Cache = nil
+
+	fmt.Fprint(os.Stderr, "Fetching articles...")
+	store, stats, err := offline.Sync(client, previous, func(done, total int) {
+		fmt.Fprintf(os.Stderr, "\rDownloading articles... %d/%d", done+1, total)
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+	if err := store.Save(dir); err != nil {
+		return err
+	}
+
+	// The offline copy has the content of every article, so search covers all of them
+	indexArticles(slices.Collect(maps.Values(store.Articles)))
+
+	fmt.Fprintf(tui.Output(), "✓ Synced %d articles (%d new, %d updated, %d removed)\n",
+		len(store.Articles), stats.Added, stats.Updated, stats.Removed)
+	return nil
+}
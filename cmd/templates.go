@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hassek/bc-cli/config"
+	"github.com/hassek/bc-cli/output"
+	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/prompts"
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Customize the templates of the CLI's output",
+	Long: `Customize the templates of the CLI's output.
+
+Everything the CLI prints is rendered from Go text/template templates. A file named
+after a template in ~/.butler-coffee/templates, e.g. order-summary.tmpl, replaces the
+built-in template. Overrides are checked when the CLI starts: one that doesn't parse
+is reported and the built-in template is used instead.`,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the templates and which ones are overridden",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runTemplatesList,
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a template, its override if there is one",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runTemplatesShow,
+}
+
+var templatesEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Override a template in $EDITOR",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  runTemplatesEdit,
+}
+
+var templatesResetCmd = &cobra.Command{
+	Use:   "reset [name]",
+	Short: "Remove a template override, or all of them with --all",
+	Args:  usageArgs(cobra.MaximumNArgs(1)),
+	RunE:  runTemplatesReset,
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesEditCmd)
+	templatesCmd.AddCommand(templatesResetCmd)
+
+	templatesShowCmd.Flags().Bool("builtin", false, "Print the built-in template even if it is overridden")
+	templatesResetCmd.Flags().Bool("all", false, "Remove all template overrides")
+	templatesResetCmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+}
+
+// templateInfo is a template as listed by templates list
+type templateInfo struct {
+	Name       string `json:"name" yaml:"name"`
+	Overridden bool   `json:"overridden" yaml:"overridden"`
+	Path       string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	dir, err := config.GetTemplatesDir()
+	if err != nil {
+		return err
+	}
+
+	var infos []templateInfo
+	table := output.Table{Headers: []string{"NAME", "OVERRIDDEN", "PATH"}}
+	for _, name := range templates.Names() {
+		info := templateInfo{Name: name}
+		if path := overridePath(dir, name); fileExists(path) {
+			info.Overridden = true
+			info.Path = path
+		}
+		infos = append(infos, info)
+		table.Rows = append(table.Rows, []string{info.Name, fmt.Sprint(info.Overridden), info.Path})
+	}
+
+	if format.IsStructured() {
+		return writeOutput(format, infos, table)
+	}
+	return templates.RenderToStdout(templates.TemplateListTemplate, struct {
+		Templates []templateInfo
+	}{
+		Templates: infos,
+	})
+}
+
+func runTemplatesShow(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	text, err := builtinTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	if builtin, _ := cmd.Flags().GetBool("builtin"); !builtin {
+		dir, err := config.GetTemplatesDir()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(overridePath(dir, name))
+		if err == nil {
+			text = string(data)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read template override: %w", err)
+		}
+	}
+
+	fmt.Fprint(tui.Output(), text)
+	if !strings.HasSuffix(text, "\n") {
+		fmt.Fprintln(tui.Output())
+	}
+	return nil
+}
+
+func runTemplatesEdit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	text, err := builtinTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	dir, err := config.GetTemplatesDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	path := overridePath(dir, name)
+	if data, err := os.ReadFile(path); err == nil {
+		text = string(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read template override: %w", err)
+	}
+
+	// Edit a copy so a broken template never replaces a working one
+	draft, err := os.CreateTemp("", name+"-*"+templates.OverrideExt)
+	if err != nil {
+		return fmt.Errorf("failed to create draft: %w", err)
+	}
+	defer os.Remove(draft.Name())
+	_, err = draft.WriteString(text)
+	if closeErr := draft.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+
+	for {
+		if err := runEditor(draft.Name()); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(draft.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read draft: %w", err)
+		}
+
+		if err := templates.Validate(string(data)); err != nil {
+			fmt.Fprintf(tui.Output(), "The template doesn't parse: %v\n", err)
+			again, promptErr := prompts.PromptConfirm("Edit it again?")
+			if promptErr != nil || !again {
+				return withExitCode(ExitCodeCancelled, fmt.Errorf("template %s not saved", name))
+			}
+			continue
+		}
+
+		if string(data) == text {
+			fmt.Fprintln(tui.Output(), "No changes.")
+			return nil
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("failed to save template override: %w", err)
+		}
+		fmt.Fprintf(tui.Output(), "✓ Saved %s\n", path)
+		return nil
+	}
+}
+
+func runTemplatesReset(cmd *cobra.Command, args []string) error {
+	dir, err := config.GetTemplatesDir()
+	if err != nil {
+		return err
+	}
+
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		if len(args) > 0 {
+			return withExitCode(ExitCodeUsage, errors.New("pass a template name or --all, not both"))
+		}
+		if err := confirmAction(cmd, "Reset", "", "Remove all template overrides?"); err != nil {
+			return err
+		}
+		removed := 0
+		for _, name := range templates.Names() {
+			if err := os.Remove(overridePath(dir, name)); err == nil {
+				removed++
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove template override: %w", err)
+			}
+		}
+		if removed == 1 {
+			fmt.Fprintln(tui.Output(), "✓ Removed 1 template override")
+		} else {
+			fmt.Fprintf(tui.Output(), "✓ Removed %d template overrides\n", removed)
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		return withExitCode(ExitCodeUsage, errors.New("pass a template name or --all"))
+	}
+	name := args[0]
+	if _, err := builtinTemplate(name); err != nil {
+		return err
+	}
+	if err := os.Remove(overridePath(dir, name)); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(tui.Output(), "Template %s isn't overridden.\n", name)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to remove template override: %w", err)
+	}
+	fmt.Fprintf(tui.Output(), "✓ Template %s reset to the built-in one\n", name)
+	return nil
+}
+
+// builtinTemplate returns the built-in template called name, or a usage error
+func builtinTemplate(name string) (string, error) {
+	text, ok := templates.Builtin(name)
+	if !ok {
+		return "", withExitCode(ExitCodeUsage, fmt.Errorf("unknown template %q (see bc-cli templates list)", name))
+	}
+	return text, nil
+}
+
+func overridePath(dir, name string) string {
+	return filepath.Join(dir, name+templates.OverrideExt)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// The editor may come with arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %s: %w", fields[0], err)
+	}
+	return nil
+}
@@ -12,6 +12,12 @@ const (
 	DefaultAPIURL      = "https://api.butler.coffee"
 	ConfigDir          = ".butler-coffee"
 	ConfigFile         = "config.json"
+	CacheDir           = "cache"
+	ThemesDir          = "themes"
+	TemplatesDir       = "templates"
+	OfflineDir         = "offline"
+	BookmarksDir       = "bookmarks"
+	HistoryDir         = "history"
 	DefaultMinQuantity = 1  // Minimum quantity per month
 	DefaultMaxQuantity = 10 // Maximum quantity per month
 
@@ -25,8 +31,20 @@ type Config struct {
 	RefreshToken          string `json:"refresh_token,omitempty"`
 	ExpiresAt             string `json:"expires_at,omitempty"`
 	RefreshTokenExpiresAt string `json:"refresh_token_expires_at,omitempty"`
+	UserID                string `json:"user_id,omitempty"`
 	MinQuantity           int    `json:"min_quantity,omitempty"`
 	MaxQuantity           int    `json:"max_quantity,omitempty"`
+
+	// Keymap is the key binding preset of the TUI: default, emacs or arrows
+	Keymap string `json:"keymap,omitempty"`
+	// KeyBindings overrides single bindings of the preset, e.g. {"up": ["up", "ctrl+p"]}
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"`
+	// Mouse turns on wheel scrolling and clicking in the TUI. Prompts drawn below a
+	// command's output, outside the app shell, stay keyboard-only.
+	Mouse bool `json:"mouse,omitempty"`
+	// Theme is a built-in theme (auto, dark, light or high-contrast) or the name of a
+	// theme file in the themes directory
+	Theme string `json:"theme,omitempty"`
 }
 
 func GetAPIURL() string {
@@ -37,12 +55,95 @@ func GetAPIURL() string {
 	return DefaultAPIURL
 }
 
-func GetConfigPath() (string, error) {
+// GetThemesDir returns the directory holding user theme files
+func GetThemesDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ThemesDir), nil
+}
+
+// GetOfflineDir returns the directory holding the knowledge base synced for offline
+// reading. It isn't part of the cache, so it survives logging out.
+func GetOfflineDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, OfflineDir), nil
+}
+
+// GetBookmarksDir returns the directory holding the collections and notes of bookmarks
+// the API doesn't keep for the user with userID, so another account logging in on this
+// computer doesn't see them. Sessions from before the user ID was saved use the shared
+// directory they were kept in.
+func GetBookmarksDir(userID string) (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if userID == "" {
+		return filepath.Join(dir, BookmarksDir), nil
+	}
+	// The ID comes from the server and mustn't lead out of the bookmarks directory
+	if !filepath.IsLocal(userID) || filepath.Base(userID) != userID {
+		return "", fmt.Errorf("invalid user ID %q", userID)
+	}
+	return filepath.Join(dir, BookmarksDir, userID), nil
+}
+
+// GetHistoryDir returns the directory holding the reading history
+func GetHistoryDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, HistoryDir), nil
+}
+
+// GetTemplatesDir returns the directory holding template overrides
+func GetTemplatesDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, TemplatesDir), nil
+}
+
+// GetConfigDir returns the directory holding the config file and other local state
+func GetConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ConfigDir, ConfigFile), nil
+	return filepath.Join(home, ConfigDir), nil
+}
+
+func GetConfigPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigFile), nil
+}
+
+// GetCacheDir returns the directory for disposable cached data
+func GetCacheDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, CacheDir), nil
+}
+
+// ClearCache removes all cached data, e.g. when the user logs out
+func ClearCache() error {
+	dir, err := GetCacheDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
 }
 
 func LoadConfig() (*Config, error) {
@@ -0,0 +1,143 @@
+// Package history keeps how far the user read each article, on disk, so the reader
+// can reopen an article where they left it and list the ones read recently.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// storeVersion is the version of the on-disk format; histories of other versions are
+// ignored
+const storeVersion = 1
+
+// maxEntries is how many articles the history remembers, the least recently read ones
+// being forgotten
+const maxEntries = 200
+
+// Finished is the share of an article past which it counts as read
+const Finished = 0.99
+
+// Entry is the reading progress of an article
+type Entry struct {
+	ArticleID string `json:"article_id"`
+	Title     string `json:"title"`
+	// Offset is the line at the top of the reader, out of the Lines the article was
+	// wrapped to
+	Offset int `json:"offset"`
+	Lines  int `json:"lines"`
+	// Percent is how much of the article was read at most, from 0 to 1
+	Percent float64   `json:"percent"`
+	ReadAt  time.Time `json:"read_at"`
+}
+
+// IsFinished reports whether the article was read to the end
+func (e Entry) IsFinished() bool {
+	return e.Percent >= Finished
+}
+
+// Store is the reading history
+type Store struct {
+	Version int `json:"version"`
+	// Entries are by article ID
+	Entries map[string]Entry `json:"entries"`
+}
+
+// NewStore returns an empty history
+func NewStore() *Store {
+	return &Store{Version: storeVersion, Entries: make(map[string]Entry)}
+}
+
+// Path returns the file the history is kept in
+func Path(dir string) string {
+	return filepath.Join(dir, "reading.json")
+}
+
+// Load reads the history kept in dir. Without one, or with one of another version, it
+// returns an empty history.
+func Load(dir string) (*Store, error) {
+	data, err := os.ReadFile(Path(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewStore(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reading history: %w", err)
+	}
+
+	store := NewStore()
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse reading history: %w", err)
+	}
+	if store.Version != storeVersion || store.Entries == nil {
+		return NewStore(), nil
+	}
+	return store, nil
+}
+
+// Save writes the history to dir, replacing the previous one atomically
+func (s *Store) Save(dir string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode reading history: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "reading.*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save reading history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save reading history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save reading history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), Path(dir)); err != nil {
+		return fmt.Errorf("failed to save reading history: %w", err)
+	}
+	return nil
+}
+
+// Get returns the reading progress of an article
+func (s *Store) Get(articleID string) (Entry, bool) {
+	entry, ok := s.Entries[articleID]
+	return entry, ok
+}
+
+// Record saves the reading progress of an article, forgetting the least recently read
+// articles past maxEntries
+func (s *Store) Record(entry Entry) {
+	s.Entries[entry.ArticleID] = entry
+	if len(s.Entries) <= maxEntries {
+		return
+	}
+	for _, old := range s.Recent(0)[maxEntries:] {
+		delete(s.Entries, old.ArticleID)
+	}
+}
+
+// Recent returns the n most recently read articles, most recent first, or all of them
+// if n is 0
+func (s *Store) Recent(n int) []Entry {
+	entries := make([]Entry, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return b.ReadAt.Compare(a.ReadAt)
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
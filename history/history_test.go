@@ -0,0 +1,91 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func articleIDs(entries []Entry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ArticleID
+	}
+	return ids
+}
+
+func TestRecent(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewStore()
+	store.Record(Entry{ArticleID: "a1", ReadAt: start})
+	store.Record(Entry{ArticleID: "a2", ReadAt: start.Add(2 * time.Hour)})
+	store.Record(Entry{ArticleID: "a3", ReadAt: start.Add(time.Hour)})
+	// Reading an article again moves it up
+	store.Record(Entry{ArticleID: "a1", ReadAt: start.Add(3 * time.Hour)})
+
+	if got := articleIDs(store.Recent(0)); !slices.Equal(got, []string{"a1", "a2", "a3"}) {
+		t.Errorf("Recent(0) = %v, want a1, a2, a3", got)
+	}
+	if got := articleIDs(store.Recent(2)); !slices.Equal(got, []string{"a1", "a2"}) {
+		t.Errorf("Recent(2) = %v, want a1, a2", got)
+	}
+}
+
+func TestRecordForgetsOldest(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewStore()
+	for i := range maxEntries + 5 {
+		store.Record(Entry{ArticleID: fmt.Sprintf("a%d", i), ReadAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	if len(store.Entries) != maxEntries {
+		t.Fatalf("the history has %d entries, want %d", len(store.Entries), maxEntries)
+	}
+	for i := range 5 {
+		if _, ok := store.Get(fmt.Sprintf("a%d", i)); ok {
+			t.Errorf("a%d is still in the history, want the oldest forgotten", i)
+		}
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	if store, err := Load(dir); err != nil || len(store.Entries) != 0 {
+		t.Fatalf("Load without a history = %v, %v, want an empty one", store, err)
+	}
+
+	readAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	want := Entry{ArticleID: "a1", Title: "Espresso ratios", Offset: 40, Lines: 120, Percent: 0.5, ReadAt: readAt}
+	store := NewStore()
+	store.Record(want)
+	if err := store.Save(dir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got, ok := loaded.Get("a1")
+	if !ok || got != want {
+		t.Errorf("Get(a1) = %+v, %v, want %+v", got, ok, want)
+	}
+
+	if err := os.WriteFile(Path(dir), []byte(`{"version":99,"entries":{"a1":{}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := Load(dir); err != nil || len(loaded.Entries) != 0 {
+		t.Errorf("Load of another version = %v, %v, want an empty history", loaded, err)
+	}
+}
+
+func TestIsFinished(t *testing.T) {
+	if (Entry{Percent: 0.5}).IsFinished() {
+		t.Error("half read article is finished")
+	}
+	if !(Entry{Percent: 1}).IsFinished() {
+		t.Error("fully read article isn't finished")
+	}
+}
@@ -0,0 +1,155 @@
+// Package offline keeps a copy of the knowledge base on disk, so articles can be read
+// without a network connection. The copy is made by Sync and read through Store, which
+// answers the same content calls as the API client.
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+// storeVersion is the version of the on-disk format; copies of other versions are
+// ignored, and the next sync downloads everything again
+const storeVersion = 1
+
+// ErrNotSynced is returned for content that isn't in the offline copy
+var ErrNotSynced = errors.New("not available offline, run 'bc-cli learn sync' while online")
+
+// Store is the offline copy of the knowledge base. Articles are kept whole, with their
+// content, and listed by the category or section they are in.
+type Store struct {
+	Version    int            `json:"version"`
+	SyncedAt   time.Time      `json:"synced_at"`
+	Categories []api.Category `json:"categories"`
+	// Sections are the sections of each category, by category slug
+	Sections map[string][]api.Section `json:"sections"`
+	// CategoryArticles are the IDs of the articles in the default section of each
+	// category, by category slug
+	CategoryArticles map[string][]string `json:"category_articles"`
+	// SectionArticles are the IDs of the articles in each section, by section ID
+	SectionArticles map[string][]string `json:"section_articles"`
+	// Articles are the articles with their content, by ID
+	Articles map[string]api.Article `json:"articles"`
+}
+
+// NewStore returns an empty store
+func NewStore() *Store {
+	return &Store{
+		Version:          storeVersion,
+		Sections:         make(map[string][]api.Section),
+		CategoryArticles: make(map[string][]string),
+		SectionArticles:  make(map[string][]string),
+		Articles:         make(map[string]api.Article),
+	}
+}
+
+// Path returns the file the offline copy is kept in
+func Path(dir string) string {
+	return filepath.Join(dir, "knowledge-base.json")
+}
+
+// Load reads the offline copy kept in dir. Without one, or with one of another
+// version, it returns an empty store.
+func Load(dir string) (*Store, error) {
+	data, err := os.ReadFile(Path(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewStore(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline copy: %w", err)
+	}
+
+	store := NewStore()
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse offline copy: %w", err)
+	}
+	if store.Version != storeVersion {
+		return NewStore(), nil
+	}
+	return store, nil
+}
+
+// Save writes the store to dir, replacing the previous copy atomically so an
+// interrupted sync leaves the previous copy intact
+func (s *Store) Save(dir string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode offline copy: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create offline directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "knowledge-base.*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save offline copy: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save offline copy: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save offline copy: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), Path(dir)); err != nil {
+		return fmt.Errorf("failed to save offline copy: %w", err)
+	}
+	return nil
+}
+
+// IsEmpty reports whether nothing was synced yet
+func (s *Store) IsEmpty() bool {
+	return len(s.Categories) == 0
+}
+
+// ListCategories returns the synced categories
+func (s *Store) ListCategories() ([]api.Category, error) {
+	return s.Categories, nil
+}
+
+// CategoryHasSections reports whether a synced category has sections
+func (s *Store) CategoryHasSections(categorySlug string) (bool, error) {
+	return len(s.Sections[categorySlug]) > 0, nil
+}
+
+// ListCategorySections returns the synced sections of a category
+func (s *Store) ListCategorySections(categorySlug string) ([]api.Section, error) {
+	return s.Sections[categorySlug], nil
+}
+
+// ListCategoryArticles returns the synced articles in a category's default section
+func (s *Store) ListCategoryArticles(categorySlug string) ([]api.Article, error) {
+	return s.articles(s.CategoryArticles[categorySlug]), nil
+}
+
+// ListSectionArticles returns the synced articles in a section
+func (s *Store) ListSectionArticles(sectionID string) ([]api.Article, error) {
+	return s.articles(s.SectionArticles[sectionID]), nil
+}
+
+// GetArticle returns a synced article with its content
+func (s *Store) GetArticle(articleID string) (*api.Article, error) {
+	article, ok := s.Articles[articleID]
+	if !ok {
+		return nil, fmt.Errorf("article %s is %w", articleID, ErrNotSynced)
+	}
+	return &article, nil
+}
+
+func (s *Store) articles(ids []string) []api.Article {
+	articles := make([]api.Article, 0, len(ids))
+	for _, id := range ids {
+		if article, ok := s.Articles[id]; ok {
+			articles = append(articles, article)
+		}
+	}
+	return articles
+}
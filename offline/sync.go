@@ -0,0 +1,127 @@
+package offline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+// Source is where the knowledge base is synced from, the API client
+type Source interface {
+	ListCategories() ([]api.Category, error)
+	ListCategorySections(categorySlug string) ([]api.Section, error)
+	ListCategoryArticles(categorySlug string) ([]api.Article, error)
+	ListSectionArticles(sectionID string) ([]api.Article, error)
+	GetArticle(articleID string) (*api.Article, error)
+}
+
+// SyncStats counts what a sync did to the articles
+type SyncStats struct {
+	Added     int
+	Updated   int
+	Unchanged int
+	Removed   int
+}
+
+// Sync downloads the knowledge base from source and returns the new offline copy,
+// leaving previous as it was. Articles published at the same time as in previous are
+// kept from it instead of being downloaded again. progress, if not nil, is called
+// before each download with the number of articles downloaded so far and the number
+// to download.
+func Sync(source Source, previous *Store, progress func(done, total int)) (*Store, SyncStats, error) {
+	var stats SyncStats
+	store := NewStore()
+
+	categories, err := source.ListCategories()
+	if err != nil {
+		return nil, stats, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+	store.Categories = categories
+
+	// Walk the categories and sections first, collecting the listed articles
+	var listed []api.Article
+	for _, category := range categories {
+		articles, err := source.ListCategoryArticles(category.Slug)
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to fetch the articles of %s: %w", category.Name, err)
+		}
+		store.CategoryArticles[category.Slug] = articleIDs(articles)
+		listed = append(listed, articles...)
+
+		sections, err := source.ListCategorySections(category.Slug)
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to fetch the sections of %s: %w", category.Name, err)
+		}
+		store.Sections[category.Slug] = sections
+		for _, section := range sections {
+			articles, err := source.ListSectionArticles(section.ID)
+			if err != nil {
+				return nil, stats, fmt.Errorf("failed to fetch the articles of %s: %w", section.Name, err)
+			}
+			store.SectionArticles[section.ID] = articleIDs(articles)
+			listed = append(listed, articles...)
+		}
+	}
+
+	// Keep the articles that weren't republished, download the others
+	var download []string
+	seen := make(map[string]bool)
+	for _, article := range listed {
+		if seen[article.ID] {
+			continue
+		}
+		seen[article.ID] = true
+		if old, ok := previous.Articles[article.ID]; ok && unchanged(old, article) {
+			// Copies synced before bookmarks were dropped may still have one
+			old.IsBookmarked, old.BookmarkID = false, ""
+			store.Articles[article.ID] = old
+			stats.Unchanged++
+			continue
+		}
+		download = append(download, article.ID)
+	}
+
+	for i, id := range download {
+		if progress != nil {
+			progress(i, len(download))
+		}
+		article, err := source.GetArticle(id)
+		if err != nil {
+			return nil, stats, fmt.Errorf("failed to fetch article %s: %w", id, err)
+		}
+		// Bookmarks belong to an account and need the network, so they aren't kept
+		article.IsBookmarked, article.BookmarkID = false, ""
+		store.Articles[id] = *article
+
+		if _, ok := previous.Articles[id]; ok {
+			stats.Updated++
+		} else {
+			stats.Added++
+		}
+	}
+
+	for id := range previous.Articles {
+		if _, ok := store.Articles[id]; !ok {
+			stats.Removed++
+		}
+	}
+
+	store.SyncedAt = time.Now()
+	return store, stats, nil
+}
+
+// unchanged reports whether the listed version of an article was published at the
+// same time as the stored one, which has its content
+func unchanged(stored, listed api.Article) bool {
+	return stored.PublishedAt != nil && listed.PublishedAt != nil &&
+		*stored.PublishedAt == *listed.PublishedAt && stored.Content != ""
+}
+
+func articleIDs(articles []api.Article) []string {
+	ids := make([]string, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	return ids
+}
@@ -0,0 +1,201 @@
+package offline
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+// fakeSource serves a knowledge base from memory and records the articles fetched
+type fakeSource struct {
+	categories []api.Category
+	sections   map[string][]api.Section
+	articles   []api.Article // listed by their CategoryID, or SectionID when set
+	fetched    []string
+}
+
+func (f *fakeSource) ListCategories() ([]api.Category, error) {
+	return f.categories, nil
+}
+
+func (f *fakeSource) ListCategorySections(categorySlug string) ([]api.Section, error) {
+	return f.sections[categorySlug], nil
+}
+
+func (f *fakeSource) ListCategoryArticles(categorySlug string) ([]api.Article, error) {
+	var articles []api.Article
+	for _, a := range f.articles {
+		if a.CategoryID == categorySlug && a.SectionID == nil {
+			a.Content = "" // Lists come without content
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+func (f *fakeSource) ListSectionArticles(sectionID string) ([]api.Article, error) {
+	var articles []api.Article
+	for _, a := range f.articles {
+		if a.SectionID != nil && *a.SectionID == sectionID {
+			a.Content = ""
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+func (f *fakeSource) GetArticle(articleID string) (*api.Article, error) {
+	f.fetched = append(f.fetched, articleID)
+	for _, a := range f.articles {
+		if a.ID == articleID {
+			return &a, nil
+		}
+	}
+	return nil, &api.StatusError{StatusCode: 404, Message: "not found"}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		categories: []api.Category{{Slug: "brewing", Name: "Brewing"}, {Slug: "origins", Name: "Origins"}},
+		sections:   map[string][]api.Section{"brewing": {{ID: "espresso", Name: "Espresso"}}},
+		articles: []api.Article{
+			{ID: "a1", CategoryID: "brewing", Title: "Pour Over", Content: "Pour slowly", PublishedAt: ptr("2025-01-01"), IsBookmarked: true, BookmarkID: "b1"},
+			{ID: "a2", CategoryID: "brewing", SectionID: ptr("espresso"), Title: "Ratios", Content: "1:2", PublishedAt: ptr("2025-02-01")},
+			{ID: "a3", CategoryID: "origins", Title: "Ethiopia", Content: "Heirloom", PublishedAt: ptr("2025-03-01")},
+		},
+	}
+}
+
+func TestSync(t *testing.T) {
+	source := newFakeSource()
+	store, stats, err := Sync(source, NewStore(), nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats != (SyncStats{Added: 3}) {
+		t.Errorf("Sync() stats = %+v, want 3 added", stats)
+	}
+
+	categories, _ := store.ListCategories()
+	if len(categories) != 2 {
+		t.Errorf("ListCategories() = %d categories, want 2", len(categories))
+	}
+	if hasSections, _ := store.CategoryHasSections("brewing"); !hasSections {
+		t.Error("CategoryHasSections(brewing) = false, want true")
+	}
+	if articles, _ := store.ListCategoryArticles("brewing"); len(articles) != 1 || articles[0].ID != "a1" {
+		t.Errorf("ListCategoryArticles(brewing) = %v, want a1", articles)
+	}
+	if articles, _ := store.ListSectionArticles("espresso"); len(articles) != 1 || articles[0].ID != "a2" {
+		t.Errorf("ListSectionArticles(espresso) = %v, want a2", articles)
+	}
+
+	article, err := store.GetArticle("a1")
+	if err != nil || article.Content != "Pour slowly" {
+		t.Fatalf("GetArticle(a1) = %v, %v, want the article with its content", article, err)
+	}
+	if article.IsBookmarked || article.BookmarkID != "" {
+		t.Error("GetArticle(a1) kept the bookmark of the account that synced")
+	}
+	if _, err := store.GetArticle("missing"); !errors.Is(err, ErrNotSynced) {
+		t.Errorf("GetArticle(missing) error = %v, want ErrNotSynced", err)
+	}
+}
+
+func TestSyncIsIncremental(t *testing.T) {
+	source := newFakeSource()
+	previous, _, err := Sync(source, NewStore(), nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// a1 is republished, a3 is gone and a4 is new
+	source.articles[0].PublishedAt = ptr("2025-05-01")
+	source.articles[0].Content = "Pour slower"
+	source.articles[2] = api.Article{ID: "a4", CategoryID: "origins", Title: "Kenya", Content: "Bright", PublishedAt: ptr("2025-06-01")}
+	source.fetched = nil
+
+	var progress []int
+	store, stats, err := Sync(source, previous, func(done, total int) {
+		progress = append(progress, done, total)
+	})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if want := (SyncStats{Added: 1, Updated: 1, Unchanged: 1, Removed: 1}); stats != want {
+		t.Errorf("Sync() stats = %+v, want %+v", stats, want)
+	}
+	if !slices.Equal(source.fetched, []string{"a1", "a4"}) {
+		t.Errorf("Sync() fetched %v, want only the new and republished articles", source.fetched)
+	}
+	if !slices.Equal(progress, []int{0, 2, 1, 2}) {
+		t.Errorf("Sync() progress = %v", progress)
+	}
+	if article, _ := store.GetArticle("a1"); article == nil || article.Content != "Pour slower" {
+		t.Errorf("GetArticle(a1) = %v, want the republished content", article)
+	}
+	if _, err := store.GetArticle("a3"); err == nil {
+		t.Error("GetArticle(a3) found an article that is gone")
+	}
+	if _, err := previous.GetArticle("a3"); err != nil {
+		t.Errorf("Sync() changed the previous copy: %v", err)
+	}
+}
+
+func TestSyncDropsBookmarksOfUnchangedArticles(t *testing.T) {
+	source := newFakeSource()
+	// A copy synced when bookmarks were still kept
+	previous := NewStore()
+	for _, article := range source.articles {
+		article.IsBookmarked, article.BookmarkID = true, "b-"+article.ID
+		previous.Articles[article.ID] = article
+	}
+
+	store, stats, err := Sync(source, previous, nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if stats.Unchanged != 3 {
+		t.Fatalf("Sync() stats = %+v, want the articles unchanged", stats)
+	}
+	for id, article := range store.Articles {
+		if article.IsBookmarked || article.BookmarkID != "" {
+			t.Errorf("article %s kept bookmark %q", id, article.BookmarkID)
+		}
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	empty, err := Load(dir)
+	if err != nil || !empty.IsEmpty() {
+		t.Fatalf("Load() without a copy = %v, %v, want an empty store", empty, err)
+	}
+
+	store, _, err := Sync(newFakeSource(), NewStore(), nil)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if err := store.Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.IsEmpty() || !loaded.SyncedAt.Equal(store.SyncedAt) {
+		t.Errorf("Load() = %+v, want the saved copy", loaded)
+	}
+	if article, err := loaded.GetArticle("a3"); err != nil || article.Content != "Heirloom" {
+		t.Errorf("GetArticle(a3) = %v, %v after loading", article, err)
+	}
+}
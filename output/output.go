@@ -0,0 +1,92 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format selects how command results are printed
+type Format string
+
+const (
+	// FormatRich is the default human-oriented rendering (templates, pickers)
+	FormatRich  Format = ""
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatTable Format = "table"
+)
+
+// Formats lists the values accepted by --output
+var Formats = []string{string(FormatJSON), string(FormatYAML), string(FormatTable)}
+
+// ParseFormat validates an --output value
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(value)) {
+	case FormatRich:
+		return FormatRich, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML:
+		return FormatYAML, nil
+	case FormatTable:
+		return FormatTable, nil
+	}
+	return FormatRich, fmt.Errorf("invalid output format %q (expected one of: %s)", value, strings.Join(Formats, ", "))
+}
+
+// IsStructured reports whether the format replaces the rich rendering
+func (f Format) IsStructured() bool {
+	return f != FormatRich
+}
+
+// Table is a simple grid of strings rendered as aligned columns
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// Write encodes v as JSON or YAML, or renders table for FormatTable
+func Write(w io.Writer, format Format, v any, table Table) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatTable:
+		return WriteTable(w, table)
+	}
+	return fmt.Errorf("output format %q has no structured rendering", format)
+}
+
+// WriteTable renders a table with columns aligned by tabwriter
+func WriteTable(w io.Writer, table Table) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(table.Headers) > 0 {
+		if _, err := fmt.Fprintln(tw, strings.Join(table.Headers, "\t")); err != nil {
+			return err
+		}
+	}
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// Tabs and newlines would break the column layout
+			cells[i] = strings.Join(strings.Fields(cell), " ")
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
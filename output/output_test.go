@@ -0,0 +1,143 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Format
+		wantErr bool
+	}{
+		{value: "", want: FormatRich},
+		{value: "json", want: FormatJSON},
+		{value: "YAML", want: FormatYAML},
+		{value: "table", want: FormatTable},
+		{value: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseFormat(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteJSONSchema(t *testing.T) {
+	started := "2025-01-15T10:00:00Z"
+	subscription := FromSubscription(api.Subscription{
+		ID:              "sub-1",
+		Tier:            "explorer",
+		Status:          "active",
+		StartedAt:       &started,
+		DefaultQuantity: 2,
+		DefaultPreferences: []api.SubscriptionPreference{
+			{ID: "pref-1", Quantity: 2, GrindType: "ground", BrewingMethod: "espresso"},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, []Subscription{subscription}, Table{}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 subscription, got %d", len(decoded))
+	}
+	for _, key := range []string{"id", "tier", "status", "total_quantity", "started_at", "expires_at", "created_on", "preferences"} {
+		if _, ok := decoded[0][key]; !ok {
+			t.Errorf("expected key %q in JSON output", key)
+		}
+	}
+	if decoded[0]["expires_at"] != nil {
+		t.Errorf("expected expires_at to be null, got %v", decoded[0]["expires_at"])
+	}
+}
+
+func TestWriteYAMLEmptyLists(t *testing.T) {
+	plan := FromPlan(api.AvailablePlan{ID: "plan-1", Name: "Explorer"})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatYAML, plan, Table{}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid YAML: %v", err)
+	}
+	if features, ok := decoded["features"].([]any); !ok || len(features) != 0 {
+		t.Errorf("expected an empty features list, got %#v", decoded["features"])
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	table := ArticlesTable([]Article{
+		{ID: "a1", Title: "Espresso\tBasics", Author: "Ana", ReadTimeMinutes: 5},
+		{ID: "a22", Title: "Grinders", Author: "Bob", ReadTimeMinutes: 12},
+	})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatTable, nil, table); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "ID ") || !strings.Contains(lines[0], "READ TIME") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "Espresso Basics") {
+		t.Errorf("expected tabs in cells to be collapsed, got %q", lines[1])
+	}
+
+	// Columns are aligned: the title column starts at the same offset on every line
+	titleCol := strings.Index(lines[0], "TITLE")
+	if strings.Index(lines[1], "Espresso") != titleCol || strings.Index(lines[2], "Grinders") != titleCol {
+		t.Errorf("expected aligned columns, got:\n%s", buf.String())
+	}
+}
+
+func TestFromArticleTags(t *testing.T) {
+	article := FromArticle(api.Article{ID: "a1", Tags: "espresso, grind ,,beans"})
+
+	want := []string{"espresso", "grind", "beans"}
+	if len(article.Tags) != len(want) {
+		t.Fatalf("expected tags %v, got %v", want, article.Tags)
+	}
+	for i := range want {
+		if article.Tags[i] != want[i] {
+			t.Errorf("expected tag %q, got %q", want[i], article.Tags[i])
+		}
+	}
+
+	if empty := FromArticle(api.Article{}); empty.Tags == nil {
+		t.Error("expected empty tags to encode as an empty list")
+	}
+}
@@ -0,0 +1,343 @@
+package output
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/history"
+	"github.com/hassek/bc-cli/search"
+)
+
+// The types below are the stable JSON/YAML schemas emitted by --output. They are
+// decoupled from the API response types so API changes don't silently break scripts.
+
+// LineItem describes how part of an order or subscription is prepared
+type LineItem struct {
+	Quantity      int    `json:"quantity" yaml:"quantity"`
+	GrindType     string `json:"grind_type" yaml:"grind_type"`
+	BrewingMethod string `json:"brewing_method" yaml:"brewing_method"`
+	Notes         string `json:"notes,omitempty" yaml:"notes,omitempty"`
+}
+
+// Subscription is the output schema for a subscription
+type Subscription struct {
+	ID            string     `json:"id" yaml:"id"`
+	Tier          string     `json:"tier" yaml:"tier"`
+	Status        string     `json:"status" yaml:"status"`
+	TotalQuantity int        `json:"total_quantity" yaml:"total_quantity"`
+	StartedAt     *string    `json:"started_at" yaml:"started_at"`
+	ExpiresAt     *string    `json:"expires_at" yaml:"expires_at"`
+	CreatedOn     string     `json:"created_on" yaml:"created_on"`
+	Preferences   []LineItem `json:"preferences" yaml:"preferences"`
+}
+
+// Order is the output schema for an order
+type Order struct {
+	ID                   string     `json:"id" yaml:"id"`
+	Tier                 string     `json:"tier" yaml:"tier"`
+	Status               string     `json:"status" yaml:"status"`
+	TotalQuantity        int        `json:"total_quantity" yaml:"total_quantity"`
+	ExpectedShipmentDate *string    `json:"expected_shipment_date" yaml:"expected_shipment_date"`
+	CreatedOn            string     `json:"created_on" yaml:"created_on"`
+	LineItems            []LineItem `json:"line_items" yaml:"line_items"`
+}
+
+// Plan is the output schema for subscription tiers and products
+type Plan struct {
+	ID             string   `json:"id" yaml:"id"`
+	Tier           string   `json:"tier" yaml:"tier"`
+	Name           string   `json:"name" yaml:"name"`
+	Summary        string   `json:"summary" yaml:"summary"`
+	Price          string   `json:"price" yaml:"price"`
+	Currency       string   `json:"currency" yaml:"currency"`
+	BillingPeriod  string   `json:"billing_period" yaml:"billing_period"`
+	IsSubscription bool     `json:"is_subscription" yaml:"is_subscription"`
+	MinQuantity    int      `json:"min_quantity" yaml:"min_quantity"`
+	MaxQuantity    int      `json:"max_quantity" yaml:"max_quantity"`
+	Features       []string `json:"features" yaml:"features"`
+}
+
+// Category is the output schema for a learn category
+type Category struct {
+	ID          string `json:"id" yaml:"id"`
+	Slug        string `json:"slug" yaml:"slug"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// Article is the output schema for an article, without its content
+type Article struct {
+	ID              string   `json:"id" yaml:"id"`
+	CategoryID      string   `json:"category_id" yaml:"category_id"`
+	SectionID       *string  `json:"section_id" yaml:"section_id"`
+	Title           string   `json:"title" yaml:"title"`
+	Summary         string   `json:"summary" yaml:"summary"`
+	Author          string   `json:"author" yaml:"author"`
+	ReadTimeMinutes int      `json:"read_time_minutes" yaml:"read_time_minutes"`
+	Tags            []string `json:"tags" yaml:"tags"`
+	PublishedAt     *string  `json:"published_at" yaml:"published_at"`
+	IsBookmarked    bool     `json:"is_bookmarked" yaml:"is_bookmarked"`
+}
+
+// Bookmark is the output schema for a bookmarked article
+type Bookmark struct {
+	ID         string  `json:"id" yaml:"id"`
+	CreatedAt  string  `json:"created_at" yaml:"created_at"`
+	Collection string  `json:"collection" yaml:"collection"`
+	Note       string  `json:"note" yaml:"note"`
+	Article    Article `json:"article" yaml:"article"`
+}
+
+// Collection is the output schema for a collection of bookmarks
+type Collection struct {
+	Name      string `json:"name" yaml:"name"`
+	Bookmarks int    `json:"bookmarks" yaml:"bookmarks"`
+}
+
+// ReadingProgress is the output schema for an article in the reading history
+type ReadingProgress struct {
+	ArticleID string `json:"article_id" yaml:"article_id"`
+	Title     string `json:"title" yaml:"title"`
+	Percent   int    `json:"percent" yaml:"percent"` // How much was read, from 0 to 100
+	ReadAt    string `json:"read_at" yaml:"read_at"`
+}
+
+// SearchResult is the output schema for an article matching a search, best first
+type SearchResult struct {
+	ID      string   `json:"id" yaml:"id"`
+	Title   string   `json:"title" yaml:"title"`
+	Summary string   `json:"summary" yaml:"summary"`
+	Tags    []string `json:"tags" yaml:"tags"`
+	Score   float64  `json:"score" yaml:"score"`
+	Snippet string   `json:"snippet" yaml:"snippet"`
+}
+
+// FromSubscription converts an API subscription to its output schema
+func FromSubscription(s api.Subscription) Subscription {
+	preferences := make([]LineItem, len(s.DefaultPreferences))
+	for i, pref := range s.DefaultPreferences {
+		preferences[i] = LineItem{
+			Quantity:      pref.GetQuantity(),
+			GrindType:     pref.GrindType,
+			BrewingMethod: pref.BrewingMethod,
+			Notes:         pref.Notes,
+		}
+	}
+
+	return Subscription{
+		ID:            s.ID,
+		Tier:          s.Tier,
+		Status:        s.Status,
+		TotalQuantity: s.GetTotalQuantity(),
+		StartedAt:     s.StartedAt,
+		ExpiresAt:     s.ExpiresAt,
+		CreatedOn:     s.CreatedOn,
+		Preferences:   preferences,
+	}
+}
+
+// FromOrder converts an API order to its output schema
+func FromOrder(o api.Order) Order {
+	lineItems := make([]LineItem, len(o.LineItems))
+	for i, item := range o.LineItems {
+		lineItems[i] = LineItem{
+			Quantity:      item.GetQuantity(),
+			GrindType:     item.GrindType,
+			BrewingMethod: item.BrewingMethod,
+			Notes:         item.Notes,
+		}
+	}
+
+	return Order{
+		ID:                   o.ID,
+		Tier:                 o.Tier,
+		Status:               o.Status,
+		TotalQuantity:        o.GetTotalQuantity(),
+		ExpectedShipmentDate: o.ExpectedShipmentDate,
+		CreatedOn:            o.CreatedOn,
+		LineItems:            lineItems,
+	}
+}
+
+// FromPlan converts an API plan (tier or product) to its output schema
+func FromPlan(p api.AvailablePlan) Plan {
+	features := p.Features
+	if features == nil {
+		features = []string{}
+	}
+
+	return Plan{
+		ID:             p.ID,
+		Tier:           p.Tier,
+		Name:           p.Name,
+		Summary:        p.Summary,
+		Price:          p.Price,
+		Currency:       p.Currency,
+		BillingPeriod:  p.BillingPeriod,
+		IsSubscription: p.IsSubscription,
+		MinQuantity:    p.MinQuantity,
+		MaxQuantity:    p.MaxQuantity,
+		Features:       features,
+	}
+}
+
+// FromCategory converts an API category to its output schema
+func FromCategory(c api.Category) Category {
+	return Category{
+		ID:          c.ID,
+		Slug:        c.Slug,
+		Name:        c.Name,
+		Description: c.Description,
+	}
+}
+
+// FromArticle converts an API article to its output schema
+func FromArticle(a api.Article) Article {
+	return Article{
+		ID:              a.ID,
+		CategoryID:      a.CategoryID,
+		SectionID:       a.SectionID,
+		Title:           a.Title,
+		Summary:         a.Summary,
+		Author:          a.Author,
+		ReadTimeMinutes: a.ReadTime,
+		Tags:            splitTags(a.Tags),
+		PublishedAt:     a.PublishedAt,
+		IsBookmarked:    a.IsBookmarked,
+	}
+}
+
+// FromBookmark converts an API bookmark to its output schema
+func FromBookmark(b api.Bookmark) Bookmark {
+	return Bookmark{
+		ID:         b.ID,
+		CreatedAt:  b.CreatedAt,
+		Collection: b.Collection,
+		Note:       b.Note,
+		Article:    FromArticle(b.Article),
+	}
+}
+
+// FromReadingProgress converts a reading history entry to its output schema
+func FromReadingProgress(e history.Entry) ReadingProgress {
+	return ReadingProgress{
+		ArticleID: e.ArticleID,
+		Title:     e.Title,
+		Percent:   int(math.Round(e.Percent * 100)),
+		ReadAt:    e.ReadAt.Format(time.RFC3339),
+	}
+}
+
+// FromSearchResult converts a search result to its output schema
+func FromSearchResult(r search.Result) SearchResult {
+	return SearchResult{
+		ID:      r.Document.ID,
+		Title:   r.Document.Title,
+		Summary: r.Document.Summary,
+		Tags:    splitTags(r.Document.Tags),
+		Score:   r.Score,
+		Snippet: r.Snippet,
+	}
+}
+
+// splitTags splits comma-separated tags, never returning nil so they encode as a list
+func splitTags(list string) []string {
+	tags := []string{}
+	for tag := range strings.SplitSeq(list, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SubscriptionsTable lays out subscriptions as table rows
+func SubscriptionsTable(subscriptions []Subscription) Table {
+	table := Table{Headers: []string{"ID", "TIER", "STATUS", "QUANTITY"}}
+	for _, s := range subscriptions {
+		table.Rows = append(table.Rows, []string{s.ID, s.Tier, s.Status, strconv.Itoa(s.TotalQuantity)})
+	}
+	return table
+}
+
+// OrdersTable lays out orders as table rows
+func OrdersTable(orders []Order) Table {
+	table := Table{Headers: []string{"ID", "TIER", "STATUS", "QUANTITY", "CREATED"}}
+	for _, o := range orders {
+		table.Rows = append(table.Rows, []string{o.ID, o.Tier, o.Status, strconv.Itoa(o.TotalQuantity), o.CreatedOn})
+	}
+	return table
+}
+
+// PlansTable lays out tiers or products as table rows
+func PlansTable(plans []Plan) Table {
+	table := Table{Headers: []string{"ID", "TIER", "NAME", "PRICE", "PERIOD", "QUANTITY"}}
+	for _, p := range plans {
+		table.Rows = append(table.Rows, []string{
+			p.ID,
+			p.Tier,
+			p.Name,
+			p.Price + " " + p.Currency,
+			p.BillingPeriod,
+			strconv.Itoa(p.MinQuantity) + "-" + strconv.Itoa(p.MaxQuantity),
+		})
+	}
+	return table
+}
+
+// CategoriesTable lays out categories as table rows
+func CategoriesTable(categories []Category) Table {
+	table := Table{Headers: []string{"SLUG", "NAME", "DESCRIPTION"}}
+	for _, c := range categories {
+		table.Rows = append(table.Rows, []string{c.Slug, c.Name, c.Description})
+	}
+	return table
+}
+
+// ArticlesTable lays out articles as table rows
+func ArticlesTable(articles []Article) Table {
+	table := Table{Headers: []string{"ID", "TITLE", "AUTHOR", "READ TIME"}}
+	for _, a := range articles {
+		table.Rows = append(table.Rows, []string{a.ID, a.Title, a.Author, strconv.Itoa(a.ReadTimeMinutes) + " min"})
+	}
+	return table
+}
+
+// BookmarksTable lays out bookmarks as table rows
+func BookmarksTable(bookmarks []Bookmark) Table {
+	table := Table{Headers: []string{"ID", "ARTICLE", "TITLE", "COLLECTION", "CREATED"}}
+	for _, b := range bookmarks {
+		table.Rows = append(table.Rows, []string{b.ID, b.Article.ID, b.Article.Title, b.Collection, b.CreatedAt})
+	}
+	return table
+}
+
+// CollectionsTable lays out collections of bookmarks as table rows
+func CollectionsTable(collections []Collection) Table {
+	table := Table{Headers: []string{"NAME", "BOOKMARKS"}}
+	for _, c := range collections {
+		table.Rows = append(table.Rows, []string{c.Name, strconv.Itoa(c.Bookmarks)})
+	}
+	return table
+}
+
+// ReadingHistoryTable lays out the reading history as table rows
+func ReadingHistoryTable(entries []ReadingProgress) Table {
+	table := Table{Headers: []string{"ARTICLE", "TITLE", "READ", "LAST READ"}}
+	for _, e := range entries {
+		table.Rows = append(table.Rows, []string{e.ArticleID, e.Title, strconv.Itoa(e.Percent) + "%", e.ReadAt})
+	}
+	return table
+}
+
+// SearchResultsTable lays out search results as table rows
+func SearchResultsTable(results []SearchResult) Table {
+	table := Table{Headers: []string{"ID", "TITLE", "SCORE", "MATCH"}}
+	for _, r := range results {
+		table.Rows = append(table.Rows, []string{r.ID, r.Title, strconv.FormatFloat(r.Score, 'f', 2, 64), r.Snippet})
+	}
+	return table
+}
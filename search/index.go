@@ -0,0 +1,347 @@
+// Package search is a full-text index over the knowledge base articles, ranked with
+// BM25. It is kept on disk and updated as articles are fetched.
+package search
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Ranking parameters. A match in a title says more about an article than one in its
+// body, so the fields are weighted, BM25F style.
+const (
+	k1 = 1.2
+	b  = 0.75
+
+	titleWeight   = 3.0
+	tagsWeight    = 2.0
+	summaryWeight = 1.5
+	contentWeight = 1.0
+)
+
+// indexVersion is the version of the on-disk format; files of other versions are
+// ignored and the index is rebuilt
+const indexVersion = 1
+
+// Document is an article as it is indexed, its text without markup
+type Document struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Tags    string `json:"tags"`
+	Content string `json:"content"`
+}
+
+// Result is a document matching a search and its score, higher for better matches
+type Result struct {
+	Document Document
+	Score    float64
+	// Snippet is the passage of the summary or content that matches best
+	Snippet string
+}
+
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldTags
+	fieldSummary
+	fieldContent
+	fieldCount
+)
+
+var fieldWeights = [fieldCount]float64{titleWeight, tagsWeight, summaryWeight, contentWeight}
+
+// entry is an indexed document with the frequencies of its terms by field
+type entry struct {
+	doc     Document
+	freqs   [fieldCount]map[string]int
+	lengths [fieldCount]int
+}
+
+func newEntry(doc Document) *entry {
+	e := &entry{doc: doc}
+	for f, text := range [fieldCount]string{doc.Title, doc.Tags, doc.Summary, doc.Content} {
+		e.freqs[f] = make(map[string]int)
+		for _, term := range terms(text) {
+			e.freqs[f][term]++
+			e.lengths[f]++
+		}
+	}
+	return e
+}
+
+// Index is an inverted index of documents. It is safe for concurrent use.
+type Index struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+	// postings lists the documents each term appears in, in any field
+	postings map[string]map[string]bool
+	// lengths sums the field lengths of all documents, for the average lengths
+	lengths [fieldCount]int
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{
+		entries:  make(map[string]*entry),
+		postings: make(map[string]map[string]bool),
+	}
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// IDs returns the IDs of the indexed documents
+func (idx *Index) IDs() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	ids := make([]string, 0, len(idx.entries))
+	for id := range idx.entries {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Documents returns the indexed documents
+func (idx *Index) Documents() []Document {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	docs := make([]Document, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		docs = append(docs, e.doc)
+	}
+	return docs
+}
+
+// Has reports whether the document with id is indexed, and with its content
+func (idx *Index) Has(id string) (indexed, withContent bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	e, ok := idx.entries[id]
+	return ok, ok && e.doc.Content != ""
+}
+
+// Add indexes documents, replacing the indexed versions of the same documents, and
+// reports whether the index changed. Article lists come without content, so a document
+// without content keeps the content indexed before.
+func (idx *Index) Add(docs ...Document) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	changed := false
+	for _, doc := range docs {
+		if old, ok := idx.entries[doc.ID]; ok {
+			if doc.Content == "" {
+				doc.Content = old.doc.Content
+			}
+			if doc == old.doc {
+				continue
+			}
+			idx.remove(old)
+		}
+		idx.insert(newEntry(doc))
+		changed = true
+	}
+	return changed
+}
+
+// Remove drops the documents with ids from the index
+func (idx *Index) Remove(ids ...string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, id := range ids {
+		if e, ok := idx.entries[id]; ok {
+			idx.remove(e)
+		}
+	}
+}
+
+func (idx *Index) insert(e *entry) {
+	idx.entries[e.doc.ID] = e
+	for f := range fieldCount {
+		idx.lengths[f] += e.lengths[f]
+		for term := range e.freqs[f] {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[string]bool)
+			}
+			idx.postings[term][e.doc.ID] = true
+		}
+	}
+}
+
+func (idx *Index) remove(e *entry) {
+	delete(idx.entries, e.doc.ID)
+	for f := range fieldCount {
+		idx.lengths[f] -= e.lengths[f]
+		for term := range e.freqs[f] {
+			delete(idx.postings[term], e.doc.ID)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+}
+
+// Search returns up to limit documents matching text, best first. Every word of text
+// adds to the score of the documents it appears in, so documents don't need all of
+// them; the last word also matches longer words starting with it unless text ends
+// with a space or punctuation. A limit of zero or less returns all matches.
+func (idx *Index) Search(text string, limit int) []Result {
+	q := parseQuery(text)
+	if len(q.terms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	n := float64(len(idx.entries))
+	var avgLengths [fieldCount]float64
+	for f := range fieldCount {
+		avgLengths[f] = max(float64(idx.lengths[f])/n, 1)
+	}
+
+	scores := make(map[string]float64)
+	for i := range q.terms {
+		for _, term := range idx.expand(q, i) {
+			ids := idx.postings[term]
+			idf := math.Log(1 + (n-float64(len(ids))+0.5)/(float64(len(ids))+0.5))
+			for id := range ids {
+				e := idx.entries[id]
+				// Term frequency weighted by field and normalized by field length
+				var tf float64
+				for f := range fieldCount {
+					if freq := e.freqs[f][term]; freq > 0 {
+						norm := 1 - b + b*float64(e.lengths[f])/avgLengths[f]
+						tf += fieldWeights[f] * float64(freq) / norm
+					}
+				}
+				scores[id] += idf * tf / (k1 + tf)
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{Document: idx.entries[id].doc, Score: score})
+	}
+	slices.SortFunc(results, func(a, b Result) int {
+		// Ties in a stable order
+		return cmp.Or(cmp.Compare(b.Score, a.Score),
+			cmp.Compare(a.Document.Title, b.Document.Title), cmp.Compare(a.Document.ID, b.Document.ID))
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	for i := range results {
+		results[i].Snippet = bestSnippet(results[i].Document, q)
+	}
+	return results
+}
+
+// bestSnippet returns the passage of the content that matches q, or else the start of
+// the summary, the content having nothing better to show
+func bestSnippet(doc Document, q query) string {
+	content, matches := snippet(doc.Content, q)
+	if matches > 0 || doc.Summary == "" {
+		return content
+	}
+	summary, _ := snippet(doc.Summary, q)
+	return summary
+}
+
+// expand returns the indexed terms matching query term i: the term itself, or every
+// term it is a prefix of when it is the word being typed
+func (idx *Index) expand(q query, i int) []string {
+	if !q.prefix || i != len(q.terms)-1 {
+		if _, ok := idx.postings[q.terms[i]]; ok {
+			return []string{q.terms[i]}
+		}
+		return nil
+	}
+	var expanded []string
+	for term := range idx.postings {
+		if q.matches(i, term) {
+			expanded = append(expanded, term)
+		}
+	}
+	return expanded
+}
+
+// indexFile is the on-disk format of an index: its documents, which are indexed again
+// when it is loaded
+type indexFile struct {
+	Version   int        `json:"version"`
+	Documents []Document `json:"documents"`
+}
+
+// Load reads the index saved at path. A missing file, or one saved by another
+// version, gives an empty index.
+func Load(path string) (*Index, error) {
+	idx := NewIndex()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return idx, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return idx, fmt.Errorf("failed to parse search index: %w", err)
+	}
+	if file.Version == indexVersion {
+		idx.Add(file.Documents...)
+	}
+	return idx, nil
+}
+
+// Save writes the index to path, replacing the file atomically so a concurrent Load
+// never sees half of it
+func (idx *Index) Save(path string) error {
+	idx.mu.RLock()
+	file := indexFile{Version: indexVersion, Documents: make([]Document, 0, len(idx.entries))}
+	for _, e := range idx.entries {
+		file.Documents = append(file.Documents, e.doc)
+	}
+	idx.mu.RUnlock()
+	slices.SortFunc(file.Documents, func(a, b Document) int { return cmp.Compare(a.ID, b.ID) })
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testDocuments = []Document{
+	{
+		ID:      "espresso",
+		Title:   "Dialing In Espresso",
+		Summary: "Get the grind right for a balanced shot",
+		Tags:    "espresso, grind",
+		Content: "Espresso needs a fine grind. Adjust the grinder in small steps and taste every shot until the espresso is sweet.",
+	},
+	{
+		ID:      "pour-over",
+		Title:   "Pour Over Basics",
+		Summary: "Brew a clean cup by hand",
+		Tags:    "brewing, filter",
+		Content: "A medium grind and a slow, steady pour make a clean cup. Bloom the coffee with a little water first.",
+	},
+	{
+		ID:      "storage",
+		Title:   "Storing Beans",
+		Summary: "Keep your coffee fresh",
+		Tags:    "beans",
+		Content: "Store beans in an airtight container away from light and heat. Grind just before brewing.",
+	},
+}
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Add(testDocuments...)
+	return idx
+}
+
+func resultIDs(results []Result) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Document.ID
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "Title matches rank first",
+			query: "espresso ",
+			want:  []string{"espresso"},
+		},
+		{
+			name:  "Matches in any field",
+			query: "grind ",
+			want:  []string{"espresso", "storage", "pour-over"},
+		},
+		{
+			name:  "Plurals match",
+			query: "bean ",
+			want:  []string{"storage"},
+		},
+		{
+			name:  "Case is ignored",
+			query: "AIRTIGHT",
+			want:  []string{"storage"},
+		},
+		{
+			name:  "The word being typed matches as a prefix",
+			query: "pou",
+			want:  []string{"pour-over"},
+		},
+		{
+			name:  "Complete words don't match as a prefix",
+			query: "pou ",
+			want:  []string{},
+		},
+		{
+			name:  "Documents match some of the words",
+			query: "airtight shot",
+			want:  []string{"espresso", "storage"},
+		},
+		{
+			name:  "No words",
+			query: "  ...",
+			want:  []string{},
+		},
+	}
+
+	idx := newTestIndex()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultIDs(idx.Search(tt.query, 0)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+	results := newTestIndex().Search("grind", 2)
+	if got := resultIDs(results); !reflect.DeepEqual(got, []string{"espresso", "storage"}) {
+		t.Errorf("Search() = %v, want the best 2 results", got)
+	}
+}
+
+func TestAddReplacesDocuments(t *testing.T) {
+	idx := newTestIndex()
+
+	if idx.Add(testDocuments[0]) {
+		t.Error("Add() of an indexed document reported a change")
+	}
+
+	// Lists come without content, which keeps the content indexed before
+	if !idx.Add(Document{ID: "storage", Title: "Keeping Coffee Fresh", Summary: "Keep your coffee fresh"}) {
+		t.Error("Add() of a changed document reported no change")
+	}
+	if got := resultIDs(idx.Search("storing", 0)); len(got) != 0 {
+		t.Errorf("Search() found the old title in %v", got)
+	}
+	if got := resultIDs(idx.Search("keeping", 0)); !reflect.DeepEqual(got, []string{"storage"}) {
+		t.Errorf("Search() = %v, want the new title found", got)
+	}
+	if got := resultIDs(idx.Search("airtight", 0)); !reflect.DeepEqual(got, []string{"storage"}) {
+		t.Errorf("Search() = %v, want the content kept", got)
+	}
+	if indexed, withContent := idx.Has("storage"); !indexed || !withContent {
+		t.Errorf("Has() = %v, %v, want the document indexed with content", indexed, withContent)
+	}
+
+	idx.Remove("storage")
+	if got := resultIDs(idx.Search("airtight", 0)); len(got) != 0 {
+		t.Errorf("Search() found a removed document in %v", got)
+	}
+	if ids := idx.IDs(); len(ids) != 2 || slices.Contains(ids, "storage") {
+		t.Errorf("IDs() = %v, want the 2 documents left", ids)
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	long := strings.Repeat("filler ", 40) + "the crema tells you about the extraction " + strings.Repeat("filler ", 40)
+	idx := NewIndex()
+	idx.Add(
+		Document{ID: "long", Title: "Crema", Content: long},
+		Document{ID: "title", Title: "Water", Summary: "Soft water brews better coffee", Content: "Minerals matter."},
+	)
+
+	results := idx.Search("extraction", 0)
+	if len(results) != 1 {
+		t.Fatalf("Search() = %v, want 1 result", resultIDs(results))
+	}
+	snippet := results[0].Snippet
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") || !strings.Contains(snippet, "the extraction") {
+		t.Errorf("Snippet = %q, want the passage around the match", snippet)
+	}
+	if words := len(strings.Fields(snippet)); words != snippetWords {
+		t.Errorf("Snippet has %d words, want %d", words, snippetWords)
+	}
+
+	// Without a match in the content, the summary is shown
+	results = idx.Search("water", 0)
+	if len(results) != 1 || results[0].Snippet != "Soft water brews better coffee" {
+		t.Errorf("Search() = %+v, want the summary as the snippet", results)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  [][2]int
+	}{
+		{
+			name:  "Whole words",
+			text:  "Fresh beans, fresh bean",
+			query: "beans ",
+			want:  [][2]int{{6, 11}, {19, 23}},
+		},
+		{
+			name:  "Prefix of the last word",
+			text:  "Brewing a brew",
+			query: "fresh bre",
+			want:  [][2]int{{0, 7}, {10, 14}},
+		},
+		{
+			name:  "Multi-byte text",
+			text:  "Café crème",
+			query: "crème",
+			want:  [][2]int{{6, 12}},
+		},
+		{
+			name:  "Empty query",
+			text:  "Fresh beans",
+			query: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(tt.text, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Matches(%q, %q) = %v, want %v", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "search-index.json")
+	if err := newTestIndex().Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	idx, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if idx.Len() != len(testDocuments) {
+		t.Errorf("Len() = %d, want %d", idx.Len(), len(testDocuments))
+	}
+	if got := resultIDs(idx.Search("airtight", 0)); !reflect.DeepEqual(got, []string{"storage"}) {
+		t.Errorf("Search() = %v after loading", got)
+	}
+}
+
+func TestLoadIgnoresOtherVersions(t *testing.T) {
+	dir := t.TempDir()
+
+	idx, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || idx.Len() != 0 {
+		t.Errorf("Load() of a missing file = %d documents, %v, want an empty index", idx.Len(), err)
+	}
+
+	path := filepath.Join(dir, "old.json")
+	if err := os.WriteFile(path, []byte(`{"version": 0, "documents": [{"id": "a1"}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	idx, err = Load(path)
+	if err != nil || idx.Len() != 0 {
+		t.Errorf("Load() of an old version = %d documents, %v, want an empty index", idx.Len(), err)
+	}
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a term of a text and where its word is in the text
+type token struct {
+	term       string
+	start, end int // byte offsets of the word
+}
+
+// tokenize splits text into words, runs of letters and digits, and turns each into
+// its term: lower case with plural endings removed, so "Beans" finds "bean"
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{term: normalize(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: normalize(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// terms returns the terms of text
+func terms(text string) []string {
+	tokens := tokenize(text)
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = t.term
+	}
+	return terms
+}
+
+// normalize lower-cases a word and strips its plural ending, the first step of the
+// Porter stemmer: "sses" becomes "ss", "ies" becomes "i" and a final "s" goes unless
+// it follows another. Short words are kept whole.
+func normalize(word string) string {
+	word = strings.ToLower(word)
+	if utf8.RuneCountInString(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// query is a parsed search query. Its last term also matches as a prefix while it is
+// being typed, so results show up before a word is complete.
+type query struct {
+	terms  []string
+	prefix bool
+}
+
+func parseQuery(text string) query {
+	q := query{terms: terms(text)}
+	if len(q.terms) > 0 {
+		last, _ := utf8.DecodeLastRuneInString(text)
+		q.prefix = unicode.IsLetter(last) || unicode.IsDigit(last)
+	}
+	return q
+}
+
+// matches reports whether term matches the query term at index i
+func (q query) matches(i int, term string) bool {
+	if q.prefix && i == len(q.terms)-1 {
+		return strings.HasPrefix(term, q.terms[i])
+	}
+	return term == q.terms[i]
+}
+
+// match returns the index of the query term term matches, or -1
+func (q query) match(term string) int {
+	for i := range q.terms {
+		if q.matches(i, term) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Matches returns the byte ranges of the words of text that match query, for
+// highlighting them
+func Matches(text, query string) [][2]int {
+	q := parseQuery(query)
+	if len(q.terms) == 0 {
+		return nil
+	}
+	var ranges [][2]int
+	for _, t := range tokenize(text) {
+		if q.match(t.term) >= 0 {
+			ranges = append(ranges, [2]int{t.start, t.end})
+		}
+	}
+	return ranges
+}
+
+// snippetWords is the number of words in a snippet
+const snippetWords = 24
+
+// snippet returns the passage of text around the words that match q best: the window
+// of snippetWords words with the most distinct query terms, then the most matches.
+// Passages cut from the middle of text are marked with an ellipsis. It also returns
+// the number of matching words in the passage.
+func snippet(text string, q query) (string, int) {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return "", 0
+	}
+
+	best, bestDistinct, bestCount := 0, 0, 0
+	for start := 0; start == 0 || start+snippetWords <= len(tokens); start++ {
+		seen := make(map[int]bool)
+		count := 0
+		for _, t := range tokens[start:min(start+snippetWords, len(tokens))] {
+			if i := q.match(t.term); i >= 0 {
+				seen[i] = true
+				count++
+			}
+		}
+		if len(seen) > bestDistinct || len(seen) == bestDistinct && count > bestCount {
+			best, bestDistinct, bestCount = start, len(seen), count
+		}
+	}
+
+	end := len(text)
+	if last := best + snippetWords; last < len(tokens) {
+		end = tokens[last-1].end
+	}
+	passage := strings.Join(strings.Fields(text[tokens[best].start:end]), " ")
+	if best > 0 {
+		passage = "…" + passage
+	}
+	if end < len(text) {
+		passage += "…"
+	}
+	return passage, bestCount
+}
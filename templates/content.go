@@ -15,10 +15,11 @@ const ArticleContentTemplate = `{{ if .Author }}{{ faint "By" }} {{ cyan .Author
 {{ end }}{{ .Content }}
 `
 
-// RenderArticleContent renders an article with formatting for terminal display
-func RenderArticleContent(article *api.Article) (string, error) {
-	// Pre-render markdown content to support template syntax
-	renderedContent := RenderDescription(article.Content)
+// RenderArticleContent renders an article with formatting for terminal display, its
+// Markdown content wrapped to width
+func RenderArticleContent(article *api.Article, width int) (string, error) {
+	// Pre-render the template syntax in the content, then its Markdown
+	renderedContent := RenderMarkdown(RenderDescription(article.Content), width)
 
 	// Format published date if available
 	var publishedAt string
@@ -44,3 +45,14 @@ func RenderArticleContent(article *api.Article) (string, error) {
 
 	return RenderToString(ArticleContentTemplate, data)
 }
+
+const CategoryListTemplate = `{{range .Categories}}{{bold .Name}} {{faint (printf "(%s)" .Slug)}}
+{{if .Description}}  {{.Description}}
+{{end}}
+{{end}}`
+
+const ArticleListTemplate = `{{range .Articles}}{{if .IsBookmarked}}★ {{end}}{{bold .Title}}
+  {{faint (printf "%s • %d min read • %s" .Author .ReadTime .ID)}}
+{{if .Summary}}  {{.Summary}}
+{{end}}
+{{end}}`
@@ -0,0 +1,424 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/hassek/bc-cli/utils"
+)
+
+// Limits of content rendering. Descriptions and articles come from the backend, so
+// their templates get a restricted set of functions and a bounded amount of work.
+const (
+	// maxContentSize is the largest content rendered as a template, longer content is
+	// shown as plain text
+	maxContentSize = 64 << 10
+	// maxRenderedSize is the largest rendered content, and the largest text a content
+	// function may return
+	maxRenderedSize = 256 << 10
+	// maxWork is the most text the content functions may make in all, nested calls
+	// included
+	maxWork = 4 << 20
+	// maxRepeat is the largest count accepted by repeat
+	maxRepeat = 200
+	// maxWidth is the largest width accepted by wrap, paragraph, progressBar and printf
+	maxWidth = 200
+	// renderBudget is how long rendering may take
+	renderBudget = 250 * time.Millisecond
+)
+
+// Sizes used to bound the output of content functions before calling them
+const (
+	// styleOverhead is more than the escape codes and margins styling adds to a line
+	styleOverhead = 64
+	// maxValueSize is more than printf writes for a value that isn't a string, with
+	// the widths and precisions checkFormat allows
+	maxValueSize = 512
+)
+
+var (
+	errContentTooLarge = errors.New("rendered content is too large")
+	errRenderTimeout   = errors.New("rendering took too long")
+)
+
+// sandbox keeps track of the work done rendering a piece of content. Its functions
+// check the size of their output before making it, and fail once the work or time
+// allowed is spent, which stops the template execution.
+type sandbox struct {
+	deadline time.Time
+	// work is the size of the text the functions made so far
+	work int
+}
+
+func newSandbox() *sandbox {
+	return &sandbox{deadline: time.Now().Add(renderBudget)}
+}
+
+// spend accounts for a function about to make size bytes of text
+func (s *sandbox) spend(size int) error {
+	if time.Now().After(s.deadline) {
+		return errRenderTimeout
+	}
+	if size > maxRenderedSize || s.work+size > maxWork {
+		return errContentTooLarge
+	}
+	s.work += size
+	return nil
+}
+
+// styled makes a style function of funcMap a content function. width is the width it
+// wraps text to, 0 if it doesn't.
+func (s *sandbox) styled(name string, width func() int) func(string) (string, error) {
+	render := funcMap[name].(func(string) string)
+	return func(text string) (string, error) {
+		w := 0
+		if width != nil {
+			w = width()
+		}
+		if err := s.spend(styledSize(text, w)); err != nil {
+			return "", err
+		}
+		// Only the styling added here may reach the terminal
+		return render(utils.SanitizeText(text)), nil
+	}
+}
+
+// funcs returns the functions available to content templates: formatting only, with
+// the size of their output checked against the limits above
+func (s *sandbox) funcs() template.FuncMap {
+	return template.FuncMap{
+		"upper": func(text string) (string, error) {
+			// Changing the case may make some characters longer
+			if err := s.spend(2 * len(text)); err != nil {
+				return "", err
+			}
+			return strings.ToUpper(text), nil
+		},
+		"lower": func(text string) (string, error) {
+			if err := s.spend(2 * len(text)); err != nil {
+				return "", err
+			}
+			return strings.ToLower(text), nil
+		},
+		"add":           funcMap["add"],
+		"percentage":    funcMap["percentage"],
+		"wrapAuto":      s.styled("wrapAuto", autoWidth),
+		"highlight":     s.styled("highlight", nil),
+		"emphasis":      s.styled("emphasis", nil),
+		"section":       s.styled("section", nil),
+		"bold":          s.styled("bold", nil),
+		"faint":         s.styled("faint", nil),
+		"faintNoWrap":   s.styled("faintNoWrap", nil),
+		"cyan":          s.styled("cyan", nil),
+		"green":         s.styled("green", nil),
+		"yellow":        s.styled("yellow", nil),
+		"red":           s.styled("red", nil),
+		"paragraphAuto": s.styled("paragraphAuto", autoWidth),
+		"repeat": func(text string, count int) (string, error) {
+			if count < 0 || count > maxRepeat {
+				return "", fmt.Errorf("repeat count %d is out of range 0-%d", count, maxRepeat)
+			}
+			if err := s.spend(len(text) * count); err != nil {
+				return "", err
+			}
+			return strings.Repeat(text, count), nil
+		},
+		"wrap": func(text string, width int) (string, error) {
+			if err := checkWidth(width); err != nil {
+				return "", err
+			}
+			if err := s.spend(styledSize(text, width)); err != nil {
+				return "", err
+			}
+			return utils.WrapText(text, width), nil
+		},
+		"paragraph": func(text string, width int) (string, error) {
+			if err := checkWidth(width); err != nil {
+				return "", err
+			}
+			if err := s.spend(styledSize(text, width)); err != nil {
+				return "", err
+			}
+			return paragraph(text, width), nil
+		},
+		"progressBar": func(current, total, width int) (string, error) {
+			if err := checkWidth(width); err != nil {
+				return "", err
+			}
+			if total < 0 || current < 0 || current > total {
+				return "", fmt.Errorf("progress %d/%d is out of range", current, total)
+			}
+			if err := s.spend(len("█") * width); err != nil {
+				return "", err
+			}
+			return progressBar(current, total, width), nil
+		},
+		"printf": func(format string, args ...any) (string, error) {
+			if err := checkFormat(format); err != nil {
+				return "", err
+			}
+			if err := s.spend(formattedSize(format, args)); err != nil {
+				return "", err
+			}
+			// %c makes control characters out of numbers
+			return utils.SanitizeText(fmt.Sprintf(format, args...)), nil
+		},
+	}
+}
+
+// contentFuncs has the names of the content functions, for checking templates
+var contentFuncs = (&sandbox{}).funcs()
+
+// styledSize bounds the size of text once styled and, if width isn't 0, wrapped to
+// width. Styling pads every line to the longest one, and wrapping makes at most two
+// lines per width characters, since a line and the first word of the next are longer
+// than width.
+func styledSize(text string, width int) int {
+	lines := strings.Count(text, "\n") + 2 // With a bottom margin
+	longest := width
+	if width > 0 {
+		lines += 2 * len(text) / width
+	} else {
+		for line := range strings.SplitSeq(text, "\n") {
+			longest = max(longest, len(line))
+		}
+	}
+	return len(text) + lines*(longest+styleOverhead)
+}
+
+// formattedSize bounds the size of the output of printf. Every verb may refer to any
+// argument, so each counts as the longest of them.
+func formattedSize(format string, args []any) int {
+	longest := 0
+	for _, arg := range args {
+		size := maxValueSize
+		if s, ok := arg.(string); ok {
+			size = len(s)
+		}
+		longest = max(longest, size)
+	}
+	return len(format) + strings.Count(format, "%")*(longest+maxWidth)
+}
+
+// contentBuiltins are the text/template built-in functions content templates may use
+var contentBuiltins = map[string]bool{
+	"and": true, "or": true, "not": true,
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+}
+
+func checkWidth(width int) error {
+	if width < 1 || width > maxWidth {
+		return fmt.Errorf("width %d is out of range 1-%d", width, maxWidth)
+	}
+	return nil
+}
+
+// checkFormat rejects printf formats with a width or precision that is computed from
+// an argument or larger than maxWidth, the only ways a short format makes long output
+func checkFormat(format string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Scan the flags, width and precision up to the verb
+		n := 0
+		for i++; i < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[i]) >= 0; i++ {
+			switch c := format[i]; {
+			case c == '*':
+				return errors.New("printf widths from arguments aren't allowed")
+			case c >= '0' && c <= '9':
+				n = n*10 + int(c-'0')
+				if n > maxWidth {
+					return fmt.Errorf("printf width is larger than %d", maxWidth)
+				}
+			default:
+				n = 0
+			}
+		}
+	}
+	return nil
+}
+
+// RenderDescription renders a description string that may contain template syntax.
+// If the description contains template markers ({{ }}), it renders them.
+// Otherwise, it returns the description as-is.
+// This allows backend CMS to send descriptions with highlighting and formatting.
+//
+// Descriptions are untrusted, so they are rendered in a sandbox: only the formatting
+// functions in contentFuncs, no loops or nested templates, and limits on sizes and
+// time. Content that doesn't render within them is shown as plain text. Either way
+// terminal control sequences are removed from the result, since template literals and
+// printf can make them out of text that was clean when decoded.
+func RenderDescription(description string) string {
+	// Check if description contains template syntax
+	if !strings.Contains(description, "{{") {
+		return description
+	}
+
+	rendered, err := renderContent(description)
+	if err != nil {
+		return plainText(description)
+	}
+	return rendered
+}
+
+// ContentSource returns content as written, Markdown, with its template actions replaced
+// by their text, e.g. for exporting articles
+func ContentSource(content string) string {
+	if !strings.Contains(content, "{{") {
+		return content
+	}
+	return plainText(content)
+}
+
+// renderContent renders untrusted content as a template within the sandbox limits
+func renderContent(content string) (string, error) {
+	if len(content) > maxContentSize {
+		return "", errContentTooLarge
+	}
+
+	t, err := template.New("content").Funcs(contentFuncs).Parse(content)
+	if err != nil {
+		return "", err
+	}
+	if len(t.Templates()) > 1 {
+		return "", errors.New("defining templates isn't allowed")
+	}
+	if err := checkNode(t.Root); err != nil {
+		return "", err
+	}
+
+	// The checks above leave no loops, so the sandbox functions are where the limits
+	// apply: they fail once the work or time allowed is spent, stopping the execution
+	s := newSandbox()
+	out := &limitedBuffer{limit: maxRenderedSize}
+	if err := t.Funcs(s.funcs()).Execute(out, nil); err != nil {
+		return "", err
+	}
+	return utils.SanitizeText(out.String()), nil
+}
+
+// checkNode allows the parts of the template language content needs: text, actions,
+// conditionals and calls to the content functions. Loops, nested templates and
+// anything else are rejected.
+func checkNode(node parse.Node) error {
+	switch n := node.(type) {
+	case nil, *parse.TextNode, *parse.CommentNode, *parse.BoolNode, *parse.NumberNode,
+		*parse.StringNode, *parse.NilNode, *parse.DotNode, *parse.FieldNode, *parse.VariableNode:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.ActionNode:
+		return checkNode(n.Pipe)
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := checkNode(cmd); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := checkNode(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *parse.ChainNode:
+		return checkNode(n.Node)
+	case *parse.IdentifierNode:
+		if _, ok := contentFuncs[n.Ident]; !ok && !contentBuiltins[n.Ident] {
+			return fmt.Errorf("function %q isn't allowed", n.Ident)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s isn't allowed", node)
+	}
+}
+
+func checkBranch(n *parse.BranchNode) error {
+	if err := checkNode(n.Pipe); err != nil {
+		return err
+	}
+	if err := checkNode(n.List); err != nil {
+		return err
+	}
+	return checkNode(n.ElseList)
+}
+
+// limitedBuffer is a buffer that fails writes past its limit, which stops the
+// template execution writing to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errContentTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+// plainText is the fallback for content that doesn't render: the text with each
+// action replaced by the string literals in it, so {{highlight "fresh"}} keeps "fresh",
+// and terminal control sequences removed
+func plainText(content string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(content, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(content[start+2:], "}}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(content[:start])
+		b.WriteString(strings.Join(stringLiterals(content[start+2:start+2+end]), " "))
+		content = content[start+2+end+2:]
+	}
+	b.WriteString(content)
+	return utils.SanitizeText(b.String())
+}
+
+// stringLiterals returns the unquoted string literals of a template action. Unquoting
+// turns escapes like \x1b into control characters, so the literals are sanitised.
+func stringLiterals(action string) []string {
+	var literals []string
+	for i := 0; i < len(action); i++ {
+		if c := action[i]; c != '"' && c != '`' {
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(action[i:])
+		if err != nil {
+			break
+		}
+		if s, err := strconv.Unquote(quoted); err == nil {
+			literals = append(literals, utils.SanitizeText(s))
+		}
+		i += len(quoted) - 1
+	}
+	return literals
+}
@@ -0,0 +1,280 @@
+package templates
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+func TestRenderDescriptionSandbox(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+		wantErr     string
+	}{
+		{
+			name:        "Formatting functions",
+			description: `{{upper "fresh"}} {{printf "%-6s|" "beans"}}`,
+			want:        "FRESH beans |",
+		},
+		{
+			name:        "Conditionals",
+			description: `{{if eq 1 1}}yes{{else}}no{{end}}`,
+			want:        "yes",
+		},
+		{
+			name:        "Repeat within the limit",
+			description: `{{repeat "─" 3}}`,
+			want:        "───",
+		},
+		{
+			name:        "Repeat over the limit",
+			description: `{{repeat "─" 1000000000}}`,
+			wantErr:     "repeat count",
+		},
+		{
+			name:        "Wrap over the limit",
+			description: `{{wrap "text" 100000}}`,
+			wantErr:     "width",
+		},
+		{
+			name:        "Paragraph with a negative width",
+			description: `{{paragraph "text" -1}}`,
+			wantErr:     "width",
+		},
+		{
+			name:        "Progress bar over the limit",
+			description: `{{progressBar 1 2 1000000}}`,
+			wantErr:     "width",
+		},
+		{
+			name:        "Printf with a huge width",
+			description: `{{printf "%1000000d" 1}}`,
+			wantErr:     "printf width",
+		},
+		{
+			name:        "Printf with a width from an argument",
+			description: `{{printf "%*d" 1000000 1}}`,
+			wantErr:     "printf widths",
+		},
+		{
+			name:        "Printf making control characters",
+			description: `{{printf "%c]52;c;cm0gLXJm%c" 27 7}}done`,
+			want:        "done",
+		},
+		{
+			name:        "Built-in functions outside the whitelist",
+			description: `{{print "x"}}`,
+			wantErr:     `function "print" isn't allowed`,
+		},
+		{
+			name:        "Loops",
+			description: `{{range 1000000000}}{{end}}`,
+			wantErr:     "isn't allowed",
+		},
+		{
+			name:        "Template definitions",
+			description: `{{define "a"}}{{template "a"}}{{end}}{{template "a"}}`,
+			wantErr:     "defining templates",
+		},
+		{
+			name:        "Output over the limit",
+			description: strings.Repeat(`{{repeat "abcdefghij" 200}}`, 200),
+			wantErr:     "too large",
+		},
+		{
+			name:        "Nested repeat",
+			description: `{{repeat (repeat (repeat (repeat "xxxxx" 200) 200) 200) 10}}`,
+			wantErr:     "too large",
+		},
+		{
+			name:        "Styling padding every line",
+			description: `{{bold (printf "%s%s" (repeat (repeat "x" 200) 200) (repeat (repeat "\n" 200) 200))}}`,
+			wantErr:     "too large",
+		},
+		{
+			name:        "Printf referring to an argument many times",
+			description: `{{printf "%[1]s%[1]s%[1]s%[1]s%[1]s%[1]s%[1]s%[1]s%[1]s%[1]s" (repeat (repeat "x" 200) 200)}}`,
+			wantErr:     "too large",
+		},
+		{
+			name:        "Content over the limit",
+			description: "{{bold \"x\"}}" + strings.Repeat("x", maxContentSize),
+			wantErr:     "too large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderContent(tt.description)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderContent() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderContent() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderContentBoundsMemory(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := renderContent(`{{repeat (repeat (repeat (repeat "xxxxx" 200) 200) 200) 10}}`)
+	runtime.ReadMemStats(&after)
+
+	if err == nil {
+		t.Fatal("renderContent() succeeded, want the nested repeat rejected")
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4*maxWork {
+		t.Errorf("renderContent() allocated %d bytes, want at most %d", allocated, 4*maxWork)
+	}
+}
+
+func TestRenderDescriptionFallsBackToPlainText(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "Disallowed function keeps its text",
+			description: `Get {{print "fresh coffee"}} monthly`,
+			want:        "Get fresh coffee monthly",
+		},
+		{
+			name:        "Parse error",
+			description: `Get {{highlight "fresh coffee"`,
+			want:        `Get {{highlight "fresh coffee"`,
+		},
+		{
+			name:        "Several literals",
+			description: "{{index `this` \"that\"}}!",
+			want:        "this that!",
+		},
+		{
+			name:        "Escaped control characters in a literal",
+			description: `{{print "\x1b]52;c;cm0gLXJm\a" "fresh\u009b2J"}}`,
+			want:        " fresh",
+		},
+		{
+			name:        "Limit exceeded",
+			description: `{{repeat "spam" 1000000}}eggs`,
+			want:        "spameggs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderDescription(tt.description); got != tt.want {
+				t.Errorf("RenderDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderDescriptionRemovesControlSequences(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "OSC in a string literal",
+			description: `Title{{"\x1b]0;pwned\a"}}`,
+			want:        "Title",
+		},
+		{
+			name:        "CSI made by printf",
+			description: `{{printf "%c[2J" 27}}Cleared`,
+			want:        "Cleared",
+		},
+		{
+			name:        "CSI in a styled literal",
+			description: `{{bold "\x1b[8mhidden"}}`,
+			want:        "hidden",
+		},
+		{
+			name:        "OSC in the plain text fallback",
+			description: `Copy {{print "\x1b]52;c;cm0gLXJm\a"}}this`,
+			want:        "Copy this",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderDescription(tt.description)
+			if strings.ContainsAny(got, "\x1b\a") || strings.TrimSpace(got) != tt.want {
+				t.Errorf("RenderDescription() = %q, want %q", got, tt.want)
+			}
+
+			article, err := RenderArticleContent(&api.Article{Title: "Payload", Content: tt.description}, 80)
+			if err != nil {
+				t.Fatalf("RenderArticleContent() error = %v", err)
+			}
+			if strings.ContainsAny(article, "\x1b\a") {
+				t.Errorf("RenderArticleContent() = %q, want no control sequences", article)
+			}
+		})
+	}
+}
+
+func FuzzRenderDescription(f *testing.F) {
+	seeds := []string{
+		"plain text",
+		`We offer {{highlight "premium coffee"}} from around the world`,
+		`{{paragraphAuto (printf "%s — drink it" (highlight "We only offer"))}}`,
+		`{{if .}}{{.Field}}{{else}}{{faint "none"}}{{end}}`,
+		`{{$x := repeat "ab" 200}}{{$x}}{{$x}}`,
+		`{{repeat "x" 1000000000}}`,
+		`{{repeat (repeat (repeat (repeat "xxxxx" 200) 200) 200) 10}}`,
+		`{{paragraph (repeat (repeat "\n" 200) 200) 200}}`,
+		`{{range 1000000000}}{{end}}`,
+		`{{define "a"}}{{template "a"}}{{end}}`,
+		`{{printf "%[1]*d" 100000 1}}`,
+		`{{wrap "a b c" 0}}{{progressBar 5 2 10}}`,
+		"{{`unterminated",
+		"{{}}{{{{}}}}",
+		`{{"\x1b]0;pwned\a"}}`,
+		`{{printf "%c[2J" 27}}`,
+		`{{bold "\x1b[8mhidden"}}`,
+		`{{print "\x1b]52;c;cm0gLXJm\a"}}`,
+		`{{printf "%c%c" 0x9d 0x9c}}`,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, description string) {
+		start := time.Now()
+		got := RenderDescription(description)
+
+		if !strings.Contains(description, "{{") && got != description {
+			t.Fatalf("RenderDescription(%q) = %q, want the description unchanged", description, got)
+		}
+		if strings.Contains(description, "{{") && strings.ContainsFunc(got, isTerminalControl) {
+			t.Fatalf("RenderDescription(%q) = %q, want no ESC, BEL or C1 characters", description, got)
+		}
+		if len(got) > max(maxRenderedSize, len(description)) {
+			t.Fatalf("RenderDescription(%q) returned %d bytes", description, len(got))
+		}
+		if elapsed := time.Since(start); elapsed > 2*renderBudget {
+			t.Fatalf("RenderDescription(%q) took %v", description, elapsed)
+		}
+	})
+}
+
+// isTerminalControl reports whether r starts or ends a terminal control sequence
+func isTerminalControl(r rune) bool {
+	return r == '\x1b' || r == '\a' || r >= 0x80 && r <= 0x9f
+}
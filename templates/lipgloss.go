@@ -5,53 +5,42 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui/styles"
 )
 
-var (
-	// Box style for preference headers and progress bars
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			Padding(0, 1).
-			Width(60)
-
-	// Style for headers
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("14"))
-
-	// Style for info text
-	infoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("7"))
-
-	// Style for warnings
-	warningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("11"))
-)
+// boxStyle is the box around preference headers and progress bars, built on use so it
+// follows the active theme
+func boxStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Border).
+		Padding(0, 1).
+		Width(60)
+}
 
 // RenderPreferenceHeader renders a preference header box using Lipgloss
 func RenderPreferenceHeader(preferenceNum, totalQuantity, remaining int, lowRemaining bool) string {
 	var lines []string
 
 	// Header line
-	header := headerStyle.Render(fmt.Sprintf("Preference #%d", preferenceNum))
+	header := styles.HeadingStyle.Render(fmt.Sprintf("Preference #%d", preferenceNum))
 	lines = append(lines, header)
 
 	// Allocating from line
-	allocLine := infoStyle.Render(fmt.Sprintf("Allocating from: %d total", totalQuantity))
+	allocLine := styles.TextStyle.Render(fmt.Sprintf("Allocating from: %d total", totalQuantity))
 	lines = append(lines, allocLine)
 
 	// Remaining line
 	remainingText := fmt.Sprintf("Remaining: %d", remaining)
 	if lowRemaining {
 		remainingText += " ⚠️  (almost done!)"
-		lines = append(lines, warningStyle.Render(remainingText))
+		lines = append(lines, styles.WarningStyle.Render(remainingText))
 	} else {
-		lines = append(lines, infoStyle.Render(remainingText))
+		lines = append(lines, styles.TextStyle.Render(remainingText))
 	}
 
 	content := strings.Join(lines, "\n")
-	return boxStyle.Render(content)
+	return boxStyle().Render(content)
 }
 
 // RenderProgressBar renders a progress bar box using Lipgloss
@@ -68,7 +57,7 @@ func RenderProgressBar(current, total int) string {
 		progressText += " ✓"
 	}
 
-	return boxStyle.Render(infoStyle.Render(progressText))
+	return boxStyle().Render(styles.TextStyle.Render(progressText))
 }
 
 // RenderOrderSummary renders the order summary box using Lipgloss
@@ -76,28 +65,135 @@ func RenderOrderSummary(tierName string, totalQuantity int, currency string, tot
 	var lines []string
 
 	// Title
-	lines = append(lines, headerStyle.Render("Your Order Summary"))
+	lines = append(lines, styles.HeadingStyle.Render("Your Order Summary"))
 	lines = append(lines, "")
 
 	// Order details
-	lines = append(lines, infoStyle.Render(fmt.Sprintf("Tier: %s", tierName)))
-	lines = append(lines, infoStyle.Render(fmt.Sprintf("Total: %d/month", totalQuantity)))
-	lines = append(lines, infoStyle.Render(fmt.Sprintf("Price: %s %.2f/%s", currency, totalPrice, billingPeriod)))
+	lines = append(lines, styles.TextStyle.Render(fmt.Sprintf("Tier: %s", tierName)))
+	lines = append(lines, styles.TextStyle.Render(fmt.Sprintf("Total: %d/month", totalQuantity)))
+	lines = append(lines, styles.TextStyle.Render(fmt.Sprintf("Price: %s %.2f/%s", currency, totalPrice, billingPeriod)))
 	lines = append(lines, "")
-	lines = append(lines, infoStyle.Render("How your coffee will be prepared:"))
+	lines = append(lines, styles.TextStyle.Render("How your coffee will be prepared:"))
 
 	// Line items
 	for i, item := range lineItems {
-		lines = append(lines, infoStyle.Render(fmt.Sprintf("   %d. %s", i+1, item)))
+		lines = append(lines, styles.TextStyle.Render(fmt.Sprintf("   %d. %s", i+1, item)))
 	}
 
 	content := strings.Join(lines, "\n")
 
 	summaryBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(styles.Border).
 		Padding(1, 2).
 		Width(65)
 
 	return summaryBox.Render(content)
 }
+
+// DiffOp marks how a row in a preference diff changed
+type DiffOp int
+
+const (
+	DiffUnchanged DiffOp = iota
+	DiffAdded
+	DiffRemoved
+	DiffModified
+)
+
+// DiffRow is a single row of a side-by-side preference diff
+type DiffRow struct {
+	Op     DiffOp
+	Before string
+	After  string
+}
+
+// PreferenceDiffView holds everything needed to render a preference diff
+type PreferenceDiffView struct {
+	Rows           []DiffRow
+	QuantityBefore int
+	QuantityAfter  int
+	HasPricing     bool
+	Currency       string
+	BillingPeriod  string // the plan's, e.g. month; quantities are per billing period
+	PriceBefore    float64
+	PriceAfter     float64
+}
+
+// RenderPreferenceDiff renders current and proposed preferences side by side.
+// Removed rows are red, added rows green, modified rows yellow and unchanged rows faint.
+func RenderPreferenceDiff(view PreferenceDiffView) string {
+	const columnWidth = 38
+
+	column := lipgloss.NewStyle().Width(columnWidth).PaddingRight(2)
+	removed := lipgloss.NewStyle().Foreground(styles.Error)
+	added := lipgloss.NewStyle().Foreground(styles.Success)
+	modified := lipgloss.NewStyle().Foreground(styles.Warning)
+
+	var lines []string
+	lines = append(lines, styles.HeadingStyle.Render("Proposed Changes"))
+	lines = append(lines, "")
+	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+		column.Render(styles.FaintStyle.Render("Current")),
+		column.Render(styles.FaintStyle.Render("Proposed"))))
+
+	for _, row := range view.Rows {
+		before, after := row.Before, row.After
+		if before == "" {
+			before = "—"
+		}
+		if after == "" {
+			after = "—"
+		}
+
+		var left, right string
+		switch row.Op {
+		case DiffAdded:
+			left = styles.FaintStyle.Render("  " + before)
+			right = added.Render("+ " + after)
+		case DiffRemoved:
+			left = removed.Render("- " + before)
+			right = styles.FaintStyle.Render("  " + after)
+		case DiffModified:
+			left = modified.Render("~ " + before)
+			right = modified.Render("~ " + after)
+		default:
+			left = styles.FaintStyle.Render("  " + before)
+			right = styles.FaintStyle.Render("  " + after)
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, column.Render(left), column.Render(right)))
+	}
+
+	lines = append(lines, "")
+	quantity := fmt.Sprintf("Quantity: %d → %d", view.QuantityBefore, view.QuantityAfter)
+	if view.BillingPeriod != "" {
+		quantity += " per " + view.BillingPeriod
+	}
+	lines = append(lines, styles.TextStyle.Render(quantity+" "+
+		renderDelta(float64(view.QuantityAfter-view.QuantityBefore), "%+.0f")))
+
+	if view.HasPricing {
+		lines = append(lines, styles.TextStyle.Render(fmt.Sprintf("Price:    %s %.2f → %.2f/%s %s",
+			view.Currency, view.PriceBefore, view.PriceAfter, view.BillingPeriod,
+			renderDelta(view.PriceAfter-view.PriceBefore, "%+.2f"))))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Border).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderDelta formats a numeric change, coloured green for decreases and yellow for increases
+func renderDelta(delta float64, format string) string {
+	text := "(" + fmt.Sprintf(format, delta) + ")"
+	switch {
+	case delta > 0:
+		return lipgloss.NewStyle().Foreground(styles.Warning).Render(text)
+	case delta < 0:
+		return lipgloss.NewStyle().Foreground(styles.Success).Render(text)
+	default:
+		return styles.FaintStyle.Render("(no change)")
+	}
+}
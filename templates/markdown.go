@@ -0,0 +1,485 @@
+package templates
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui/styles"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// MaxMarkdownWidth caps the width Markdown is wrapped to, long lines being hard to read
+const MaxMarkdownWidth = 100
+
+// minMarkdownWidth is the narrowest width Markdown is wrapped to, however deep the
+// lists and quotes it is nested in
+const minMarkdownWidth = 10
+
+var (
+	markdown       = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	markdownParser = markdown.Parser()
+)
+
+// RenderMarkdown renders Markdown for the terminal, wrapped to width (up to
+// MaxMarkdownWidth) and styled with the active theme: headings, emphasis, lists, block
+// quotes, code blocks, tables and links. Text already styled, like the output of
+// RenderDescription, keeps its styling.
+func RenderMarkdown(source string, width int) string {
+	if width <= 0 || width > MaxMarkdownWidth {
+		width = MaxMarkdownWidth
+	}
+	src := []byte(source)
+	r := markdownRenderer{source: src}
+	doc := markdownParser.Parse(text.NewReader(src))
+	return strings.Join(r.blocks(doc, width), "\n")
+}
+
+// MarkdownText returns the text of Markdown without markup or styling, each block on
+// lines of its own, with template actions replaced by their text like RenderDescription
+// does when rendering fails. It is the text searches look through.
+func MarkdownText(source string) string {
+	src := []byte(plainText(source))
+	r := markdownRenderer{source: src}
+	var lines []string
+	_ = ast.Walk(markdownParser.Parse(text.NewReader(src)), func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || node.Type() != ast.TypeBlock {
+			return ast.WalkContinue, nil
+		}
+		switch node.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			for i := 0; i < node.Lines().Len(); i++ {
+				segment := node.Lines().At(i)
+				lines = append(lines, strings.TrimRight(string(segment.Value(src)), "\n"))
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.ThematicBreak:
+			return ast.WalkSkipChildren, nil
+		}
+		if child := node.FirstChild(); child != nil && child.Type() == ast.TypeInline {
+			lines = append(lines, r.plain(node))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(lines, "\n")
+}
+
+// MarkdownHTML renders Markdown as HTML, e.g. for exporting articles. Raw HTML in the
+// source is left out, content being untrusted.
+func MarkdownHTML(source string) string {
+	var b strings.Builder
+	if err := markdown.Convert([]byte(source), &b); err != nil {
+		return "<pre>" + html.EscapeString(source) + "</pre>"
+	}
+	return b.String()
+}
+
+// markdownRenderer renders a parsed Markdown document as lines of styled text
+type markdownRenderer struct {
+	source []byte
+}
+
+// blocks renders the block children of node, separated by blank lines unless they
+// are the paragraphs and lists of an item of a tight list
+func (r markdownRenderer) blocks(node ast.Node, width int) []string {
+	width = max(width, minMarkdownWidth)
+	tight := false
+	if _, ok := node.(*ast.ListItem); ok {
+		tight = node.Parent().(*ast.List).IsTight
+	}
+
+	var lines []string
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		block := r.block(child, width)
+		if len(block) == 0 {
+			continue
+		}
+		if len(lines) > 0 && !tight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+func (r markdownRenderer) block(node ast.Node, width int) []string {
+	switch n := node.(type) {
+	case *ast.Heading:
+		return r.heading(n, width)
+	case *ast.Paragraph, *ast.TextBlock:
+		return wrapLines(r.inline(node, span{}), width)
+	case *ast.List:
+		return r.list(n, width)
+	case *ast.Blockquote:
+		bar := lipgloss.NewStyle().Foreground(styles.Border).Render("│") + " "
+		return prefixLines(r.blocks(n, width-2), bar, bar)
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		return r.code(node, width)
+	case *ast.ThematicBreak:
+		return []string{lipgloss.NewStyle().Foreground(styles.Border).Render(strings.Repeat("─", width))}
+	case *east.Table:
+		return r.table(n, width)
+	case *ast.HTMLBlock:
+		var lines []string
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			lines = append(lines, styles.FaintStyle.Render(strings.TrimRight(string(line.Value(r.source)), "\n")))
+		}
+		return lines
+	default:
+		return r.blocks(node, width)
+	}
+}
+
+func (r markdownRenderer) heading(n *ast.Heading, width int) []string {
+	style := span{bold: true, color: styles.Section}
+	if n.Level <= 2 {
+		style.color = styles.Heading
+	}
+	lines := wrapLines(r.inline(n, style), width)
+	if n.Level == 1 {
+		underline := min(width, maxLineWidth(lines))
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.Border).Render(strings.Repeat("─", underline)))
+	}
+	return lines
+}
+
+func (r markdownRenderer) list(n *ast.List, width int) []string {
+	var lines []string
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		var marker string
+		if n.IsOrdered() {
+			marker = lipgloss.NewStyle().Foreground(styles.Primary).Render(strconv.Itoa(number) + string(n.Marker))
+			number++
+		} else {
+			bullet := "•"
+			if listDepth(n) > 0 {
+				bullet = "◦"
+			}
+			marker = lipgloss.NewStyle().Foreground(styles.Primary).Render(bullet)
+		}
+		marker += " "
+		indent := strings.Repeat(" ", lipgloss.Width(marker))
+
+		if len(lines) > 0 && !n.IsTight {
+			lines = append(lines, "")
+		}
+		lines = append(lines, prefixLines(r.blocks(item, width-len(indent)), marker, indent)...)
+	}
+	return lines
+}
+
+// listDepth returns how many lists n is nested in
+func listDepth(n ast.Node) int {
+	depth := 0
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if _, ok := p.(*ast.List); ok {
+			depth++
+		}
+	}
+	return depth
+}
+
+// code renders a code block as is, indented, with lines longer than width broken
+func (r markdownRenderer) code(node ast.Node, width int) []string {
+	style := lipgloss.NewStyle().Foreground(styles.Accent)
+	var lines []string
+	for i := 0; i < node.Lines().Len(); i++ {
+		segment := node.Lines().At(i)
+		line := strings.TrimRight(string(segment.Value(r.source)), "\n")
+		line = strings.ReplaceAll(line, "\t", "    ")
+		for _, part := range breakLine(line, width-2) {
+			lines = append(lines, "  "+style.Render(part))
+		}
+	}
+	return lines
+}
+
+// breakLine breaks line into parts of at most width runes
+func breakLine(line string, width int) []string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return []string{line}
+	}
+	var parts []string
+	for len(runes) > width {
+		parts = append(parts, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(parts, string(runes))
+}
+
+// table renders a table with its columns narrowed to fit width, cells wrapping within
+// them
+func (r markdownRenderer) table(n *east.Table, width int) []string {
+	var rows [][]string
+	header := -1
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var style span
+		if _, ok := row.(*east.TableHeader); ok {
+			style.bold = true
+			header = len(rows)
+		}
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, r.inline(cell, style))
+		}
+		rows = append(rows, cells)
+	}
+
+	columns := len(n.Alignments)
+	widths := make([]int, columns)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < columns {
+				widths[i] = max(widths[i], lipgloss.Width(cell))
+			}
+		}
+	}
+	// Narrow the widest column until the table fits, separators taking 3 cells each
+	for total := sum(widths) + 3*(columns-1); total > width; total-- {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	border := lipgloss.NewStyle().Foreground(styles.Border)
+	var lines []string
+	for i, row := range rows {
+		columnLines := make([][]string, columns)
+		height := 1
+		for c := range columns {
+			cell := ""
+			if c < len(row) {
+				cell = row[c]
+			}
+			columnLines[c] = wrapLines(cell, widths[c])
+			height = max(height, len(columnLines[c]))
+		}
+		for l := range height {
+			parts := make([]string, columns)
+			for c := range columns {
+				cell := ""
+				if l < len(columnLines[c]) {
+					cell = columnLines[c][l]
+				}
+				parts[c] = alignCell(cell, widths[c], n.Alignments[c])
+			}
+			lines = append(lines, strings.TrimRight(strings.Join(parts, border.Render(" │ ")), " "))
+		}
+		if i == header {
+			parts := make([]string, columns)
+			for c, w := range widths {
+				parts[c] = strings.Repeat("─", w)
+			}
+			lines = append(lines, border.Render(strings.Join(parts, "─┼─")))
+		}
+	}
+	return lines
+}
+
+func alignCell(cell string, width int, alignment east.Alignment) string {
+	padding := max(width-lipgloss.Width(cell), 0)
+	switch alignment {
+	case east.AlignRight:
+		return strings.Repeat(" ", padding) + cell
+	case east.AlignCenter:
+		return strings.Repeat(" ", padding/2) + cell + strings.Repeat(" ", padding-padding/2)
+	default:
+		return cell + strings.Repeat(" ", padding)
+	}
+}
+
+// span is the style of inline text. Spans are combined rather than nested, so text
+// after a nested span keeps the outer style.
+type span struct {
+	bold, italic, underline, strikethrough bool
+	color                                  lipgloss.TerminalColor
+}
+
+// render styles text, leaving text without a style untouched so styling already in
+// it, from a template, survives
+func (s span) render(text string) string {
+	if s == (span{}) {
+		return text
+	}
+	style := lipgloss.NewStyle().Bold(s.bold).Italic(s.italic).Underline(s.underline).Strikethrough(s.strikethrough)
+	if s.color != nil {
+		style = style.Foreground(s.color)
+	}
+	return style.Render(text)
+}
+
+// inline renders the inline children of node as one styled string
+func (r markdownRenderer) inline(node ast.Node, style span) string {
+	var b strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *ast.Text:
+			value := n.Segment.Value(r.source)
+			if !n.IsRaw() {
+				value = unescape(value)
+			}
+			b.WriteString(style.render(string(value)))
+			if n.HardLineBreak() {
+				b.WriteString("\n")
+			} else if n.SoftLineBreak() {
+				b.WriteString(" ")
+			}
+		case *ast.String:
+			value := n.Value
+			if !n.IsRaw() {
+				value = unescape(value)
+			}
+			b.WriteString(style.render(string(value)))
+		case *ast.Emphasis:
+			inner := style
+			if n.Level >= 2 {
+				inner.bold = true
+			} else {
+				inner.italic = true
+			}
+			b.WriteString(r.inline(n, inner))
+		case *east.Strikethrough:
+			inner := style
+			inner.strikethrough = true
+			b.WriteString(r.inline(n, inner))
+		case *ast.CodeSpan:
+			inner := style
+			inner.color = styles.Accent
+			b.WriteString(inner.render(r.text(n)))
+		case *ast.Link:
+			inner := style
+			inner.underline, inner.color = true, styles.Primary
+			b.WriteString(r.inline(n, inner))
+			if destination := string(n.Destination); destination != "" && destination != r.text(n) {
+				b.WriteString(" " + styles.FaintStyle.Render("("+destination+")"))
+			}
+		case *ast.AutoLink:
+			inner := style
+			inner.underline, inner.color = true, styles.Primary
+			b.WriteString(inner.render(string(n.URL(r.source))))
+		case *ast.Image:
+			b.WriteString(styles.FaintStyle.Render("[image: " + r.text(n) + "] (" + string(n.Destination) + ")"))
+		case *ast.RawHTML:
+			for i := 0; i < n.Segments.Len(); i++ {
+				segment := n.Segments.At(i)
+				b.WriteString(styles.FaintStyle.Render(string(segment.Value(r.source))))
+			}
+		case *east.TaskCheckBox:
+			if n.IsChecked {
+				b.WriteString(lipgloss.NewStyle().Foreground(styles.Success).Render("☑") + " ")
+			} else {
+				b.WriteString("☐ ")
+			}
+		default:
+			b.WriteString(r.inline(n, style))
+		}
+	}
+	return b.String()
+}
+
+// text returns the text of the inline children of node, without styling
+func (r markdownRenderer) text(node ast.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if t, ok := child.(*ast.Text); ok {
+			b.Write(t.Segment.Value(r.source))
+		} else {
+			b.WriteString(r.text(child))
+		}
+	}
+	return b.String()
+}
+
+// plain returns the text of the inline children of node without markup: the text of
+// links and emphasis, and the URLs of autolinks
+func (r markdownRenderer) plain(node ast.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *ast.Text:
+			value := n.Segment.Value(r.source)
+			if !n.IsRaw() {
+				value = unescape(value)
+			}
+			b.Write(value)
+			if n.HardLineBreak() || n.SoftLineBreak() {
+				b.WriteString(" ")
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.AutoLink:
+			b.Write(n.URL(r.source))
+		case *ast.RawHTML:
+		default:
+			b.WriteString(r.plain(n))
+		}
+	}
+	return b.String()
+}
+
+// unescape resolves backslash escapes and character references
+func unescape(value []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+}
+
+// wrapLines wraps styled text to width, keeping its line breaks
+func wrapLines(s string, width int) []string {
+	if s == "" {
+		return nil
+	}
+	wrapped := lipgloss.NewStyle().Width(width).Render(s)
+	lines := strings.Split(wrapped, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+// prefixLines prefixes the first line with first and the others with rest
+func prefixLines(lines []string, first, rest string) []string {
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return lines
+}
+
+func maxLineWidth(lines []string) int {
+	width := 0
+	for _, line := range lines {
+		width = max(width, lipgloss.Width(line))
+	}
+	return width
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,146 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hassek/bc-cli/api"
+)
+
+// Tests run without a terminal, so the rendered Markdown has no colors and the
+// expected output is its layout alone
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		width  int
+		want   string
+	}{
+		{
+			name:   "Headings",
+			source: "# Pour over\n\n## Steps\n\nText",
+			width:  40,
+			want:   "Pour over\n─────────\n\nSteps\n\nText",
+		},
+		{
+			name:   "Emphasis, code and escapes",
+			source: "Use **fresh** *beans*, `93C` water \\*always\\* &amp; ~~never~~ stale ones",
+			width:  80,
+			want:   "Use fresh beans, 93C water *always* & never stale ones",
+		},
+		{
+			name:   "Paragraphs wrap to the width",
+			source: "Grind the beans right before brewing\nto keep their aroma.",
+			width:  20,
+			want:   "Grind the beans\nright before brewing\nto keep their aroma.",
+		},
+		{
+			name:   "Hard line breaks",
+			source: "one  \ntwo\\\nthree",
+			width:  40,
+			want:   "one\ntwo\nthree",
+		},
+		{
+			name:   "Lists",
+			source: "1. Grind\n2. Brew\n   - slowly\n   - evenly\n\n- [x] done\n- [ ] todo",
+			width:  40,
+			want:   "1. Grind\n2. Brew\n   ◦ slowly\n   ◦ evenly\n\n• ☑ done\n• ☐ todo",
+		},
+		{
+			name:   "List items wrap with a hanging indent",
+			source: "- Heat the water to ninety three degrees",
+			width:  20,
+			want:   "• Heat the water to\n  ninety three\n  degrees",
+		},
+		{
+			name:   "Block quotes",
+			source: "> Coffee is\n> a **ritual**\n>\n> Second",
+			width:  40,
+			want:   "│ Coffee is a ritual\n│\n│ Second",
+		},
+		{
+			name:   "Code blocks keep their lines",
+			source: "```go\nfmt.Println(\"hi\")\n\tx := 1\n```",
+			width:  40,
+			want:   "  fmt.Println(\"hi\")\n      x := 1",
+		},
+		{
+			name:   "Links show their destination",
+			source: "See [the guide](https://butler.coffee/guide) or <https://butler.coffee>.",
+			width:  80,
+			want:   "See the guide (https://butler.coffee/guide) or https://butler.coffee.",
+		},
+		{
+			name:   "Tables",
+			source: "| Method | Ratio | Time |\n|:--|:-:|--:|\n| V60 | 1:16 | 3 min |\n| Espresso | 1:2 | 30 s |",
+			width:  40,
+			want: "Method   │ Ratio │  Time\n" +
+				"─────────┼───────┼──────\n" +
+				"V60      │ 1:16  │ 3 min\n" +
+				"Espresso │  1:2  │  30 s",
+		},
+		{
+			name:   "Tables narrow to the width",
+			source: "| Method | Notes |\n|--|--|\n| V60 | Pour in slow circles |",
+			width:  20,
+			want: "Method │ Notes\n" +
+				"───────┼────────────\n" +
+				"V60    │ Pour in\n" +
+				"       │ slow\n" +
+				"       │ circles",
+		},
+		{
+			name:   "Thematic breaks span the width",
+			source: "Above\n\n---\n\nBelow",
+			width:  20,
+			want:   "Above\n\n" + strings.Repeat("─", 20) + "\n\nBelow",
+		},
+		{
+			name:   "Styling already in the text is kept",
+			source: "A \x1b[1mbold\x1b[0m word",
+			width:  40,
+			want:   "A \x1b[1mbold\x1b[0m word",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.source, tt.width); got != tt.want {
+				t.Errorf("RenderMarkdown() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownCapsTheWidth(t *testing.T) {
+	got := RenderMarkdown(strings.Repeat("word ", 100), 500)
+	for _, line := range strings.Split(got, "\n") {
+		if len(line) > MaxMarkdownWidth {
+			t.Fatalf("line of %d characters, want at most %d: %q", len(line), MaxMarkdownWidth, line)
+		}
+	}
+}
+
+func TestRenderArticleContentRendersMarkdown(t *testing.T) {
+	article := &api.Article{
+		Title:   "Pour over",
+		Content: "## Steps\n\n- Use {{highlight \"fresh\"}} beans",
+	}
+	got, err := RenderArticleContent(article, 60)
+	if err != nil {
+		t.Fatalf("RenderArticleContent() error = %v", err)
+	}
+	if strings.Contains(got, "##") || !strings.Contains(got, "• Use fresh beans") {
+		t.Errorf("RenderArticleContent() = %q, want rendered Markdown", got)
+	}
+}
+
+func TestMarkdownText(t *testing.T) {
+	source := "# Dialing *In*\n\nUse a [fine grind](https://example.com) and\n{{highlight \"taste\"}} it.\n\n" +
+		"- one\n- two\n\n```\nbrew --slow\n```\n\n<div>html</div>\n\n| Ratio | Time |\n| --- | --- |\n| 1:2 | 30s |"
+	want := "Dialing In\nUse a fine grind and taste it.\none\ntwo\nbrew --slow\nRatio\nTime\n1:2\n30s"
+	if got := MarkdownText(source); got != want {
+		t.Errorf("MarkdownText() =\n%q\nwant\n%q", got, want)
+	}
+}
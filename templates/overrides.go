@@ -0,0 +1,172 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hassek/bc-cli/tui/styles"
+)
+
+// OverrideExt is the extension of template override files
+const OverrideExt = ".tmpl"
+
+// TemplateListTemplate lists the templates for the templates command
+const TemplateListTemplate = `{{range .Templates}}{{if .Overridden}}{{green "●"}}{{else}}{{faint "○"}}{{end}} {{.Name}}{{if .Overridden}} {{faint .Path}}{{end}}
+{{end}}
+{{faint "● overridden  ○ built-in"}}
+`
+
+// builtin maps the template names to the built-in templates. A file named after a
+// template in the overrides directory, e.g. order-summary.tmpl, replaces it.
+var builtin = map[string]string{
+	"action-cancelled":            ActionCancelledTemplate,
+	"active-subscriptions":        ActiveSubscriptionsTemplate,
+	"already-logged-in":           AlreadyLoggedInTemplate,
+	"article-content":             ArticleContentTemplate,
+	"article-list":                ArticleListTemplate,
+	"authenticating":              AuthenticatingTemplate,
+	"cancel-double-confirm":       CancelDoubleConfirmTemplate,
+	"cancel-warning":              CancelWarningTemplate,
+	"category-list":               CategoryListTemplate,
+	"checkout-header":             CheckoutHeaderTemplate,
+	"current-subscription-config": CurrentSubscriptionConfigTemplate,
+	"login-success":               LoginSuccessTemplate,
+	"logout-success":              LogoutSuccessTemplate,
+	"manage-not-authenticated":    ManageNotAuthenticatedTemplate,
+	"manage-subscription-header":  ManageSubscriptionHeaderTemplate,
+	"no-actions-available":        NoActionsAvailableTemplate,
+	"no-orders":                   NoOrdersTemplate,
+	"no-subscriptions":            NoSubscriptionsTemplate,
+	"not-logged-in":               NotLoggedInTemplate,
+	"order-config-intro":          OrderConfigIntroTemplate,
+	"order-list":                  OrderListTemplate,
+	"order-show":                  OrderShowTemplate,
+	"order-split-intro":           OrderSplitIntroTemplate,
+	"order-summary":               OrderSummaryTemplate,
+	"pause-confirm-with-date":     PauseConfirmWithDateTemplate,
+	"pause-warning":               PauseWarningTemplate,
+	"plan-list":                   PlanListTemplate,
+	"preference-header":           PreferenceHeaderTemplate,
+	"product-details":             ProductDetailsTemplate,
+	"progress-bar":                ProgressBarTemplate,
+	"resume-info":                 ResumeInfoTemplate,
+	"split-order-intro":           SplitOrderIntroTemplate,
+	"subscription-cancelled":      SubscriptionCancelledTemplate,
+	"subscription-details":        SubscriptionDetailsTemplate,
+	"subscription-list":           SubscriptionListTemplate,
+	"subscription-paused":         SubscriptionPausedTemplate,
+	"subscription-resumed":        SubscriptionResumedTemplate,
+	"subscription-show":           SubscriptionShowTemplate,
+	"subscription-updated":        SubscriptionUpdatedTemplate,
+	"success-art":                 SuccessArtTemplate,
+	"success-message":             SuccessMessageTemplate,
+	"template-list":               TemplateListTemplate,
+	"uniform-order-intro":         UniformOrderIntroTemplate,
+	"update-subscription-header":  UpdateSubscriptionHeaderTemplate,
+}
+
+// names maps the built-in templates back to their names, so Render can tell when it
+// is given one
+var names = make(map[string]string, len(builtin))
+
+func init() {
+	for name, text := range builtin {
+		names[text] = name
+	}
+}
+
+// overrides holds the parsed overrides loaded by LoadOverrides, by template name
+var overrides = map[string]*template.Template{}
+
+// Names returns the names of the built-in templates, sorted
+func Names() []string {
+	list := make([]string, 0, len(builtin))
+	for name := range builtin {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Builtin returns the built-in template called name
+func Builtin(name string) (string, bool) {
+	text, ok := builtin[name]
+	return text, ok
+}
+
+// Validate reports whether text parses as a template
+func Validate(text string) error {
+	_, err := parseTemplate(text)
+	return err
+}
+
+// LoadOverrides reads the template overrides in dir, replacing any loaded before.
+// Overrides are checked here rather than when they are used: a file that doesn't parse
+// or doesn't name a template is skipped and reported, and the built-in template stays
+// in use. A missing dir means no overrides.
+func LoadOverrides(dir string) []error {
+	overrides = map[string]*template.Template{}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []error{fmt.Errorf("failed to read template overrides: %w", err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), OverrideExt)
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if _, ok := builtin[name]; !ok {
+			errs = append(errs, fmt.Errorf("template override %s doesn't match any template, ignoring it", entry.Name()))
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read template override %s, using the built-in template: %w", entry.Name(), err))
+			continue
+		}
+		t, err := parseTemplate(string(data))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("template override %s is invalid, using the built-in template: %w", entry.Name(), err))
+			continue
+		}
+		overrides[name] = t
+	}
+	return errs
+}
+
+// renderOverride renders the override of a built-in template to w and reports whether
+// there was one. An override failing on the data, e.g. on a field that doesn't exist,
+// is reported and the caller falls back to the built-in template.
+func renderOverride(w io.Writer, tmpl string, data any) (bool, error) {
+	name, ok := names[tmpl]
+	if !ok {
+		return false, nil
+	}
+	t, ok := overrides[name]
+	if !ok {
+		return false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		_, err = fmt.Fprintf(w, "%s\n", styles.WarningStyle.Render(fmt.Sprintf(
+			"Warning: template override %s failed: %v; using the built-in template", name+OverrideExt, err)))
+		return false, err
+	}
+	_, err := buf.WriteTo(w)
+	return true, err
+}
@@ -0,0 +1,87 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinNamesAreUnique(t *testing.T) {
+	// Render finds overrides by the text of the built-in template, so two templates
+	// with the same text would share one name
+	if len(names) != len(builtin) {
+		t.Errorf("%d built-in templates but %d distinct texts", len(builtin), len(names))
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"no-orders.tmpl":        "No orders here\n",
+		"no-subscriptions.tmpl": "{{if}}\n",
+		"not-a-template.tmpl":   "hello\n",
+		"notes.txt":             "ignored\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { LoadOverrides(t.TempDir()) })
+
+	errs := LoadOverrides(dir)
+	if len(errs) != 2 {
+		t.Fatalf("LoadOverrides() returned %d errors, want 2: %v", len(errs), errs)
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{name: "Valid override replaces the template", tmpl: NoOrdersTemplate, want: "No orders here"},
+		{name: "Invalid override keeps the built-in", tmpl: NoSubscriptionsTemplate, want: "You don't have any subscriptions yet."},
+		{name: "Template without an override", tmpl: ActionCancelledTemplate, want: "Pause cancelled."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderToString(tt.tmpl, struct{ Action string }{"Pause"})
+			if err != nil {
+				t.Fatalf("RenderToString() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("RenderToString() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadOverridesMissingDir(t *testing.T) {
+	if errs := LoadOverrides(filepath.Join(t.TempDir(), "missing")); errs != nil {
+		t.Errorf("LoadOverrides() = %v, want no errors", errs)
+	}
+}
+
+func TestOverrideFallsBackOnExecutionError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "action-cancelled.tmpl"), []byte("{{.Missing.Field}}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { LoadOverrides(t.TempDir()) })
+	if errs := LoadOverrides(dir); errs != nil {
+		t.Fatalf("LoadOverrides() = %v", errs)
+	}
+
+	got, err := RenderToString(ActionCancelledTemplate, struct{ Action string }{"Pause"})
+	if err != nil {
+		t.Fatalf("RenderToString() error = %v", err)
+	}
+	if !strings.Contains(got, "action-cancelled.tmpl failed") {
+		t.Errorf("RenderToString() = %q, want a warning about the override", got)
+	}
+	if !strings.HasSuffix(got, "Pause cancelled.\n") {
+		t.Errorf("RenderToString() = %q, want the built-in template after the warning", got)
+	}
+}
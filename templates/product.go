@@ -12,3 +12,33 @@ Description:
 
 {{repeat "═" 60}}
 `
+
+const PlanListTemplate = `{{range .Plans}}┌─ {{.Name}}{{if .Tier}} ({{.Tier}}){{end}}
+│  {{.Price}} {{.Currency}}{{if .BillingPeriod}}/{{.BillingPeriod}}{{end}}{{if .MaxQuantity}} • {{.MinQuantity}}-{{.MaxQuantity}} units{{end}}
+{{if .Summary}}│  {{.Summary}}
+{{end}}└─ ID: {{.ID}}
+
+{{end}}`
+
+const NoOrdersTemplate = `You don't have any orders yet.
+
+To place one, run: bc-cli products or bc-cli subscriptions
+`
+
+const OrderListTemplate = `{{range .Orders}}┌─ {{.ID}}
+│  Status: {{.Status | upper}}{{if .Tier}} • Tier: {{.Tier}}{{end}}
+│  Quantity: {{.TotalQuantity}}
+└─ Created: {{.CreatedOn}}
+
+{{end}}`
+
+const OrderShowTemplate = `Order {{.ID}}
+
+Status: {{.Status | upper}}
+{{if .Tier}}Tier: {{.Tier}}
+{{end}}Created: {{.CreatedOn}}
+{{if .ExpectedShipment}}Expected Shipment: {{.ExpectedShipment}}
+{{end}}
+Total: {{.TotalQuantity}}
+{{range $i, $item := .LineItems}}  {{add $i 1}}. {{$item}}
+{{end}}`
@@ -215,20 +215,6 @@ How your coffee is currently prepared:
 
 `
 
-const UpdatePreferencesSummaryTemplate = `
-{{repeat "─" 60}}
-New Subscription Preferences:
-{{repeat "─" 60}}
-
-Total: {{.TotalQuantity}} per month
-
-How your coffee will be prepared:
-{{range $i, $item := .LineItems}}  {{add $i 1}}. {{$item}}
-{{end}}
-{{repeat "─" 60}}
-
-`
-
 const SubscriptionUpdatedTemplate = `
 ✓ Subscription updated successfully!
 
@@ -261,3 +247,22 @@ you can always start a new subscription with: bc-cli subscriptions
 const ActionCancelledTemplate = `
 {{.Action}} cancelled.
 `
+
+// Scriptable Subscription Templates
+
+const SubscriptionListTemplate = `{{printf "%-38s %-12s %-10s %s" "ID" "TIER" "STATUS" "QUANTITY"}}
+{{range .Subscriptions}}{{printf "%-38s %-12s %-10s %d" .ID .Tier .Status .SumQuantity}}
+{{end}}`
+
+const SubscriptionShowTemplate = `{{.StatusIcon}} {{.Tier | upper}} ({{.ID}})
+
+Status: {{.Status | upper}}
+{{if .StartedAt}}Started: {{.StartedAt}}
+{{end}}{{if .ExpiresAt}}Expires: {{.ExpiresAt}}
+{{end}}{{if .HasNextShipment}}Next Shipment: {{.NextShipment}}
+{{end}}{{if .HasPricing}}Billing: {{.Price}} {{.Currency}}/{{.BillingPeriod}}
+{{end}}{{if .HasOrderDetails}}
+Order Configuration:
+  Total: {{.TotalQuantity}} per month
+{{range $i, $item := .LineItems}}  {{add $i 1}}. {{$item}}
+{{end}}{{end}}`
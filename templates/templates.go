@@ -3,11 +3,11 @@ package templates
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
 	"text/template"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui"
 	"github.com/hassek/bc-cli/tui/components"
 	"github.com/hassek/bc-cli/tui/styles"
 	"github.com/hassek/bc-cli/utils"
@@ -28,46 +28,20 @@ var funcMap = template.FuncMap{
 		}
 		return float64(current) / float64(total) * 100
 	},
-	"progressBar": func(current, total, width int) string {
-		if total == 0 {
-			return strings.Repeat("░", width)
-		}
-		percentage := float64(current) / float64(total)
-		filled := int(percentage * float64(width))
-		bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
-		return bar
-	},
-	"wrap": func(text string, width int) string {
-		return utils.WrapText(text, width)
-	},
+	"progressBar": progressBar,
+	"wrap":        utils.WrapText,
 	"wrapAuto": func(text string) string {
-		// Auto-detect terminal width and use most of it with margins
-		termWidth := utils.GetTerminalWidth()
-		// Use 90% of terminal width with reasonable min/max bounds
-		maxWidth := min(
-			// Minimum width for readability
-			max(int(float64(termWidth)*0.9),
-
-				60),
-			// Maximum width for readability
-			120)
-		return utils.WrapText(text, maxWidth)
+		return utils.WrapText(text, autoWidth())
 	},
 	// Style functions for inline text formatting
 	"highlight": func(text string) string {
 		return styles.ActiveStyle.Render(text) // Bold cyan
 	},
 	"emphasis": func(text string) string {
-		return lipgloss.NewStyle().
-			Italic(true).
-			Foreground(lipgloss.Color("247")).
-			Render(text)
+		return styles.EmphasisStyle.Render(text)
 	},
 	"section": func(text string) string {
-		return lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("214")). // Orange/yellow
-			Render(text)
+		return styles.SectionStyle.Render(text)
 	},
 	"bold": func(text string) string {
 		return lipgloss.NewStyle().Bold(true).Render(text)
@@ -78,59 +52,81 @@ var funcMap = template.FuncMap{
 	"faintNoWrap": func(text string) string {
 		// Faint text without wrapping - useful for dividers/lines
 		style := lipgloss.NewStyle().
-			Foreground(styles.Faint).
+			Foreground(styles.Muted).
 			Width(0) // Don't wrap
 		return style.Render(text)
 	},
 	"cyan": func(text string) string {
-		return lipgloss.NewStyle().Foreground(styles.Cyan).Render(text)
+		return lipgloss.NewStyle().Foreground(styles.Primary).Render(text)
 	},
 	"green": func(text string) string {
-		return lipgloss.NewStyle().Foreground(styles.Green).Render(text)
+		return lipgloss.NewStyle().Foreground(styles.Success).Render(text)
 	},
 	"yellow": func(text string) string {
-		return lipgloss.NewStyle().Foreground(styles.Yellow).Render(text)
+		return lipgloss.NewStyle().Foreground(styles.Warning).Render(text)
 	},
 	"red": func(text string) string {
-		return lipgloss.NewStyle().Foreground(styles.Red).Render(text)
+		return lipgloss.NewStyle().Foreground(styles.Error).Render(text)
 	},
 	// Paragraph styling with wrapping
-	"paragraph": func(text string, width int) string {
-		style := lipgloss.NewStyle().
-			Width(width).
-			MarginBottom(1)
-		return style.Render(text)
-	},
+	"paragraph": paragraph,
 	"paragraphAuto": func(text string) string {
-		// Auto-detect terminal width and use most of it with margins
-		termWidth := utils.GetTerminalWidth()
-		// Use 90% of terminal width with reasonable min/max bounds
-		maxWidth := min(
-			// Minimum width for readability
-			max(int(float64(termWidth)*0.9),
-
-				60),
-			// Maximum width for readability
-			120)
 		style := lipgloss.NewStyle().
-			Width(maxWidth).
+			Width(autoWidth()).
 			MarginBottom(1)
 		return style.Render(text)
 	},
 }
 
-// Render renders a template with the given data
+// autoWidth is the width text is wrapped to by wrapAuto and paragraphAuto: most of the
+// terminal, with margins
+func autoWidth() int {
+	// Use 90% of terminal width with reasonable min/max bounds
+	return min(
+		// Minimum width for readability
+		max(int(float64(utils.GetTerminalWidth())*0.9), 60),
+		// Maximum width for readability
+		120)
+}
+
+func progressBar(current, total, width int) string {
+	if total == 0 {
+		return strings.Repeat("░", width)
+	}
+	percentage := float64(current) / float64(total)
+	filled := int(percentage * float64(width))
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+	return bar
+}
+
+func paragraph(text string, width int) string {
+	style := lipgloss.NewStyle().
+		Width(width).
+		MarginBottom(1)
+	return style.Render(text)
+}
+
+// Render renders a template with the given data. Built-in templates are replaced by
+// their override when one is loaded, see LoadOverrides.
 func Render(w io.Writer, tmpl string, data any) error {
-	t, err := template.New("output").Funcs(funcMap).Parse(tmpl)
+	if done, err := renderOverride(w, tmpl, data); done || err != nil {
+		return err
+	}
+
+	t, err := parseTemplate(tmpl)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 	return t.Execute(w, data)
 }
 
-// RenderToStdout renders a template to stdout
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(funcMap).Parse(text)
+}
+
+// RenderToStdout renders a template to stdout, or to the app shell while one runs
 func RenderToStdout(tmpl string, data any) error {
-	return Render(os.Stdout, tmpl, data)
+	return Render(tui.Output(), tmpl, data)
 }
 
 // RenderToString renders a template to a string
@@ -150,22 +146,3 @@ func RenderInViewport(title, tmpl string, data any) error {
 	}
 	return components.ShowTextViewer(title, content)
 }
-
-// RenderDescription renders a description string that may contain template syntax.
-// If the description contains template markers ({{ }}), it renders them.
-// Otherwise, it returns the description as-is.
-// This allows backend CMS to send descriptions with highlighting and formatting.
-func RenderDescription(description string) string {
-	// Check if description contains template syntax
-	if !strings.Contains(description, "{{") {
-		return description
-	}
-
-	// Render as template
-	rendered, err := RenderToString(description, nil)
-	if err != nil {
-		// If template rendering fails, show error for debugging
-		return fmt.Sprintf("Template rendering error: %v\n\nOriginal content:\n%s", err, description)
-	}
-	return rendered
-}
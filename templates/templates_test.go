@@ -3,6 +3,8 @@ package templates
 import (
 	"strings"
 	"testing"
+
+	"github.com/hassek/bc-cli/api"
 )
 
 func TestStyleFunctions(t *testing.T) {
@@ -224,3 +226,32 @@ func TestSubscriptionDescriptionWithTemplates(t *testing.T) {
 		}
 	}
 }
+
+func TestSubscriptionListShowsTotalQuantity(t *testing.T) {
+	data := struct {
+		Subscriptions []api.Subscription
+	}{
+		Subscriptions: []api.Subscription{
+			{ID: "sub-1", Tier: "explorer", Status: "active", DefaultQuantity: 2, DefaultPreferences: []api.SubscriptionPreference{
+				{Quantity: 2, BrewingMethod: "espresso", GrindType: "ground"},
+				{Quantity: 3, BrewingMethod: "v60", GrindType: "whole_bean"},
+			}},
+			{ID: "sub-2", Tier: "daily", Status: "paused", DefaultQuantity: 4},
+		},
+	}
+
+	out, err := RenderToString(SubscriptionListTemplate, data)
+	if err != nil {
+		t.Fatalf("RenderToString() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want a header and 2 rows:\n%s", len(lines), out)
+	}
+	for i, want := range []string{"5", "4"} {
+		if fields := strings.Fields(lines[i+1]); fields[len(fields)-1] != want {
+			t.Errorf("row %d = %q, want quantity %s", i+1, lines[i+1], want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package app runs the interactive flows inside a single long-lived Bubble Tea program.
+//
+// The root model keeps the duck, the stack of the running flows, shown as a breadcrumb,
+// and the current screen. Flows stay plain sequential code: every picker, prompt and
+// viewer they open through the tui package is pushed onto the shell as a child screen
+// instead of starting a program of its own, so switching screens doesn't flicker or
+// restart the duck.
+package app
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
+)
+
+// screen is a child model shown by the shell
+type screen struct {
+	model  tea.Model
+	header bool          // draw the duck above the screen
+	notice string        // output the flow printed before showing the screen
+	done   chan struct{} // closed when the screen quits
+}
+
+// frame is a flow running in the shell
+type frame struct {
+	name string
+}
+
+type (
+	showMsg       struct{ screen *screen }
+	screenDoneMsg struct{ screen *screen }
+	pushFrameMsg  struct{ frame *frame }
+	popFrameMsg   struct{ frame *frame }
+	badgeMsg      string
+	flowDoneMsg   struct{}
+	outputMsg     string
+)
+
+// model is the root model of the shell
+type model struct {
+	duck     *components.DuckComponent
+	frames   []*frame // the running flows, outermost first
+	badge    string   // shown after the breadcrumb, e.g. "offline"
+	current  *screen  // nil while the flow is busy, e.g. loading data
+	output   string   // printed by the flow since the last screen was shown
+	size     tea.WindowSizeMsg
+	wentBack bool // the last key was the back key
+	quitting bool // the user quit with Ctrl+C
+}
+
+func newModel(name, badge string) *model {
+	return &model{
+		duck:   components.NewDuckComponent(),
+		frames: []*frame{{name: name}},
+		badge:  badge,
+	}
+}
+
+func (m *model) Init() tea.Cmd {
+	return m.duck.Init()
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+		return m, m.updateCurrent(m.screenSize())
+
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.Active().Quit) {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.wentBack = key.Matches(msg, keys.Active().Back)
+
+	case tea.MouseMsg:
+		// Screens hit test from their own top row, below the shell's chrome
+		msg.Y -= lineCount(m.chrome())
+		return m, m.updateCurrent(msg)
+
+	case outputMsg:
+		m.output += string(msg)
+		return m, nil
+
+	case showMsg:
+		m.current = msg.screen
+		m.current.notice = m.output
+		m.output = ""
+		m.wentBack = false
+		cmds := []tea.Cmd{wrap(msg.screen, msg.screen.model.Init())}
+		if m.size.Width > 0 {
+			cmds = append(cmds, m.updateCurrent(m.screenSize()))
+		}
+		return m, tea.Batch(cmds...)
+
+	case screenDoneMsg:
+		if msg.screen != m.current {
+			return m, nil
+		}
+		// Back means back, anything else finished the screen with a choice
+		if msg.screen.header && !m.wentBack {
+			m.duck.TriggerAction()
+		}
+		m.current = nil
+		close(msg.screen.done)
+		return m, nil
+
+	case pushFrameMsg:
+		m.frames = append(m.frames, msg.frame)
+		return m, nil
+
+	case popFrameMsg:
+		// The frame goes with any a nested flow failed to pop, never the root one
+		if i := slices.Index(m.frames, msg.frame); i > 0 {
+			m.frames = m.frames[:i]
+		}
+		return m, nil
+
+	case badgeMsg:
+		m.badge = string(msg)
+		return m, nil
+
+	case flowDoneMsg:
+		return m, tea.Quit
+	}
+
+	var duckCmd tea.Cmd
+	m.duck, duckCmd = m.duck.Update(msg)
+	return m, tea.Batch(duckCmd, m.updateCurrent(msg))
+}
+
+// updateCurrent passes msg to the current screen
+func (m *model) updateCurrent(msg tea.Msg) tea.Cmd {
+	if m.current == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.current.model, cmd = m.current.model.Update(msg)
+	return wrap(m.current, cmd)
+}
+
+// screenSize is the window size left for the current screen below the shell's chrome
+func (m *model) screenSize() tea.WindowSizeMsg {
+	size := m.size
+	size.Height = max(size.Height-lineCount(m.chrome()), 1)
+	return size
+}
+
+// chrome renders everything the shell draws above the current screen
+func (m *model) chrome() string {
+	var b strings.Builder
+	if m.current == nil || m.current.header {
+		b.WriteString(m.duck.View())
+	}
+	names := make([]string, len(m.frames))
+	for i, f := range m.frames {
+		names[i] = f.name
+	}
+	b.WriteString(styles.FaintStyle.Render(strings.Join(names, " › ")))
+	if m.badge != "" {
+		b.WriteString("  " + styles.WarningStyle.Render("● "+m.badge))
+	}
+	b.WriteString("\n\n")
+	notice := m.output
+	if m.current != nil {
+		notice = m.current.notice
+	}
+	if notice = strings.Trim(notice, "\n"); notice != "" {
+		b.WriteString(notice)
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+func (m *model) View() string {
+	if m.quitting {
+		return ""
+	}
+	if m.current == nil {
+		if m.output != "" {
+			return m.chrome()
+		}
+		return m.chrome() + styles.FaintStyle.Render("Loading...")
+	}
+	return m.chrome() + m.current.model.View()
+}
+
+// lineCount counts the lines taken by s
+func lineCount(s string) int {
+	return strings.Count(s, "\n")
+}
+
+// wrap makes a screen's tea.Quit finish the screen instead of the whole program.
+// Screens quit the same way they would as programs of their own, so any model can be
+// hosted unchanged.
+func wrap(s *screen, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case tea.QuitMsg:
+			return screenDoneMsg{screen: s}
+		case tea.BatchMsg:
+			wrapped := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				wrapped[i] = wrap(s, c)
+			}
+			return wrapped
+		default:
+			if cmds, ok := sequenceCmds(msg); ok {
+				wrapped := make([]tea.Cmd, len(cmds))
+				for i, c := range cmds {
+					wrapped[i] = wrap(s, c)
+				}
+				// Sequence drops nil commands and returns a lone one as it is
+				if seq := tea.Sequence(wrapped...); seq != nil {
+					return seq()
+				}
+				return nil
+			}
+			return msg
+		}
+	}
+}
+
+// sequenceCmds returns the commands of a tea.Sequence message. Bubble Tea doesn't
+// export its type, but like tea.BatchMsg it is a list of commands.
+func sequenceCmds(msg tea.Msg) ([]tea.Cmd, bool) {
+	v := reflect.ValueOf(msg)
+	cmdsType := reflect.TypeFor[[]tea.Cmd]()
+	if !v.IsValid() || v.Kind() != reflect.Slice || !v.Type().ConvertibleTo(cmdsType) {
+		return nil, false
+	}
+	return v.Convert(cmdsType).Interface().([]tea.Cmd), true
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quitOnEnter is a screen that quits on Enter, like the pickers and prompts
+type quitOnEnter struct{ label string }
+
+func (q quitOnEnter) Init() tea.Cmd { return nil }
+func (q quitOnEnter) View() string  { return q.label }
+func (q quitOnEnter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+		return q, tea.Batch(nil, tea.Quit)
+	}
+	return q, nil
+}
+
+// run executes cmd and feeds the messages it produces back into m, like the program would
+func run(m *model, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			run(m, c)
+		}
+	case nil:
+	default:
+		_, next := m.Update(msg)
+		run(m, next)
+	}
+}
+
+func TestWrapTurnsQuitIntoScreenDone(t *testing.T) {
+	s := &screen{}
+
+	if msg := wrap(s, tea.Quit)(); msg != (screenDoneMsg{screen: s}) {
+		t.Errorf("wrap(tea.Quit)() = %#v, want screenDoneMsg", msg)
+	}
+
+	batch, ok := wrap(s, tea.Batch(tea.Quit, tea.Quit))().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("wrap(tea.Batch(...))() did not return a tea.BatchMsg")
+	}
+	for i, c := range batch {
+		if msg := c(); msg != (screenDoneMsg{screen: s}) {
+			t.Errorf("batch[%d]() = %#v, want screenDoneMsg", i, msg)
+		}
+	}
+
+	seq := wrap(s, tea.Sequence(tea.Println("first"), tea.Quit))()
+	if _, ok := seq.(tea.BatchMsg); ok {
+		t.Fatal("wrap(tea.Sequence(...))() turned the sequence into a batch")
+	}
+	cmds, ok := sequenceCmds(seq)
+	if !ok || len(cmds) != 2 {
+		t.Fatalf("wrap(tea.Sequence(...))() = %#v, want a sequence of 2 commands", seq)
+	}
+	if msg := cmds[1](); msg != (screenDoneMsg{screen: s}) {
+		t.Errorf("sequence[1]() = %#v, want screenDoneMsg", msg)
+	}
+
+	if _, ok := sequenceCmds(tea.KeyMsg{Type: tea.KeyEnter}); ok {
+		t.Error("sequenceCmds(tea.KeyMsg) found a sequence")
+	}
+
+	if wrap(s, nil) != nil {
+		t.Error("wrap(nil) should be nil")
+	}
+}
+
+func TestModelShowsScreensAndFinishesThem(t *testing.T) {
+	m := newModel("Learn", "")
+
+	run(m, func() tea.Msg { return outputMsg("✓ Article bookmarked!\n") })
+	if view := m.View(); !strings.Contains(view, "✓ Article bookmarked!") {
+		t.Errorf("output should be shown while the flow is busy, got:\n%s", view)
+	}
+
+	first := &screen{model: quitOnEnter{label: "first screen"}, header: true, done: make(chan struct{})}
+	run(m, func() tea.Msg { return showMsg{screen: first} })
+
+	view := m.View()
+	if !strings.Contains(view, "first screen") || !strings.Contains(view, "✓ Article bookmarked!") {
+		t.Errorf("screen should be shown below the output printed before it, got:\n%s", view)
+	}
+	if m.output != "" {
+		t.Errorf("output should move to the screen's notice, still have %q", m.output)
+	}
+
+	brewing := &frame{name: "Brewing"}
+	run(m, func() tea.Msg { return pushFrameMsg{frame: brewing} })
+	if view := m.View(); !strings.Contains(view, "Learn › Brewing") {
+		t.Errorf("breadcrumb missing from view:\n%s", view)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	run(m, cmd)
+
+	select {
+	case <-first.done:
+	default:
+		t.Fatal("screen should be done after it quit")
+	}
+	if m.current != nil {
+		t.Error("finished screen should no longer be current")
+	}
+
+	run(m, func() tea.Msg { return popFrameMsg{frame: brewing} })
+	if view := m.View(); strings.Contains(view, "Brewing") {
+		t.Errorf("popped frame still in breadcrumb:\n%s", view)
+	}
+}
+
+func TestModelCtrlCQuitsTheShell(t *testing.T) {
+	m := newModel("Learn", "")
+	run(m, func() tea.Msg {
+		return showMsg{screen: &screen{model: quitOnEnter{}, done: make(chan struct{})}}
+	})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !m.quitting {
+		t.Error("Ctrl+C should mark the shell as quitting")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Ctrl+C should quit the whole program")
+	}
+}
+
+func TestModelShowsBadge(t *testing.T) {
+	m := newModel("Learn", "")
+	if view := m.View(); strings.Contains(view, "●") {
+		t.Errorf("no badge should be shown by default, got:\n%s", view)
+	}
+
+	run(m, func() tea.Msg { return badgeMsg("offline") })
+	if view := m.View(); !strings.Contains(view, "Learn") || !strings.Contains(view, "● offline") {
+		t.Errorf("badge should follow the breadcrumb, got:\n%s", view)
+	}
+
+	run(m, func() tea.Msg { return badgeMsg("") })
+	if view := m.View(); strings.Contains(view, "offline") {
+		t.Errorf("cleared badge still shown:\n%s", view)
+	}
+}
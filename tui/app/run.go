@@ -0,0 +1,156 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hassek/bc-cli/tui"
+)
+
+var (
+	// mu guards active and badge, read by the flows and by Run
+	mu sync.Mutex
+	// active is the running shell, nil when flows run without one
+	active *session
+	// badge is shown after the breadcrumb of the shells started from now on
+	badge string
+)
+
+// SetBadge shows text after the breadcrumb, e.g. to tell the content is read offline.
+// An empty text removes the badge.
+func SetBadge(text string) {
+	mu.Lock()
+	badge = text
+	s := active
+	mu.Unlock()
+
+	if s != nil {
+		s.program.Send(badgeMsg(text))
+	}
+}
+
+// activeSession returns the running shell, nil when there is none
+func activeSession() *session {
+	mu.Lock()
+	defer mu.Unlock()
+	return active
+}
+
+// Run runs flow inside the app shell, named name in the breadcrumb. Nested calls
+// push a frame for flow onto the running shell and run it there, so the frames are
+// the stack of the flows the user went through: going back out of a flow returns
+// from its Run and pops its frame. In plain mode flow simply runs on its own.
+//
+// While the shell runs, the flow prints with tui.Output: what it prints is shown above
+// the next screen, and output left when the flow returns is printed after the shell
+// exits.
+func Run(name string, flow func() error) error {
+	if s := activeSession(); s != nil {
+		return s.run(name, flow)
+	}
+	if tui.IsPlain() {
+		return flow()
+	}
+
+	mu.Lock()
+	root := newModel(name, badge)
+	s := &session{
+		program: tui.NewProgram(root, tea.WithAltScreen()),
+		quit:    make(chan struct{}),
+	}
+	active = s
+	mu.Unlock()
+	tui.SetShell(s)
+
+	flowErr := make(chan error, 1)
+	go func() {
+		err := flow()
+		flowErr <- err
+		s.program.Send(flowDoneMsg{})
+	}()
+
+	_, runErr := s.program.Run()
+	close(s.quit)
+
+	var result error
+	if root.quitting || runErr != nil {
+		// The flow is left behind, it may be waiting on the network. Whatever it shows
+		// or prints from now on is dropped instead of reaching the terminal.
+		tui.SetQuit()
+		result = runErr
+	} else {
+		result = <-flowErr
+	}
+
+	tui.SetShell(nil)
+	mu.Lock()
+	active = nil
+	mu.Unlock()
+	fmt.Print(s.unshownOutput())
+
+	if errors.Is(result, tui.ErrQuit) {
+		return nil
+	}
+	return result
+}
+
+// session implements tui.Shell for the running program
+type session struct {
+	program *tea.Program
+	quit    chan struct{} // closed when the program exits
+
+	mu sync.Mutex
+	// unshown is the output printed since the last screen was shown, printed when the
+	// shell exits if no screen shows it
+	unshown bytes.Buffer
+}
+
+// run runs a nested flow in a frame of its own
+func (s *session) run(name string, flow func() error) error {
+	f := &frame{name: name}
+	s.program.Send(pushFrameMsg{frame: f})
+	defer s.program.Send(popFrameMsg{frame: f})
+	return flow()
+}
+
+// Write sends output to the model. Messages are delivered in order, so the output
+// reaches the model before any screen the flow shows after printing it.
+func (s *session) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.unshown.Write(p)
+	s.mu.Unlock()
+
+	s.program.Send(outputMsg(p))
+	return len(p), nil
+}
+
+func (s *session) Show(model tea.Model, header bool) error {
+	sc := &screen{
+		model:  model,
+		header: header,
+		done:   make(chan struct{}),
+	}
+	s.program.Send(showMsg{screen: sc})
+
+	// Everything printed so far is the screen's notice now
+	s.mu.Lock()
+	s.unshown.Reset()
+	s.mu.Unlock()
+
+	select {
+	case <-sc.done:
+		return nil
+	case <-s.quit:
+		return tui.ErrQuit
+	}
+}
+
+// unshownOutput returns the output no screen has shown
+func (s *session) unshownOutput() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unshown.String()
+}
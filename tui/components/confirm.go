@@ -1,9 +1,14 @@
 package components
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/keys"
 	"github.com/hassek/bc-cli/tui/styles"
 )
 
@@ -32,32 +37,55 @@ func (c *ConfirmComponent) Init() tea.Cmd {
 func (c *ConfirmComponent) Update(msg tea.Msg) (*ConfirmComponent, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
+		km := keys.Active()
+		switch {
+		case key.Matches(msg, km.Quit, km.Back):
 			c.cancelled = true
 			return c, tea.Quit
 
-		case "enter":
+		case key.Matches(msg, km.Select):
 			c.result = (c.cursor == 0) // Yes is at index 0
 			c.submitted = true
 			return c, tea.Quit
 
-		case "left", "h", "up", "k":
+		case key.Matches(msg, km.Left, km.Up):
 			c.cursor = 0
 
-		case "right", "l", "down", "j":
+		case key.Matches(msg, km.Right, km.Down):
 			c.cursor = 1
 
-		case "y", "Y":
+		case key.Matches(msg, km.Yes):
 			c.result = true
 			c.submitted = true
 			return c, tea.Quit
 
-		case "n", "N":
+		case key.Matches(msg, km.No):
 			c.result = false
 			c.submitted = true
 			return c, tea.Quit
 		}
+
+	case tea.MouseMsg:
+		if !tui.IsClick(msg) {
+			break
+		}
+		if msg.Y == tui.LineCount(c.View()) {
+			return c, c.hints().Click(msg.X)
+		}
+		if msg.Y == tui.LineCount(c.label)+2 {
+			// Options are drawn as a cursor column, the option and two spaces each
+			pos := 0
+			for i, option := range c.options {
+				width := 2 + lipgloss.Width(option)
+				if msg.X >= pos && msg.X < pos+width {
+					c.cursor = i
+					c.result = (i == 0)
+					c.submitted = true
+					return c, tea.Quit
+				}
+				pos += width + 2
+			}
+		}
 	}
 	return c, nil
 }
@@ -86,11 +114,29 @@ func (c *ConfirmComponent) View() string {
 	b.WriteString("\n\n")
 
 	// Help text
-	b.WriteString(styles.FaintStyle.Render("Use ←→ or y/n to select, Enter to confirm, Esc to cancel"))
+	b.WriteString(c.hints().View())
 
 	return b.String()
 }
 
+func (c *ConfirmComponent) hints() Hints {
+	km := keys.Active()
+	return Hints{Hints: []Hint{
+		{Text: fmt.Sprintf("Use %s or %s to select", keys.Label(km.Left, km.Right), keys.Label(km.Yes, km.No))},
+		{Text: keys.Label(km.Select) + " to confirm", Binding: km.Select},
+		{Text: keys.Label(km.Back) + " to cancel", Binding: km.Back},
+	}, Sep: ", "}
+}
+
+// FullHelp lists the key bindings of the confirmation for the help overlay
+func (c *ConfirmComponent) FullHelp() [][]key.Binding {
+	km := keys.Active()
+	return [][]key.Binding{
+		{km.Left, km.Right},
+		{km.Yes, km.No, km.Select, km.Back},
+	}
+}
+
 func (c *ConfirmComponent) Submitted() bool {
 	return c.submitted
 }
@@ -0,0 +1,63 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
+)
+
+// Hint is an action hint in a footer, e.g. "enter to select". With mouse support on,
+// clicking a hint presses its binding.
+type Hint struct {
+	Text    string
+	Binding key.Binding // zero for hints that aren't a single action, e.g. "type to filter"
+}
+
+// Hints is a footer line of hints joined by Sep
+type Hints struct {
+	Hints []Hint
+	Sep   string
+}
+
+// View renders the hints faint
+func (h Hints) View() string {
+	texts := make([]string, len(h.Hints))
+	for i, hint := range h.Hints {
+		texts[i] = hint.Text
+	}
+	return styles.FaintStyle.Render(strings.Join(texts, h.Sep))
+}
+
+// At returns the binding of the hint at column x of the footer
+func (h Hints) At(x int) (key.Binding, bool) {
+	pos := 0
+	for i, hint := range h.Hints {
+		if i > 0 {
+			pos += lipgloss.Width(h.Sep)
+		}
+		width := lipgloss.Width(hint.Text)
+		if x >= pos && x < pos+width {
+			return hint.Binding, hint.Binding.Enabled()
+		}
+		pos += width
+	}
+	return key.Binding{}, false
+}
+
+// Click returns a command pressing the binding of the hint at column x, or nil when
+// there is none. The key goes through the whole program, as if it had been typed.
+func (h Hints) Click(x int) tea.Cmd {
+	b, ok := h.At(x)
+	if !ok {
+		return nil
+	}
+	msg, ok := keys.Press(b)
+	if !ok {
+		return nil
+	}
+	return func() tea.Msg { return msg }
+}
@@ -5,8 +5,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hassek/bc-cli/tui/keys"
 	"github.com/hassek/bc-cli/tui/styles"
 )
 
@@ -39,6 +41,12 @@ func NewInputComponent(label string, min, max, defaultVal int) *InputComponent {
 	}
 }
 
+// FullHelp lists the key bindings of the input for the help overlay
+func (i *InputComponent) FullHelp() [][]key.Binding {
+	km := keys.Active()
+	return [][]key.Binding{{km.Select, km.Back}}
+}
+
 func (i *InputComponent) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -48,12 +56,13 @@ func (i *InputComponent) Update(msg tea.Msg) (*InputComponent, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
+		km := keys.Active()
+		switch {
+		case key.Matches(msg, km.Quit, km.Back):
 			i.cancelled = true
 			return i, tea.Quit
 
-		case "enter":
+		case key.Matches(msg, km.Select):
 			// Validate and submit
 			value := strings.TrimSpace(i.textInput.Value())
 			if value == "" {
@@ -107,7 +116,8 @@ func (i *InputComponent) View() string {
 	helpText := fmt.Sprintf("Enter a number between %d and %d (default: %d)", i.min, i.max, i.defaultVal)
 	b.WriteString(styles.FaintStyle.Render(helpText))
 	b.WriteString("\n")
-	b.WriteString(styles.FaintStyle.Render("Press Enter to confirm, Esc to cancel"))
+	km := keys.Active()
+	b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("Press %s to confirm, %s to cancel", keys.Label(km.Select), keys.Label(km.Back))))
 
 	return b.String()
 }
@@ -2,10 +2,17 @@ package components
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/keys"
 	"github.com/hassek/bc-cli/tui/styles"
+	"github.com/hassek/bc-cli/utils"
 )
 
 const maxVisibleItems = 10
@@ -18,6 +25,21 @@ type SelectItem interface {
 	Details() string
 }
 
+// BadgedItem is a SelectItem with a badge after its label, like a status. Badges
+// aren't matched by the filter.
+type BadgedItem interface {
+	SelectItem
+	Badge() string
+}
+
+// ItemBadge returns the badge of item, empty if it has none
+func ItemBadge(item SelectItem) string {
+	if badged, ok := item.(BadgedItem); ok {
+		return badged.Badge()
+	}
+	return ""
+}
+
 // SimpleItem is a basic implementation of SelectItem
 type SimpleItem struct {
 	LabelText   string
@@ -27,64 +49,328 @@ type SimpleItem struct {
 func (s SimpleItem) Label() string   { return s.LabelText }
 func (s SimpleItem) Details() string { return s.DetailsText }
 
-// SelectComponent handles list selection with cursor navigation
+// labelWeight makes filter matches in an item's label count more than matches in its details
+const labelWeight = 2
+
+// maxDetailsSpread limits how spread out a match in the details may be, relative to the
+// filter length. Details are long enough for nearly any short filter to match somewhere.
+const maxDetailsSpread = 2
+
+// SelectComponent handles list selection with cursor navigation. Typing, or / first,
+// filters the items with fuzzy matching on their labels and details. The multi-select
+// variant checks items with space and submits all checked items with Enter.
 type SelectComponent struct {
 	items        []SelectItem
-	cursor       int
+	matches      []selectMatch // the items passing the filter, best first
+	filter       string
+	filtering    bool
+	cursor       int // index into matches
+	multi        bool
+	checked      map[int]bool // indexes into items, only used by multi-select
 	selected     bool
 	cancelled    bool
 	title        string
 	scrollOffset int
+	onHighlight  func(index int)
+	load         LoadItems // loads more items of a lazily loaded list, nil once all are
+	fixed        int       // trailing items that stay last as more are loaded
+	loading      bool
+	loadErr      error
+}
+
+// LoadItems loads the next items of a lazily loaded list, and reports whether there are
+// more to load
+type LoadItems func() ([]SelectItem, bool, error)
+
+// loadAhead is how close the cursor gets to the last loaded item before more are loaded
+const loadAhead = maxVisibleItems / 2
+
+// itemsLoadedMsg carries the items loaded for a select
+type itemsLoadedMsg struct {
+	target *SelectComponent
+	items  []SelectItem
+	more   bool
+	err    error
+}
+
+// selectMatch is an item passing the filter and the label runes it matched
+type selectMatch struct {
+	index     int
+	positions []int
 }
 
 func NewSelectComponent(title string, items []SelectItem) *SelectComponent {
-	return &SelectComponent{
+	s := &SelectComponent{
 		title:  title,
 		items:  items,
 		cursor: 0,
 	}
+	s.applyFilter()
+	return s
+}
+
+// NewMultiSelectComponent returns a select where any number of items can be checked.
+// checked gives the initially checked items and may be nil.
+func NewMultiSelectComponent(title string, items []SelectItem, checked []bool) *SelectComponent {
+	s := NewSelectComponent(title, items)
+	s.multi = true
+	s.checked = make(map[int]bool)
+	for i, c := range checked {
+		if c && i < len(items) {
+			s.checked[i] = true
+		}
+	}
+	return s
 }
 
 func (s *SelectComponent) Init() tea.Cmd {
-	return nil
+	return s.loadMore()
 }
 
 func (s *SelectComponent) Update(msg tea.Msg) (*SelectComponent, tea.Cmd) {
+	before := s.currentIndex()
+	s, cmd := s.update(msg)
+	if s.selected || s.cancelled {
+		return s, cmd
+	}
+	if after := s.currentIndex(); after != before && after >= 0 {
+		s.loadErr = nil // Moving on retries loading
+		if s.onHighlight != nil {
+			s.onHighlight(after)
+		}
+	}
+	return s, tea.Batch(cmd, s.loadMore())
+}
+
+func (s *SelectComponent) update(msg tea.Msg) (*SelectComponent, tea.Cmd) {
 	switch msg := msg.(type) {
+	case itemsLoadedMsg:
+		if msg.target == s {
+			s.loading = false
+			s.loadErr = msg.err
+			if msg.err == nil {
+				s.addItems(msg.items, msg.more)
+			}
+		}
+		return s, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
-			s.cancelled = true
-			return s, tea.Quit
+		if s.filtering {
+			return s.updateFilter(msg)
+		}
 
-		case "enter":
-			s.selected = true
+		km := keys.Active()
+		switch {
+		case key.Matches(msg, km.Quit, km.Back):
+			s.cancelled = true
 			return s, tea.Quit
 
-		case "up", "k":
-			if s.cursor > 0 {
-				s.cursor--
-				s.adjustScroll()
+		case key.Matches(msg, km.Select):
+			// Submitting no checked items is a valid answer for a multi-select
+			if s.multi || s.current() != nil {
+				s.selected = true
+				return s, tea.Quit
 			}
 
-		case "down", "j":
-			if s.cursor < len(s.items)-1 {
-				s.cursor++
-				s.adjustScroll()
-			}
+		case s.multi && key.Matches(msg, km.Toggle):
+			s.toggle()
+
+		case s.multi && key.Matches(msg, km.ToggleAll):
+			s.toggleAll()
+
+		case key.Matches(msg, km.Up):
+			s.moveCursor(-1)
+
+		case key.Matches(msg, km.Down):
+			s.moveCursor(1)
 
-		case "home", "g":
+		case key.Matches(msg, km.Top):
 			s.cursor = 0
 			s.scrollOffset = 0
 
-		case "end", "G":
-			s.cursor = len(s.items) - 1
+		case key.Matches(msg, km.Bottom):
+			s.cursor = max(len(s.matches)-1, 0)
 			s.adjustScroll()
+
+		case key.Matches(msg, km.Filter):
+			s.filtering = true
+
+		case msg.Type == tea.KeyRunes && !msg.Alt && !key.Matches(msg, km.Help):
+			// Any other text starts filtering with it
+			s.filtering = true
+			s.setFilter(s.filter + string(msg.Runes))
+		}
+
+	case tea.MouseMsg:
+		return s.updateMouse(msg)
+	}
+	return s, nil
+}
+
+// updateFilter handles keys while the filter is being typed, where text goes into the
+// filter before any binding is considered
+func (s *SelectComponent) updateFilter(msg tea.KeyMsg) (*SelectComponent, tea.Cmd) {
+	km := keys.Active()
+	switch {
+	case (msg.Type == tea.KeyRunes && !msg.Alt) || msg.Type == tea.KeySpace:
+		s.setFilter(s.filter + string(msg.Runes))
+
+	case msg.Type == tea.KeyBackspace:
+		if s.filter == "" {
+			s.filtering = false
+			break
+		}
+		runes := []rune(s.filter)
+		s.setFilter(string(runes[:len(runes)-1]))
+
+	case msg.Type == tea.KeyCtrlU:
+		s.setFilter("")
+
+	case key.Matches(msg, km.Quit):
+		s.cancelled = true
+		return s, tea.Quit
+
+	case key.Matches(msg, km.Back):
+		// Back clears the filter first and cancels only once it is empty
+		if s.filter == "" {
+			s.cancelled = true
+			return s, tea.Quit
+		}
+		s.filtering = false
+		s.setFilter("")
+
+	case key.Matches(msg, km.Select):
+		if s.multi || s.current() != nil {
+			s.selected = true
+			return s, tea.Quit
 		}
+
+	case s.multi && key.Matches(msg, km.Toggle):
+		s.toggle()
+
+	case s.multi && key.Matches(msg, km.ToggleAll):
+		s.toggleAll()
+
+	case key.Matches(msg, km.Up):
+		s.moveCursor(-1)
+
+	case key.Matches(msg, km.Down):
+		s.moveCursor(1)
 	}
 	return s, nil
 }
 
+// toggle checks or unchecks the item under the cursor of a multi-select
+func (s *SelectComponent) toggle() {
+	if !s.multi || s.current() == nil {
+		return
+	}
+	index := s.matches[s.cursor].index
+	s.checked[index] = !s.checked[index]
+	s.moveCursor(1)
+}
+
+// toggleAll checks every item passing the filter, or unchecks them if all were checked
+func (s *SelectComponent) toggleAll() {
+	if !s.multi {
+		return
+	}
+	all := true
+	for _, match := range s.matches {
+		all = all && s.checked[match.index]
+	}
+	for _, match := range s.matches {
+		s.checked[match.index] = !all
+	}
+}
+
+// loadMore starts loading more items when the cursor nears the last loaded one, or
+// when filtering, which needs every item
+func (s *SelectComponent) loadMore() tea.Cmd {
+	if s.load == nil || s.loading || s.loadErr != nil {
+		return nil
+	}
+	if s.filter == "" && s.cursor < len(s.matches)-s.fixed-loadAhead {
+		return nil
+	}
+
+	s.loading = true
+	load := s.load
+	return func() tea.Msg {
+		items, more, err := load()
+		return itemsLoadedMsg{target: s, items: items, more: more, err: err}
+	}
+}
+
+// addItems inserts loaded items before the fixed ones, keeping the cursor on its item
+func (s *SelectComponent) addItems(items []SelectItem, more bool) {
+	current := s.currentIndex()
+	at := len(s.items) - s.fixed
+	s.items = slices.Insert(s.items, at, items...)
+	if !more {
+		s.load = nil
+	}
+
+	s.applyFilter()
+	if current >= at {
+		current += len(items)
+	}
+	for i, match := range s.matches {
+		if match.index == current {
+			s.cursor = i
+			break
+		}
+	}
+	s.adjustScroll()
+}
+
+func (s *SelectComponent) moveCursor(delta int) {
+	cursor := s.cursor + delta
+	if cursor >= 0 && cursor < len(s.matches) {
+		s.cursor = cursor
+		s.adjustScroll()
+	}
+}
+
+func (s *SelectComponent) setFilter(filter string) {
+	s.filter = filter
+	s.applyFilter()
+	s.cursor = 0
+	s.scrollOffset = 0
+}
+
+// applyFilter ranks the items matching the filter, keeping the original order
+// between equal scores
+func (s *SelectComponent) applyFilter() {
+	s.matches = s.matches[:0]
+	scores := make(map[int]int, len(s.items))
+	for i, item := range s.items {
+		labelScore, positions, labelOK := utils.FuzzyMatch(s.filter, item.Label())
+		detailsScore, detailsPositions, detailsOK := utils.FuzzyMatch(s.filter, item.Details())
+		if len(detailsPositions) > 0 {
+			spread := detailsPositions[len(detailsPositions)-1] - detailsPositions[0] + 1
+			detailsOK = detailsOK && spread <= maxDetailsSpread*len(detailsPositions)
+		}
+		if !labelOK && !detailsOK {
+			continue
+		}
+
+		score := 0
+		if labelOK {
+			score = labelScore * labelWeight
+		}
+		if detailsOK {
+			score = max(score, detailsScore)
+		}
+		scores[i] = score
+		s.matches = append(s.matches, selectMatch{index: i, positions: positions})
+	}
+
+	sort.SliceStable(s.matches, func(a, b int) bool {
+		return scores[s.matches[a].index] > scores[s.matches[b].index]
+	})
+}
+
 func (s *SelectComponent) adjustScroll() {
 	if s.cursor < s.scrollOffset {
 		s.scrollOffset = s.cursor
@@ -93,7 +379,44 @@ func (s *SelectComponent) adjustScroll() {
 	}
 }
 
-func (s *SelectComponent) View() string {
+// current returns the item under the cursor, or nil when nothing matches the filter
+func (s *SelectComponent) current() SelectItem {
+	if i := s.currentIndex(); i >= 0 {
+		return s.items[i]
+	}
+	return nil
+}
+
+// currentIndex returns the index in items of the item under the cursor, or -1
+func (s *SelectComponent) currentIndex() int {
+	if s.cursor >= 0 && s.cursor < len(s.matches) {
+		return s.matches[s.cursor].index
+	}
+	return -1
+}
+
+// highlight renders label with the matched runes picked out
+func highlight(label string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(label)
+	}
+
+	var b strings.Builder
+	next := 0
+	for i, r := range []rune(label) {
+		if next < len(positions) && positions[next] == i {
+			b.WriteString(styles.MatchStyle.Render(string(r)))
+			next++
+		} else {
+			b.WriteString(style.Render(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// header renders the lines above the visible items: the title, the filter line with the
+// match count and the scroll indicator
+func (s *SelectComponent) header() string {
 	var b strings.Builder
 
 	// Title
@@ -102,18 +425,39 @@ func (s *SelectComponent) View() string {
 		b.WriteString("\n\n")
 	}
 
-	// Calculate visible range
-	start := s.scrollOffset
-	end := min(s.scrollOffset+maxVisibleItems, len(s.items))
+	// Filter line with the match count
+	if s.filtering || s.filter != "" {
+		b.WriteString(styles.CursorStyle.Render("/ "))
+		b.WriteString(s.filter)
+		if s.filtering {
+			b.WriteString(styles.CursorStyle.Render("▏"))
+		}
+		total := fmt.Sprintf("%d", len(s.items))
+		if s.load != nil {
+			total += "+" // Not loaded yet
+		}
+		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("  %d/%s", len(s.matches), total)))
+		b.WriteString("\n\n")
+	}
 
 	// Show scroll indicator at top if needed
 	if s.scrollOffset > 0 {
 		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("  ↑ %d more above\n", s.scrollOffset)))
 	}
+	return b.String()
+}
+
+func (s *SelectComponent) View() string {
+	var b strings.Builder
+	b.WriteString(s.header())
+
+	// Calculate visible range
+	start := s.scrollOffset
+	end := min(s.scrollOffset+maxVisibleItems, len(s.matches))
 
 	// Render visible items (no inline details)
 	for i := start; i < end; i++ {
-		item := s.items[i]
+		match := s.matches[i]
 		cursor := "  "
 		style := styles.InactiveStyle
 
@@ -123,23 +467,44 @@ func (s *SelectComponent) View() string {
 		}
 
 		b.WriteString(cursor)
-		b.WriteString(style.Render(item.Label()))
+		if s.multi {
+			if s.checked[match.index] {
+				b.WriteString(styles.SelectedStyle.Render("◉ "))
+			} else {
+				b.WriteString(styles.FaintStyle.Render("○ "))
+			}
+		}
+		b.WriteString(highlight(s.items[match.index].Label(), match.positions, style))
+		if badge := ItemBadge(s.items[match.index]); badge != "" {
+			b.WriteString(" " + styles.FaintStyle.Render(badge))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.matches) == 0 {
+		b.WriteString(styles.FaintStyle.Render("  No matches"))
 		b.WriteString("\n")
 	}
 
 	// Show scroll indicator at bottom if needed
-	remaining := len(s.items) - end
+	remaining := len(s.matches) - end
 	if remaining > 0 {
 		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("  ↓ %d more below\n", remaining)))
 	}
+	switch {
+	case s.loading:
+		b.WriteString(styles.FaintStyle.Render("  Loading more...\n"))
+	case s.loadErr != nil:
+		b.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("  Couldn't load more: %v\n", s.loadErr)))
+	}
 
 	// Fixed details panel below menu
 	b.WriteString("\n")
 	b.WriteString(strings.Repeat("━", 60))
 	b.WriteString("\n")
 
-	if s.cursor >= 0 && s.cursor < len(s.items) {
-		details := s.items[s.cursor].Details()
+	if item := s.current(); item != nil {
+		details := item.Details()
 		if details != "" {
 			// Render details with better visibility (not faint)
 			b.WriteString(details)
@@ -155,11 +520,152 @@ func (s *SelectComponent) View() string {
 
 	// Instructions
 	b.WriteString("\n\n")
-	b.WriteString(styles.FaintStyle.Render("Use ↑↓ or j/k to navigate, Enter to select, Esc to cancel"))
+	b.WriteString(styles.FaintStyle.Render(s.checkedCount()))
+	b.WriteString(s.hints().View())
 
 	return b.String()
 }
 
+// checkedCount is the count of checked items leading the footer of a multi-select
+func (s *SelectComponent) checkedCount() string {
+	if !s.multi {
+		return ""
+	}
+	return fmt.Sprintf("%d selected · ", len(s.CheckedIndexes()))
+}
+
+// hints returns the footer hints for the current mode
+func (s *SelectComponent) hints() Hints {
+	km := keys.Active()
+	hint := func(b key.Binding, action string) Hint {
+		return Hint{Text: keys.Label(b) + " " + action, Binding: b}
+	}
+	var hints []Hint
+	switch {
+	case s.multi && s.filtering:
+		// Space is typed into the filter, only the other toggle keys work
+		hints = []Hint{{Text: "Type to filter"}, hint(keys.Without(km.Toggle, " "), "to toggle"),
+			hint(km.ToggleAll, "for all"), hint(km.Select, "to confirm"), hint(km.Back, "to clear")}
+	case s.multi:
+		hints = []Hint{hint(km.Toggle, "to toggle"), hint(km.ToggleAll, "for all"),
+			{Text: "type or " + keys.Label(km.Filter) + " to filter", Binding: km.Filter},
+			hint(km.Select, "to confirm"), hint(km.Back, "to cancel"), hint(km.Help, "for help")}
+	case s.filtering:
+		hints = []Hint{{Text: "Type to filter"}, {Text: keys.Label(km.Up, km.Down) + " to navigate"},
+			hint(km.Select, "to select"), hint(km.Back, "to clear")}
+	default:
+		hints = []Hint{{Text: "Use " + keys.Label(km.Up, km.Down) + " to navigate"},
+			{Text: "type or " + keys.Label(km.Filter) + " to filter", Binding: km.Filter},
+			hint(km.Select, "to select"), hint(km.Back, "to cancel"), hint(km.Help, "for help")}
+	}
+	return Hints{Hints: hints, Sep: ", "}
+}
+
+// itemsLine returns the line of the view showing the first visible item
+func (s *SelectComponent) itemsLine() int {
+	return tui.LineCount(s.header())
+}
+
+// updateMouse scrolls with the wheel and handles clicks on items and footer hints.
+// Rows are counted from the top of the view.
+func (s *SelectComponent) updateMouse(msg tea.MouseMsg) (*SelectComponent, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return s, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		s.moveCursor(-1)
+
+	case tea.MouseButtonWheelDown:
+		s.moveCursor(1)
+
+	case tea.MouseButtonLeft:
+		if msg.Y == tui.LineCount(s.View()) {
+			return s, s.hints().Click(msg.X - lipgloss.Width(s.checkedCount()))
+		}
+
+		i := s.scrollOffset + msg.Y - s.itemsLine()
+		if i < s.scrollOffset || i >= min(s.scrollOffset+maxVisibleItems, len(s.matches)) {
+			return s, nil
+		}
+		switch {
+		case s.multi:
+			s.cursor = i
+			index := s.matches[i].index
+			s.checked[index] = !s.checked[index]
+		case i == s.cursor:
+			// Clicking the item under the cursor picks it
+			s.selected = true
+			return s, tea.Quit
+		default:
+			// The first click only moves the cursor, showing the item's details
+			s.cursor = i
+		}
+	}
+	return s, nil
+}
+
+// FullHelp lists the key bindings of the select for the help overlay
+func (s *SelectComponent) FullHelp() [][]key.Binding {
+	km := keys.Active()
+	groups := [][]key.Binding{
+		{km.Up, km.Down, km.Top, km.Bottom},
+		{km.Select, km.Back, km.Filter},
+	}
+	if s.multi {
+		groups = append(groups, []key.Binding{km.Toggle, km.ToggleAll})
+	}
+	return groups
+}
+
+// TakingText reports whether the filter is being typed
+func (s *SelectComponent) TakingText() bool {
+	return s.filtering
+}
+
+// LoadMore makes a single select load its items lazily: load is called for more as the
+// cursor nears the last loaded item, or once the items are filtered. Loaded items go
+// before the last fixed items, such as a back entry.
+func (s *SelectComponent) LoadMore(load LoadItems, fixed int) {
+	s.load = load
+	s.fixed = fixed
+}
+
+// LoadAll loads the items left to load at once, e.g. before offering them in a
+// line-based prompt
+func (s *SelectComponent) LoadAll() error {
+	for s.load != nil {
+		items, more, err := s.load()
+		if err != nil {
+			return err
+		}
+		s.addItems(items, more)
+	}
+	return nil
+}
+
+// OnHighlight sets f to be called with the index in Items of the item under the cursor
+// whenever the cursor moves to another item, by key, mouse or filtering
+func (s *SelectComponent) OnHighlight(f func(index int)) {
+	s.onHighlight = f
+}
+
+// Title returns the prompt shown above the items
+func (s *SelectComponent) Title() string {
+	return s.title
+}
+
+// Items returns the selectable items
+func (s *SelectComponent) Items() []SelectItem {
+	return s.items
+}
+
+// Filter returns the text the items are filtered by
+func (s *SelectComponent) Filter() string {
+	return s.filter
+}
+
 func (s *SelectComponent) Selected() bool {
 	return s.selected
 }
@@ -169,15 +675,36 @@ func (s *SelectComponent) Cancelled() bool {
 }
 
 func (s *SelectComponent) SelectedItem() SelectItem {
-	if s.selected && s.cursor >= 0 && s.cursor < len(s.items) {
-		return s.items[s.cursor]
+	if s.selected {
+		return s.current()
 	}
 	return nil
 }
 
+// CheckedIndexes returns the indexes in Items of the checked items of a multi-select, in order
+func (s *SelectComponent) CheckedIndexes() []int {
+	var indexes []int
+	for i := range s.items {
+		if s.checked[i] {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// CheckedItems returns the checked items of a multi-select, in order
+func (s *SelectComponent) CheckedItems() []SelectItem {
+	var items []SelectItem
+	for _, i := range s.CheckedIndexes() {
+		items = append(items, s.items[i])
+	}
+	return items
+}
+
+// SelectedIndex returns the index of the selected item in Items, or -1
 func (s *SelectComponent) SelectedIndex() int {
-	if s.selected {
-		return s.cursor
+	if s.selected && s.current() != nil {
+		return s.matches[s.cursor].index
 	}
 	return -1
 }
@@ -0,0 +1,188 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui/keys"
+)
+
+// numberedItems returns n items labelled "Item 01", "Item 02"...
+func numberedItems(n int) []SelectItem {
+	items := make([]SelectItem, n)
+	for i := range items {
+		items[i] = SimpleItem{LabelText: fmt.Sprintf("Item %02d", i+1), DetailsText: "Details"}
+	}
+	return items
+}
+
+// typeText sends text to the select one key at a time
+func typeText(s *SelectComponent, text string) *SelectComponent {
+	for _, r := range text {
+		s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return s
+}
+
+// clickOn clicks the first cell of text in the view, failing the test if it isn't shown
+func clickOn(t *testing.T, s *SelectComponent, text string) (*SelectComponent, tea.Cmd) {
+	t.Helper()
+	for y, line := range strings.Split(s.View(), "\n") {
+		if i := strings.Index(line, text); i >= 0 {
+			return s.Update(tea.MouseMsg{X: lipgloss.Width(line[:i]), Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+		}
+	}
+	t.Fatalf("%q isn't shown in the view:\n%s", text, s.View())
+	return s, nil
+}
+
+func TestSelectClickOnItems(t *testing.T) {
+	tests := []struct {
+		name   string
+		title  string
+		filter string
+		down   int // cursor moves before clicking, scrolling the list
+		click  string
+	}{
+		{name: "No title", click: "Item 03"},
+		{name: "Title", title: "Pick one", click: "Item 03"},
+		{name: "Multi-line title", title: "Pick one\nof these", click: "Item 03"},
+		{name: "Active filter", title: "Pick one", filter: "item", click: "Item 04"},
+		{name: "Scrolled", title: "Pick one", down: 14, click: "Item 12"},
+		{name: "Active filter and scrolled", title: "Pick one", filter: "item", down: 14, click: "Item 13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSelectComponent(tt.title, numberedItems(20))
+			s = typeText(s, tt.filter)
+			for range tt.down {
+				s, _ = s.Update(tea.KeyMsg{Type: tea.KeyDown})
+			}
+			if tt.down > 0 && s.scrollOffset == 0 {
+				t.Fatal("the list didn't scroll")
+			}
+
+			// The first click moves the cursor, the second picks the item
+			s, _ = clickOn(t, s, tt.click)
+			if s.Selected() {
+				t.Fatal("the first click picked the item")
+			}
+			if got := s.current().Label(); got != tt.click {
+				t.Fatalf("clicking %q moved the cursor to %q", tt.click, got)
+			}
+			s, cmd := clickOn(t, s, tt.click)
+			if !s.Selected() || cmd == nil {
+				t.Fatalf("the second click on %q didn't pick it", tt.click)
+			}
+			if got := s.SelectedItem().Label(); got != tt.click {
+				t.Errorf("picked %q, want %q", got, tt.click)
+			}
+		})
+	}
+}
+
+func TestSelectClickOutsideTheItems(t *testing.T) {
+	s := NewSelectComponent("Pick one", numberedItems(20))
+	for range 14 {
+		s, _ = s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	cursor := s.cursor
+
+	for _, text := range []string{"Pick one", "more above", "more below", "Details"} {
+		s, _ = clickOn(t, s, text)
+		if s.cursor != cursor || s.Selected() {
+			t.Errorf("clicking %q moved the cursor to %d or picked an item", text, s.cursor)
+		}
+	}
+}
+
+func TestMultiSelectClickTogglesItems(t *testing.T) {
+	s := NewMultiSelectComponent("Pick some", numberedItems(20), nil)
+	s = typeText(s, "item")
+	for range 12 {
+		s, _ = s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+
+	s, _ = clickOn(t, s, "Item 10")
+	s, _ = clickOn(t, s, "Item 13")
+	s, _ = clickOn(t, s, "Item 10")
+
+	checked := s.CheckedItems()
+	if len(checked) != 1 || checked[0].Label() != "Item 13" {
+		t.Errorf("checked %v, want only Item 13", checked)
+	}
+	if s.Selected() {
+		t.Error("clicking items of a multi-select submitted it")
+	}
+}
+
+func TestSelectClickOnHints(t *testing.T) {
+	km := keys.Active()
+	tests := []struct {
+		name   string
+		multi  bool
+		filter string
+		click  string
+		want   key.Binding
+	}{
+		{name: "Cancel", click: "to cancel", want: km.Back},
+		{name: "Select", click: "to select", want: km.Select},
+		{name: "Clear the filter", filter: "item", click: "to clear", want: km.Back},
+		{name: "Multi-select confirm", multi: true, click: "to confirm", want: km.Select},
+		{name: "Multi-select toggle all", multi: true, click: "for all", want: km.ToggleAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s *SelectComponent
+			if tt.multi {
+				s = NewMultiSelectComponent("Pick", numberedItems(20), nil)
+			} else {
+				s = NewSelectComponent("Pick", numberedItems(20))
+			}
+			s = typeText(s, tt.filter)
+			for range 12 {
+				s, _ = s.Update(tea.KeyMsg{Type: tea.KeyDown})
+			}
+
+			// Hints are on the last line, below everything else
+			lines := strings.Split(s.View(), "\n")
+			footer := lines[len(lines)-1]
+			i := strings.Index(footer, tt.click)
+			if i < 0 {
+				t.Fatalf("%q isn't in the footer %q", tt.click, footer)
+			}
+			_, cmd := s.Update(tea.MouseMsg{X: lipgloss.Width(footer[:i]), Y: len(lines) - 1, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+			if cmd == nil {
+				t.Fatalf("clicking %q did nothing", tt.click)
+			}
+			msg, ok := firstKey(cmd)
+			if !ok || !key.Matches(msg, tt.want) {
+				t.Errorf("clicking %q sent %v, want %v", tt.click, msg, tt.want.Keys())
+			}
+		})
+	}
+}
+
+// firstKey runs cmd and returns the first key it sends, looking into batches
+func firstKey(cmd tea.Cmd) (tea.KeyMsg, bool) {
+	switch msg := cmd().(type) {
+	case tea.KeyMsg:
+		return msg, true
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if c == nil {
+				continue
+			}
+			if k, ok := firstKey(c); ok {
+				return k, true
+			}
+		}
+	}
+	return tea.KeyMsg{}, false
+}
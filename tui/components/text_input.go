@@ -1,10 +1,13 @@
 package components
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hassek/bc-cli/tui/keys"
 	"github.com/hassek/bc-cli/tui/styles"
 )
 
@@ -45,12 +48,13 @@ func (t *TextInputComponent) Update(msg tea.Msg) (*TextInputComponent, tea.Cmd)
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "esc":
+		km := keys.Active()
+		switch {
+		case key.Matches(msg, km.Quit, km.Back):
 			t.cancelled = true
 			return t, tea.Quit
 
-		case "enter":
+		case key.Matches(msg, km.Select):
 			// Submit the value (can be empty if optional)
 			t.value = strings.TrimSpace(t.textInput.Value())
 			t.submitted = true
@@ -79,10 +83,11 @@ func (t *TextInputComponent) View() string {
 		b.WriteString(styles.FaintStyle.Render(t.helpText))
 		b.WriteString("\n")
 	}
+	km := keys.Active()
 	if t.optional {
-		b.WriteString(styles.FaintStyle.Render("Press Enter to confirm (leave empty to skip), Esc to cancel"))
+		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("Press %s to confirm (leave empty to skip), %s to cancel", keys.Label(km.Select), keys.Label(km.Back))))
 	} else {
-		b.WriteString(styles.FaintStyle.Render("Press Enter to confirm, Esc to cancel"))
+		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("Press %s to confirm, %s to cancel", keys.Label(km.Select), keys.Label(km.Back))))
 	}
 
 	return b.String()
@@ -1,11 +1,15 @@
 package components
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
 )
 
 // TextViewerComponent provides a scrollable text viewer with viewport
@@ -19,6 +23,7 @@ type TextViewerComponent struct {
 func NewTextViewerComponent(title, content string) *TextViewerComponent {
 	// Start with reasonable defaults - will be adjusted on WindowSizeMsg
 	vp := viewport.New(80, 20)
+	vp.KeyMap = keys.Active().Viewport()
 	vp.SetContent(content)
 
 	return &TextViewerComponent{
@@ -47,10 +52,16 @@ func (c *TextViewerComponent) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		c.ready = true
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "enter":
+		km := keys.Active()
+		if key.Matches(msg, km.Close, km.Back, km.Select, km.Quit) {
 			return c, tea.Quit
 		}
+
+	case tea.MouseMsg:
+		// The wheel scrolls the viewport below, clicks only matter on the footer
+		if tui.IsClick(msg) && msg.Y == tui.LineCount(c.View()) {
+			return c, c.hints().Click(msg.X)
+		}
 	}
 
 	c.viewport, cmd = c.viewport.Update(msg)
@@ -66,10 +77,7 @@ func (c *TextViewerComponent) View() string {
 
 	// Title
 	if c.title != "" {
-		titleStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("86")) // Cyan
-		b.WriteString(titleStyle.Render(c.title))
+		b.WriteString(styles.ActiveStyle.Render(c.title))
 		b.WriteString("\n")
 	}
 
@@ -78,13 +86,29 @@ func (c *TextViewerComponent) View() string {
 	b.WriteString("\n")
 
 	// Help text
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")) // Faint gray
-	help := helpStyle.Render("↑/↓/PgUp/PgDn: scroll • q/esc/enter: exit")
-	b.WriteString(help)
+	b.WriteString(c.hints().View())
 
 	return b.String()
 }
 
+func (c *TextViewerComponent) hints() Hints {
+	km := keys.Active()
+	return Hints{Hints: []Hint{
+		{Text: keys.Label(km.Up, km.Down, km.PageUp, km.PageDown) + ": scroll"},
+		{Text: keys.Label(km.Close, km.Back, km.Select) + ": exit", Binding: km.Close},
+		{Text: keys.Label(km.Help) + ": help", Binding: km.Help},
+	}, Sep: " • "}
+}
+
+// FullHelp lists the key bindings of the viewer for the help overlay
+func (c *TextViewerComponent) FullHelp() [][]key.Binding {
+	km := keys.Active()
+	return [][]key.Binding{
+		{km.Up, km.Down, km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown},
+		{km.Close, km.Back, km.Select},
+	}
+}
+
 // SetContent updates the viewport content
 func (c *TextViewerComponent) SetContent(content string) {
 	c.viewport.SetContent(content)
@@ -92,7 +116,11 @@ func (c *TextViewerComponent) SetContent(content string) {
 
 // ShowTextViewer displays a scrollable text viewer and waits for user to exit
 func ShowTextViewer(title, content string) error {
-	p := tea.NewProgram(NewTextViewerComponent(title, content))
-	_, err := p.Run()
-	return err
+	if tui.IsPlain() {
+		// No scrolling without a terminal, print everything
+		fmt.Printf("%s\n\n%s\n", title, content)
+		return nil
+	}
+
+	return tui.Run(NewTextViewerComponent(title, content))
 }
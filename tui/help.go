@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
+)
+
+// KeyHelper is implemented by screens listing their key bindings in the help overlay.
+// The bindings come from keys.Active, so the overlay always shows the keys in use.
+type KeyHelper interface {
+	FullHelp() [][]key.Binding
+}
+
+// TextTaker is implemented by screens that may be taking text input, where ? is typed
+// as text instead of opening the help overlay
+type TextTaker interface {
+	TakingText() bool
+}
+
+// helpOverlay wraps a screen and shows the bindings of its keys on ?
+type helpOverlay struct {
+	model   tea.Model
+	keys    KeyHelper
+	help    help.Model
+	showing bool
+}
+
+// withHelp adds the help overlay to view, listing the bindings of source. Screens
+// without bindings to list are returned unchanged.
+func withHelp(view tea.Model, source tea.Model) tea.Model {
+	var src any = source
+	if c, ok := source.(interface{ inner() any }); ok {
+		src = c.inner()
+	}
+	k, ok := src.(KeyHelper)
+	if !ok {
+		return view
+	}
+	return &helpOverlay{model: view, keys: k, help: help.New()}
+}
+
+func (o *helpOverlay) Init() tea.Cmd {
+	return o.model.Init()
+}
+
+func (o *helpOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		o.help.Width = msg.Width
+
+	case tea.MouseMsg:
+		if o.showing {
+			// Clicks close the overlay, nothing below it is visible to click
+			if IsClick(msg) {
+				o.showing = false
+			}
+			return o, nil
+		}
+
+	case tea.KeyMsg:
+		km := keys.Active()
+		if o.showing && !key.Matches(msg, km.Quit) {
+			// Any key closes the overlay without reaching the screen
+			o.showing = false
+			return o, nil
+		}
+		if key.Matches(msg, km.Help) && !o.takingText() {
+			o.showing = true
+			return o, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	o.model, cmd = o.model.Update(msg)
+	return o, cmd
+}
+
+func (o *helpOverlay) takingText() bool {
+	t, ok := o.keys.(TextTaker)
+	return ok && t.TakingText()
+}
+
+func (o *helpOverlay) View() string {
+	if !o.showing {
+		return o.model.View()
+	}
+
+	groups := append(o.keys.FullHelp(), []key.Binding{keys.Active().Help, keys.Active().Quit})
+	return lipgloss.JoinVertical(lipgloss.Left,
+		styles.ActiveStyle.Render("Keyboard shortcuts"),
+		"",
+		o.help.FullHelpView(groups),
+		"",
+		styles.FaintStyle.Render("Press any key to close"),
+	)
+}
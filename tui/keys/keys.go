@@ -0,0 +1,306 @@
+// Package keys holds the key bindings of the TUI. Every screen reads its bindings from
+// Active, so a preset or the user's overrides from the config apply everywhere at once.
+package keys
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Map is the set of key bindings shared by all screens
+type Map struct {
+	Up           key.Binding
+	Down         key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+
+	Select key.Binding // choose the current item or submit
+	Back   key.Binding // cancel the current screen
+	Close  key.Binding // leave a screen without text input, e.g. a viewer
+	Quit   key.Binding // leave the whole app
+	Help   key.Binding
+
+	Filter    key.Binding
+	Toggle    key.Binding
+	ToggleAll key.Binding
+	Yes       key.Binding
+	No        key.Binding
+
+	Bookmark key.Binding
+	Related  key.Binding
+}
+
+// Preset names accepted by Preset and the keymap config setting
+const (
+	PresetDefault = "default"
+	PresetEmacs   = "emacs"
+	PresetArrows  = "arrows"
+)
+
+// Presets lists the preset names
+var Presets = []string{PresetDefault, PresetEmacs, PresetArrows}
+
+var active = Default()
+
+// Active returns the key bindings in use
+func Active() *Map {
+	return active
+}
+
+// SetActive makes m the key bindings in use
+func SetActive(m *Map) {
+	active = m
+}
+
+// Configure activates the named preset with overrides applied on top. An empty
+// preset means the default one.
+func Configure(preset string, overrides map[string][]string) error {
+	m, err := Preset(preset)
+	if err != nil {
+		return err
+	}
+	if err := m.Override(overrides); err != nil {
+		return err
+	}
+	SetActive(m)
+	return nil
+}
+
+// Default returns the default bindings: arrows plus vim-style letters
+func Default() *Map {
+	return &Map{
+		Up:           bind("up", "up", "k"),
+		Down:         bind("down", "down", "j"),
+		Left:         bind("left", "left", "h"),
+		Right:        bind("right", "right", "l"),
+		Top:          bind("go to top", "home", "g"),
+		Bottom:       bind("go to bottom", "end", "G"),
+		PageUp:       bind("page up", "pgup", "ctrl+b"),
+		PageDown:     bind("page down", "pgdown", "f", "ctrl+f"),
+		HalfPageUp:   bind("half page up", "u", "ctrl+u"),
+		HalfPageDown: bind("half page down", "d", "ctrl+d"),
+
+		Select: bind("select", "enter"),
+		Back:   bind("back", "esc"),
+		Close:  bind("close", "q"),
+		Quit:   bind("quit", "ctrl+c"),
+		Help:   bind("toggle help", "?"),
+
+		Filter:    bind("filter", "/"),
+		Toggle:    bind("toggle", " ", "tab"),
+		ToggleAll: bind("toggle all", "a", "ctrl+a"),
+		Yes:       bind("yes", "y", "Y"),
+		No:        bind("no", "n", "N"),
+
+		Bookmark: bind("bookmark", "b"),
+		Related:  bind("related articles", "r"),
+	}
+}
+
+// Preset returns the bindings of the named preset
+func Preset(name string) (*Map, error) {
+	m := Default()
+	switch name {
+	case "", PresetDefault:
+
+	case PresetEmacs:
+		m.rebind(map[string][]string{
+			"up":             {"up", "ctrl+p"},
+			"down":           {"down", "ctrl+n"},
+			"left":           {"left", "ctrl+b"},
+			"right":          {"right", "ctrl+f"},
+			"top":            {"home", "alt+<"},
+			"bottom":         {"end", "alt+>"},
+			"page_up":        {"pgup", "alt+v"},
+			"page_down":      {"pgdown", "ctrl+v"},
+			"half_page_up":   {},
+			"half_page_down": {},
+			"back":           {"esc", "ctrl+g"},
+			"toggle":         {" ", "tab"},
+			"toggle_all":     {"ctrl+a"},
+		})
+
+	case PresetArrows:
+		// Only arrows and the navigation block, letters stay free for typing
+		m.rebind(map[string][]string{
+			"up":             {"up"},
+			"down":           {"down"},
+			"left":           {"left"},
+			"right":          {"right"},
+			"top":            {"home"},
+			"bottom":         {"end"},
+			"page_up":        {"pgup"},
+			"page_down":      {"pgdown"},
+			"half_page_up":   {},
+			"half_page_down": {},
+			"toggle_all":     {"ctrl+a"},
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown keymap %q, expected one of: %s", name, strings.Join(Presets, ", "))
+	}
+	return m, nil
+}
+
+// Override replaces the keys of the named bindings, e.g. {"up": ["up", "ctrl+p"]}.
+// An empty list disables a binding.
+func (m *Map) Override(overrides map[string][]string) error {
+	bindings := m.named()
+	for name := range overrides {
+		if _, ok := bindings[name]; !ok {
+			return fmt.Errorf("unknown key binding %q, expected one of: %s", name, strings.Join(m.Names(), ", "))
+		}
+	}
+	m.rebind(overrides)
+	return nil
+}
+
+// Names returns the binding names accepted by Override, sorted
+func (m *Map) Names() []string {
+	names := make([]string, 0, len(m.named()))
+	for name := range m.named() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Map) rebind(overrides map[string][]string) {
+	bindings := m.named()
+	for name, keys := range overrides {
+		if b, ok := bindings[name]; ok {
+			*b = bind(b.Help().Desc, keys...)
+		}
+	}
+}
+
+// named maps the config names of the bindings to them
+func (m *Map) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":             &m.Up,
+		"down":           &m.Down,
+		"left":           &m.Left,
+		"right":          &m.Right,
+		"top":            &m.Top,
+		"bottom":         &m.Bottom,
+		"page_up":        &m.PageUp,
+		"page_down":      &m.PageDown,
+		"half_page_up":   &m.HalfPageUp,
+		"half_page_down": &m.HalfPageDown,
+		"select":         &m.Select,
+		"back":           &m.Back,
+		"close":          &m.Close,
+		"quit":           &m.Quit,
+		"help":           &m.Help,
+		"filter":         &m.Filter,
+		"toggle":         &m.Toggle,
+		"toggle_all":     &m.ToggleAll,
+		"yes":            &m.Yes,
+		"no":             &m.No,
+		"bookmark":       &m.Bookmark,
+		"related":        &m.Related,
+	}
+}
+
+// Viewport returns the scrolling bindings for a bubbles viewport
+func (m *Map) Viewport() viewport.KeyMap {
+	return viewport.KeyMap{
+		Up:           m.Up,
+		Down:         m.Down,
+		Left:         m.Left,
+		Right:        m.Right,
+		PageUp:       m.PageUp,
+		PageDown:     m.PageDown,
+		HalfPageUp:   m.HalfPageUp,
+		HalfPageDown: m.HalfPageDown,
+	}
+}
+
+// Label returns how the keys of the bindings are shown in help texts, e.g. "↑/k"
+func Label(bindings ...key.Binding) string {
+	var labels []string
+	for _, b := range bindings {
+		if b.Enabled() {
+			labels = append(labels, b.Help().Key)
+		}
+	}
+	return strings.Join(labels, " ")
+}
+
+// Without returns b without the given keys, for screens where other actions take them
+func Without(b key.Binding, taken ...string) key.Binding {
+	var keys []string
+	for _, k := range b.Keys() {
+		if !slices.Contains(taken, k) {
+			keys = append(keys, k)
+		}
+	}
+	return bind(b.Help().Desc, keys...)
+}
+
+// Press returns the key message of the first key of b, e.g. for a click on a hint standing
+// in for the key. It returns false for disabled bindings and keys it doesn't know.
+func Press(b key.Binding) (tea.KeyMsg, bool) {
+	if !b.Enabled() {
+		return tea.KeyMsg{}, false
+	}
+	k := b.Keys()[0]
+	var msg tea.KeyMsg
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		msg.Alt = true
+		k = rest
+	}
+	// Named keys, such as enter or ctrl+a; special keys have negative types
+	for t := tea.KeyType(-100); t <= 127; t++ {
+		if t != tea.KeyRunes && (tea.Key{Type: t}).String() == k {
+			msg.Type = t
+			return msg, true
+		}
+	}
+	if runes := []rune(k); len(runes) == 1 {
+		msg.Type = tea.KeyRunes
+		msg.Runes = runes
+		return msg, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+// bind creates a binding whose help lists all of its keys
+func bind(desc string, keys ...string) key.Binding {
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled(), key.WithHelp("", desc))
+	}
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyLabel(k)
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(labels, "/"), desc))
+}
+
+func keyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "space"
+	}
+	return k
+}
@@ -0,0 +1,225 @@
+package keys
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPreset(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		wantErr   bool
+		binding   func(m *Map) key.Binding
+		matches   tea.KeyMsg
+		unmatched tea.KeyMsg
+	}{
+		{
+			name:      "Empty preset is the default one",
+			preset:    "",
+			binding:   func(m *Map) key.Binding { return m.Up },
+			matches:   tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")},
+			unmatched: tea.KeyMsg{Type: tea.KeyCtrlP},
+		},
+		{
+			name:      "Emacs moves with ctrl+p",
+			preset:    PresetEmacs,
+			binding:   func(m *Map) key.Binding { return m.Up },
+			matches:   tea.KeyMsg{Type: tea.KeyCtrlP},
+			unmatched: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")},
+		},
+		{
+			name:      "Arrows leaves letters free",
+			preset:    PresetArrows,
+			binding:   func(m *Map) key.Binding { return m.Down },
+			matches:   tea.KeyMsg{Type: tea.KeyDown},
+			unmatched: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")},
+		},
+		{
+			name:    "Unknown preset",
+			preset:  "vscode",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Preset(tt.preset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Preset(%q) error = %v, wantErr %v", tt.preset, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			b := tt.binding(m)
+			if !key.Matches(tt.matches, b) {
+				t.Errorf("Preset(%q) binding %v doesn't match %q", tt.preset, b.Keys(), tt.matches.String())
+			}
+			if key.Matches(tt.unmatched, b) {
+				t.Errorf("Preset(%q) binding %v matches %q", tt.preset, b.Keys(), tt.unmatched.String())
+			}
+		})
+	}
+}
+
+func TestOverride(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   bool
+		wantKeys  []string
+		wantLabel string
+	}{
+		{
+			name:      "Replaces the keys of a binding",
+			overrides: map[string][]string{"bookmark": {"b", "ctrl+s"}},
+			wantKeys:  []string{"b", "ctrl+s"},
+			wantLabel: "b/ctrl+s",
+		},
+		{
+			name:      "Empty list disables the binding",
+			overrides: map[string][]string{"bookmark": {}},
+			wantKeys:  nil,
+			wantLabel: "",
+		},
+		{
+			name:      "Unknown binding",
+			overrides: map[string][]string{"teleport": {"t"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Default()
+			err := m.Override(tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Override() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := m.Bookmark.Keys(); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("Bookmark.Keys() = %v, want %v", got, tt.wantKeys)
+			}
+			if got := Label(m.Bookmark); got != tt.wantLabel {
+				t.Errorf("Label(Bookmark) = %q, want %q", got, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestLabel(t *testing.T) {
+	m := Default()
+	tests := []struct {
+		name     string
+		bindings []key.Binding
+		want     string
+	}{
+		{
+			name:     "Arrows are shown as symbols",
+			bindings: []key.Binding{m.Up, m.Down},
+			want:     "↑/k ↓/j",
+		},
+		{
+			name:     "Space is spelled out",
+			bindings: []key.Binding{m.Toggle},
+			want:     "space/tab",
+		},
+		{
+			name:     "Disabled bindings are skipped",
+			bindings: []key.Binding{bind("nothing"), m.Select},
+			want:     "enter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Label(tt.bindings...); got != tt.want {
+				t.Errorf("Label() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithout(t *testing.T) {
+	m := Default()
+	tests := []struct {
+		name      string
+		binding   key.Binding
+		taken     []string
+		wantLabel string
+		enabled   bool
+	}{
+		{
+			name:      "Drops the taken keys",
+			binding:   m.Right,
+			taken:     []string{"l", "m"},
+			wantLabel: "→",
+			enabled:   true,
+		},
+		{
+			name:      "Keeps the binding when nothing is taken",
+			binding:   m.Right,
+			wantLabel: "→/l",
+			enabled:   true,
+		},
+		{
+			name:      "Disables the binding when all keys are taken",
+			binding:   m.Close,
+			taken:     []string{"q"},
+			wantLabel: "",
+			enabled:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Without(tt.binding, tt.taken...)
+			if got := Label(b); got != tt.wantLabel {
+				t.Errorf("Label(Without()) = %q, want %q", got, tt.wantLabel)
+			}
+			if b.Enabled() != tt.enabled {
+				t.Errorf("Without().Enabled() = %v, want %v", b.Enabled(), tt.enabled)
+			}
+		})
+	}
+}
+
+func TestPress(t *testing.T) {
+	m := Default()
+	tests := []struct {
+		name    string
+		binding key.Binding
+		want    string
+		ok      bool
+	}{
+		{name: "Named key", binding: m.Select, want: "enter", ok: true},
+		{name: "Letter", binding: m.Bookmark, want: "b", ok: true},
+		{name: "Space", binding: m.Toggle, want: " ", ok: true},
+		{name: "Control key", binding: m.Quit, want: "ctrl+c", ok: true},
+		{name: "Alt key", binding: bind("top", "alt+<"), want: "alt+<", ok: true},
+		{name: "Disabled binding", binding: bind("nothing"), ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := Press(tt.binding)
+			if ok != tt.ok {
+				t.Fatalf("Press() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if msg.String() != tt.want {
+				t.Errorf("Press() = %q, want %q", msg.String(), tt.want)
+			}
+			if !key.Matches(msg, tt.binding) {
+				t.Errorf("Press() = %q doesn't match its binding", msg.String())
+			}
+		})
+	}
+}
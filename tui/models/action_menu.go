@@ -75,18 +75,12 @@ func (m ActionMenuModel) View() string {
 
 // SelectAction shows the action menu and returns the selected action
 func SelectAction(actions []ActionItem) (string, error) {
-	p := tea.NewProgram(NewActionMenuModel(actions))
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewActionMenuModel(actions), func(m ActionMenuModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return "", err
 	}
-
-	m := model.(ActionMenuModel)
-	if m.selector.Cancelled() {
-		return "", nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return "", nil
 	}
@@ -6,7 +6,10 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/history"
+	"github.com/hassek/bc-cli/templates"
 	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/hassek/bc-cli/utils"
 )
 
@@ -14,12 +17,16 @@ import (
 type ArticleItem struct {
 	Article api.Article
 	IsBack  bool
+	IsBatch bool
 }
 
 func (a ArticleItem) Label() string {
 	if a.IsBack {
 		return "← Back"
 	}
+	if a.IsBatch {
+		return "☑ Bookmark several articles"
+	}
 	label := a.Article.Title
 	if a.Article.IsBookmarked {
 		label = "★ " + label // Show bookmark indicator
@@ -27,10 +34,37 @@ func (a ArticleItem) Label() string {
 	return label
 }
 
+// readingProgress looks up how far articles were read, see SetReadingProgress
+var readingProgress func(articleID string) (history.Entry, bool)
+
+// SetReadingProgress sets where the article pickers look up how far the articles were
+// read, shown in a badge next to them
+func SetReadingProgress(lookup func(articleID string) (history.Entry, bool)) {
+	readingProgress = lookup
+}
+
+// Badge shows how far the article was read
+func (a ArticleItem) Badge() string {
+	if a.IsBack || a.IsBatch || readingProgress == nil {
+		return ""
+	}
+	entry, ok := readingProgress(a.Article.ID)
+	switch {
+	case !ok || entry.Percent == 0:
+		return ""
+	case entry.IsFinished():
+		return "✓ read"
+	}
+	return fmt.Sprintf("%.0f%% read", entry.Percent*100)
+}
+
 func (a ArticleItem) Details() string {
 	if a.IsBack {
 		return "Return to previous menu"
 	}
+	if a.IsBatch {
+		return "Check the articles to keep bookmarked, bookmarking or removing several at once"
+	}
 
 	// Get terminal width and calculate available space for details
 	termWidth := utils.GetTerminalWidth()
@@ -48,8 +82,10 @@ func (a ArticleItem) Details() string {
 		wrapWidth := max(detailsWidth-indentWidth,
 			// Minimum width
 			20)
-		wrappedSummary := utils.WrapTextWithIndent(a.Article.Summary, wrapWidth, "         ")
-		details.WriteString(fmt.Sprintf("Summary: %s\n", wrappedSummary))
+		// Summaries are Markdown like the articles
+		summary := templates.RenderMarkdown(templates.RenderDescription(a.Article.Summary), wrapWidth)
+		summary = strings.ReplaceAll(summary, "\n", "\n         ")
+		details.WriteString(fmt.Sprintf("Summary: %s\n", summary))
 	}
 
 	// Reading time
@@ -76,14 +112,19 @@ type ArticlePickerModel struct {
 	selector *components.SelectComponent
 }
 
-func NewArticlePickerModel(articles []api.Article) ArticlePickerModel {
+// NewArticlePickerModel lists articles, with an entry for bookmarking several of them
+// at once if batch is set
+func NewArticlePickerModel(articles []api.Article, batch bool) ArticlePickerModel {
 	// Convert articles to SelectItems
-	items := make([]components.SelectItem, len(articles)+1)
-	for i, article := range articles {
-		items[i] = ArticleItem{Article: article}
+	items := make([]components.SelectItem, 0, len(articles)+2)
+	for _, article := range articles {
+		items = append(items, ArticleItem{Article: article})
+	}
+	if batch {
+		items = append(items, ArticleItem{IsBatch: true})
 	}
 	// Add back option
-	items[len(articles)] = ArticleItem{IsBack: true}
+	items = append(items, ArticleItem{IsBack: true})
 
 	return ArticlePickerModel{
 		duck:     components.NewDuckComponent(),
@@ -124,20 +165,52 @@ func (m ArticlePickerModel) View() string {
 	return m.duck.View() + m.selector.View()
 }
 
-// PickArticle returns selected article or nil if back/cancelled
-func PickArticle(articles []api.Article) (*api.Article, error) {
-	p := tea.NewProgram(NewArticlePickerModel(articles))
-	model, err := p.Run()
+// MoreArticles loads the next page of a list of articles, and reports whether there are
+// more pages
+type MoreArticles func() ([]api.Article, bool, error)
+
+// PickArticle returns selected article or nil if back/cancelled. Further pages of the
+// list are loaded with more, if not nil, as the user scrolls. With batch set it offers
+// bookmarking several articles, returning ErrBatchSelected when picked. highlight, if
+// not nil, is called whenever the cursor moves onto another article with that article
+// and the loaded ones after it.
+func PickArticle(articles []api.Article, more MoreArticles, batch bool, highlight func(next []api.Article)) (*api.Article, error) {
+	model := NewArticlePickerModel(articles, batch)
+	if more != nil {
+		// The batch and back entries stay last
+		fixed := 1
+		if batch {
+			fixed = 2
+		}
+		model.selector.LoadMore(func() ([]components.SelectItem, bool, error) {
+			articles, hasMore, err := more()
+			items := make([]components.SelectItem, len(articles))
+			for i, article := range articles {
+				items[i] = ArticleItem{Article: article}
+			}
+			return items, hasMore, err
+		}, fixed)
+	}
+	if highlight != nil {
+		model.selector.OnHighlight(func(index int) {
+			var next []api.Article
+			for _, item := range model.selector.Items()[index:] {
+				if item := item.(ArticleItem); !item.IsBack && !item.IsBatch {
+					next = append(next, item.Article)
+				}
+			}
+			if len(next) > 0 {
+				highlight(next)
+			}
+		})
+	}
+
+	selectedItem, err := runPicker(model, func(m ArticlePickerModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(ArticlePickerModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
@@ -146,6 +219,29 @@ func PickArticle(articles []api.Article) (*api.Article, error) {
 	if articleItem.IsBack {
 		return nil, nil
 	}
+	if articleItem.IsBatch {
+		return nil, ErrBatchSelected
+	}
 
 	return &articleItem.Article, nil
 }
+
+// PickArticles lets the user check any number of articles, starting from those marked
+// in checked. Returns prompts.ErrUserCancelled if the user cancelled.
+func PickArticles(title string, articles []api.Article, checked []bool) ([]api.Article, error) {
+	items := make([]components.SelectItem, len(articles))
+	for i, article := range articles {
+		items[i] = ArticleItem{Article: article}
+	}
+
+	selected, err := prompts.MultiSelect(title, items, checked)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := make([]api.Article, len(selected))
+	for i, item := range selected {
+		picked[i] = item.(ArticleItem).Article
+	}
+	return picked, nil
+}
@@ -0,0 +1,342 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/search"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/prompts"
+	"github.com/hassek/bc-cli/tui/styles"
+	"github.com/hassek/bc-cli/utils"
+)
+
+const (
+	// searchLimit is the number of results a search shows
+	searchLimit = 50
+	// visibleSearchResults is the number of results shown at once
+	visibleSearchResults = 8
+)
+
+// SearchItem wraps a search result for use with the plain line-based prompt
+type SearchItem struct {
+	Result search.Result
+}
+
+func (s SearchItem) Label() string {
+	return s.Result.Document.Title
+}
+
+func (s SearchItem) Details() string {
+	return s.Result.Snippet
+}
+
+// ArticleSearchComponent searches the articles as the query is typed, listing the
+// results with their matching passages. Text goes into the query; arrows move between
+// the results and Enter opens one.
+type ArticleSearchComponent struct {
+	index        *search.Index
+	query        string
+	results      []search.Result
+	cursor       int
+	scrollOffset int
+	selected     bool
+	cancelled    bool
+}
+
+func NewArticleSearchComponent(index *search.Index, query string) *ArticleSearchComponent {
+	s := &ArticleSearchComponent{index: index}
+	s.setQuery(query)
+	return s
+}
+
+func (s *ArticleSearchComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (s *ArticleSearchComponent) Update(msg tea.Msg) (*ArticleSearchComponent, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		km := keys.Active()
+		switch {
+		case (msg.Type == tea.KeyRunes && !msg.Alt) || msg.Type == tea.KeySpace:
+			s.setQuery(s.query + string(msg.Runes))
+
+		case msg.Type == tea.KeyBackspace:
+			if s.query != "" {
+				runes := []rune(s.query)
+				s.setQuery(string(runes[:len(runes)-1]))
+			}
+
+		case msg.Type == tea.KeyCtrlU:
+			s.setQuery("")
+
+		case key.Matches(msg, km.Quit):
+			s.cancelled = true
+			return s, tea.Quit
+
+		case key.Matches(msg, km.Back):
+			// Back clears the query first and cancels only once it is empty
+			if s.query == "" {
+				s.cancelled = true
+				return s, tea.Quit
+			}
+			s.setQuery("")
+
+		case key.Matches(msg, km.Select):
+			if len(s.results) > 0 {
+				s.selected = true
+				return s, tea.Quit
+			}
+
+		case key.Matches(msg, km.Up):
+			s.moveCursor(-1)
+
+		case key.Matches(msg, km.Down):
+			s.moveCursor(1)
+		}
+
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress {
+			switch msg.Button {
+			case tea.MouseButtonWheelUp:
+				s.moveCursor(-1)
+			case tea.MouseButtonWheelDown:
+				s.moveCursor(1)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *ArticleSearchComponent) setQuery(query string) {
+	s.query = query
+	s.results = s.index.Search(query, searchLimit)
+	s.cursor = 0
+	s.scrollOffset = 0
+}
+
+func (s *ArticleSearchComponent) moveCursor(delta int) {
+	cursor := s.cursor + delta
+	if cursor < 0 || cursor >= len(s.results) {
+		return
+	}
+	s.cursor = cursor
+	if s.cursor < s.scrollOffset {
+		s.scrollOffset = s.cursor
+	} else if s.cursor >= s.scrollOffset+visibleSearchResults {
+		s.scrollOffset = s.cursor - visibleSearchResults + 1
+	}
+}
+
+// highlightMatches renders text with the words matching query picked out
+func highlightMatches(text, query string, style lipgloss.Style) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range search.Matches(text, query) {
+		b.WriteString(style.Render(text[last:match[0]]))
+		b.WriteString(styles.MatchStyle.Render(text[match[0]:match[1]]))
+		last = match[1]
+	}
+	b.WriteString(style.Render(text[last:]))
+	return b.String()
+}
+
+func (s *ArticleSearchComponent) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.ActiveStyle.Render("Search Articles"))
+	b.WriteString("\n\n")
+
+	// Query line with the result count
+	b.WriteString(styles.CursorStyle.Render("/ "))
+	b.WriteString(s.query)
+	b.WriteString(styles.CursorStyle.Render("▏"))
+	if s.query != "" {
+		count := fmt.Sprintf("  %d results", len(s.results))
+		if len(s.results) == 1 {
+			count = "  1 result"
+		}
+		b.WriteString(styles.FaintStyle.Render(count))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case s.index.Len() == 0:
+		b.WriteString(styles.FaintStyle.Render("  No articles indexed yet"))
+		b.WriteString("\n")
+	case strings.TrimSpace(s.query) == "":
+		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("  Type to search %d articles", s.index.Len())))
+		b.WriteString("\n")
+	case len(s.results) == 0:
+		b.WriteString(styles.FaintStyle.Render("  No matches"))
+		b.WriteString("\n")
+	}
+
+	if s.scrollOffset > 0 {
+		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("  ↑ %d more above\n", s.scrollOffset)))
+	}
+	end := min(s.scrollOffset+visibleSearchResults, len(s.results))
+	for i := s.scrollOffset; i < end; i++ {
+		cursor := "  "
+		style := styles.InactiveStyle
+		if i == s.cursor {
+			cursor = styles.CursorStyle.Render(styles.Cursor) + " "
+			style = styles.ActiveStyle
+		}
+		b.WriteString(cursor)
+		b.WriteString(highlightMatches(s.results[i].Document.Title, s.query, style))
+		b.WriteString("\n")
+	}
+	if remaining := len(s.results) - end; remaining > 0 {
+		b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("  ↓ %d more below\n", remaining)))
+	}
+
+	// The matching passage of the result under the cursor
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("━", 60))
+	b.WriteString("\n")
+	if result := s.SelectedResult(); result != nil && result.Snippet != "" {
+		width := min(utils.GetTerminalWidth()-10, 100)
+		for _, line := range strings.Split(utils.WrapText(result.Snippet, width), "\n") {
+			b.WriteString(highlightMatches(line, s.query, lipgloss.NewStyle()))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(strings.Repeat("━", 60))
+
+	b.WriteString("\n\n")
+	b.WriteString(s.hints().View())
+
+	return b.String()
+}
+
+func (s *ArticleSearchComponent) hints() components.Hints {
+	km := keys.Active()
+	back := "to go back"
+	if s.query != "" {
+		back = "to clear"
+	}
+	return components.Hints{Hints: []components.Hint{
+		{Text: "Type to search"},
+		{Text: keys.Label(km.Up, km.Down) + " to navigate"},
+		{Text: keys.Label(km.Select) + " to read", Binding: km.Select},
+		{Text: keys.Label(km.Back) + " " + back, Binding: km.Back},
+	}, Sep: ", "}
+}
+
+// FullHelp lists the key bindings of the search for the help overlay
+func (s *ArticleSearchComponent) FullHelp() [][]key.Binding {
+	km := keys.Active()
+	return [][]key.Binding{{km.Up, km.Down}, {km.Select, km.Back}}
+}
+
+// TakingText reports that typed text goes into the query, so help isn't on a key
+func (s *ArticleSearchComponent) TakingText() bool {
+	return true
+}
+
+// Query returns the text searched for
+func (s *ArticleSearchComponent) Query() string {
+	return s.query
+}
+
+// Cancelled reports whether the user left the search without picking a result
+func (s *ArticleSearchComponent) Cancelled() bool {
+	return s.cancelled
+}
+
+// SelectedResult returns the result under the cursor, or nil when there are none
+func (s *ArticleSearchComponent) SelectedResult() *search.Result {
+	if s.cursor >= 0 && s.cursor < len(s.results) {
+		return &s.results[s.cursor]
+	}
+	return nil
+}
+
+// ArticleSearchModel composes duck + search for the search screen
+type ArticleSearchModel struct {
+	duck   *components.DuckComponent
+	search *ArticleSearchComponent
+}
+
+func NewArticleSearchModel(index *search.Index, query string) ArticleSearchModel {
+	return ArticleSearchModel{
+		duck:   components.NewDuckComponent(),
+		search: NewArticleSearchComponent(index, query),
+	}
+}
+
+func (m ArticleSearchModel) Init() tea.Cmd {
+	return tea.Batch(m.duck.Init(), m.search.Init())
+}
+
+func (m ArticleSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var duckCmd, searchCmd tea.Cmd
+	m.duck, duckCmd = m.duck.Update(msg)
+	m.search, searchCmd = m.search.Update(msg)
+	if m.search.selected {
+		m.duck.TriggerAction()
+	}
+	return m, tea.Batch(duckCmd, searchCmd)
+}
+
+func (m ArticleSearchModel) View() string {
+	return m.duck.View() + m.search.View()
+}
+
+// SearchArticles lets the user search the index, starting from query, and returns the
+// picked result, or nil if the user cancelled, with the query last searched for. In
+// plain mode the query is asked for if empty and the results are offered through the
+// line-based prompt.
+func SearchArticles(index *search.Index, query string) (*search.Result, string, error) {
+	if tui.IsPlain() {
+		return searchArticlesPlain(index, query)
+	}
+
+	m := NewArticleSearchModel(index, query)
+	if err := tui.RunComponent(m, tui.Component(m.search, (*ArticleSearchComponent).Update)); err != nil {
+		return nil, query, err
+	}
+	if m.search.Cancelled() {
+		return nil, m.search.Query(), nil
+	}
+	return m.search.SelectedResult(), m.search.Query(), nil
+}
+
+func searchArticlesPlain(index *search.Index, query string) (*search.Result, string, error) {
+	if strings.TrimSpace(query) == "" {
+		var err error
+		query, err = prompts.PromptText("Search articles", "e.g. espresso grind", "", false)
+		if errors.Is(err, prompts.ErrUserCancelled) || err == nil && strings.TrimSpace(query) == "" {
+			return nil, query, nil
+		}
+		if err != nil {
+			return nil, query, err
+		}
+	}
+
+	// A complete query, so its last word isn't taken as a prefix
+	results := index.Search(query+" ", searchLimit)
+	if len(results) == 0 {
+		fmt.Printf("No articles match %q.\n", query)
+		return nil, query, nil
+	}
+
+	items := make([]components.SelectItem, len(results))
+	for i, result := range results {
+		items[i] = SearchItem{Result: result}
+	}
+	picked, err := prompts.Select(fmt.Sprintf("Articles matching %q", query), items)
+	if err != nil || picked == nil {
+		return nil, query, err
+	}
+	result := picked.(SearchItem).Result
+	return &result, query, nil
+}
@@ -4,12 +4,18 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/hassek/bc-cli/api"
+	"github.com/hassek/bc-cli/history"
 	"github.com/hassek/bc-cli/templates"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/hassek/bc-cli/tui/styles"
+	"github.com/hassek/bc-cli/utils"
 )
 
 // ArticleAction represents what action was taken in the article viewer
@@ -17,37 +23,58 @@ type ArticleAction int
 
 const (
 	ArticleActionNone ArticleAction = iota
-	ArticleActionToggleBookmark
 	ArticleActionShowRelated
 	ArticleActionBack
 	ArticleActionQuit // Ctrl+C - quit entire program
 )
 
+// ToggleBookmark bookmarks an article, or removes its bookmark if it's bookmarked, and
+// returns the article with its new bookmark state
+type ToggleBookmark func(article api.Article) (api.Article, error)
+
+// bookmarkToggledMsg carries the result of toggling the bookmark of the viewed article
+type bookmarkToggledMsg struct {
+	article api.Article
+	err     error
+}
+
 // ArticleViewerModel displays an article with scrollable content and actions
 type ArticleViewerModel struct {
-	viewport    viewport.Model
-	article     *api.Article
-	ready       bool
-	canBookmark bool
-	lastAction  ArticleAction
+	viewport   viewport.Model
+	article    *api.Article
+	ready      bool
+	toggle     ToggleBookmark // nil if the article can't be bookmarked
+	toggling   bool
+	status     string         // Result of the last bookmark toggle, shown above the footer
+	progress   *history.Entry // Where the article was left, updated as the user scrolls
+	lastAction ArticleAction
 }
 
-func NewArticleViewerModel(article *api.Article, canBookmark bool) *ArticleViewerModel {
+// NewArticleViewerModel returns a viewer for article, opened where progress was left and
+// updating it. Its bookmark is toggled with toggle, and article updated, while the
+// viewer stays open; a nil toggle hides the bookmark action.
+func NewArticleViewerModel(article *api.Article, toggle ToggleBookmark, progress *history.Entry) *ArticleViewerModel {
 	vp := viewport.New(80, 20)
+	vp.KeyMap = keys.Active().Viewport()
 
-	// Render article content using template
-	content, err := templates.RenderArticleContent(article)
-	if err != nil {
-		content = fmt.Sprintf("Error rendering article: %v", err)
+	m := &ArticleViewerModel{
+		viewport:   vp,
+		article:    article,
+		toggle:     toggle,
+		progress:   progress,
+		lastAction: ArticleActionNone,
 	}
-	vp.SetContent(content)
+	m.renderContent()
+	return m
+}
 
-	return &ArticleViewerModel{
-		viewport:    vp,
-		article:     article,
-		canBookmark: canBookmark,
-		lastAction:  ArticleActionNone,
+// renderContent renders the article into the viewport at its width
+func (m *ArticleViewerModel) renderContent() {
+	content, err := templates.RenderArticleContent(m.article, m.viewport.Width)
+	if err != nil {
+		content = fmt.Sprintf("Error rendering article: %v", err)
 	}
+	m.viewport.SetContent(content)
 }
 
 func (m *ArticleViewerModel) Init() tea.Cmd {
@@ -63,74 +90,139 @@ func (m *ArticleViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if !m.ready {
 			m.viewport = viewport.New(msg.Width, msg.Height-verticalMargins)
+			m.viewport.KeyMap = keys.Active().Viewport()
 			m.viewport.YPosition = headerHeight
-
-			// Re-render content with current article
-			content, err := templates.RenderArticleContent(m.article)
-			if err != nil {
-				content = fmt.Sprintf("Error rendering article: %v", err)
-			}
-			m.viewport.SetContent(content)
 			m.ready = true
+			m.renderContent()
+			m.resume()
 		} else {
 			m.viewport.Width = msg.Width
 			m.viewport.Height = msg.Height - verticalMargins
+			// Re-render so the Markdown wraps to the new width
+			m.renderContent()
 		}
 
+	case bookmarkToggledMsg:
+		m.toggling = false
+		if msg.err != nil {
+			// Undo the optimistic update
+			m.status = styles.ErrorStyle.Render("Couldn't update the bookmark: " + msg.err.Error())
+			m.article.IsBookmarked = !m.article.IsBookmarked
+		} else {
+			*m.article = msg.article
+			if m.article.IsBookmarked {
+				m.status = styles.SelectedStyle.Render("✓ Article bookmarked")
+			} else {
+				m.status = styles.SelectedStyle.Render("✓ Bookmark removed")
+			}
+		}
+		if m.lastAction != ArticleActionNone {
+			return m, tea.Quit // The user left while the bookmark was being updated
+		}
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		km := keys.Active()
+		if !m.toggling {
+			m.status = ""
+		}
+		switch {
+		case key.Matches(msg, km.Quit):
 			// Force quit to terminal
 			m.lastAction = ArticleActionQuit
-			return m, tea.Quit
+			return m, m.quit()
 
-		case "b":
-			// Toggle bookmark
-			if m.canBookmark {
-				m.lastAction = ArticleActionToggleBookmark
-				return m, tea.Quit
+		case key.Matches(msg, km.Bookmark):
+			if m.toggle != nil && !m.toggling {
+				return m, m.toggleBookmark()
 			}
-		case "r":
+			return m, nil
+		case key.Matches(msg, km.Related):
 			// Show related articles
 			m.lastAction = ArticleActionShowRelated
-			return m, tea.Quit
-		case "q", "esc":
+			return m, m.quit()
+		case key.Matches(msg, km.Close, km.Back):
 			// Back
 			m.lastAction = ArticleActionBack
-			return m, tea.Quit
-		// Vim-like navigation
-		case "g":
-			// Go to top (gg in vim, but we use single 'g' for simplicity)
+			return m, m.quit()
+		case key.Matches(msg, km.Top):
 			m.viewport.GotoTop()
 			return m, nil
-		case "G":
-			// Go to bottom (Shift+G)
+		case key.Matches(msg, km.Bottom):
 			m.viewport.GotoBottom()
 			return m, nil
-		case "d", "ctrl+d":
-			// Half page down
-			m.viewport.HalfPageDown()
-			return m, nil
-		case "u", "ctrl+u":
-			// Half page up
-			m.viewport.HalfPageUp()
-			return m, nil
-		case "f", "ctrl+f", "pgdown":
-			// Full page down
-			m.viewport.PageDown()
-			return m, nil
-		case "ctrl+b", "pgup":
-			// Full page up
-			m.viewport.PageUp()
-			return m, nil
+		}
+		// Scrolling keys are handled by the viewport with the same keymap
+
+	case tea.MouseMsg:
+		// The wheel scrolls the viewport below, clicks only matter on the footer
+		if tui.IsClick(msg) && msg.Y == tui.LineCount(m.View()) {
+			return m, m.hints().Click(msg.X)
 		}
 	}
 
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
+	m.recordProgress()
 	return m, cmd
 }
 
+// resume scrolls to where the article was left, unless it was finished there.
+// Articles wrapped to another width since are scrolled to the same share of their
+// length.
+func (m *ArticleViewerModel) resume() {
+	if m.progress == nil || m.progress.Offset == 0 {
+		return
+	}
+	if m.progress.IsFinished() && m.progress.Offset+m.viewport.Height >= m.progress.Lines {
+		return // Read to the end, it starts over
+	}
+	offset := m.progress.Offset
+	if total := m.viewport.TotalLineCount(); m.progress.Lines > 0 && m.progress.Lines != total {
+		offset = offset * total / m.progress.Lines
+	}
+	m.viewport.SetYOffset(offset)
+}
+
+// position returns how much of the article was scrolled through, from 0 to 1
+func (m *ArticleViewerModel) position() float64 {
+	total := m.viewport.TotalLineCount()
+	if total <= m.viewport.Height {
+		return 1
+	}
+	return min(1, float64(m.viewport.YOffset+m.viewport.Height)/float64(total))
+}
+
+// recordProgress updates the reading progress with the current position
+func (m *ArticleViewerModel) recordProgress() {
+	if m.progress == nil || !m.ready {
+		return
+	}
+	m.progress.Offset, m.progress.Lines = m.viewport.YOffset, m.viewport.TotalLineCount()
+	m.progress.Percent = max(m.progress.Percent, m.position())
+}
+
+// toggleBookmark shows the article with its bookmark toggled right away and returns the
+// command making the change, which is undone if it fails
+func (m *ArticleViewerModel) toggleBookmark() tea.Cmd {
+	article, toggle := *m.article, m.toggle
+	m.toggling = true
+	m.article.IsBookmarked = !m.article.IsBookmarked
+	return func() tea.Msg {
+		updated, err := toggle(article)
+		return bookmarkToggledMsg{article: updated, err: err}
+	}
+}
+
+// quit closes the viewer, once the bookmark being updated is, so the article the caller
+// gets back has its actual bookmark state
+func (m *ArticleViewerModel) quit() tea.Cmd {
+	if m.toggling {
+		return nil
+	}
+	return tea.Quit
+}
+
 func (m *ArticleViewerModel) View() string {
 	if !m.ready {
 		return "\n  Initializing..."
@@ -139,41 +231,136 @@ func (m *ArticleViewerModel) View() string {
 	var b strings.Builder
 
 	// Header with title and bookmark status
-	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.Cyan)
 	bookmarkIndicator := ""
 	if m.article.IsBookmarked {
 		bookmarkIndicator = " " + styles.DuckAccentStyle.Render("★")
 	}
-	b.WriteString(titleStyle.Render(m.article.Title) + bookmarkIndicator + "\n\n")
+	b.WriteString(styles.ActiveStyle.Render(m.article.Title) + bookmarkIndicator + "\n\n")
 
 	// Viewport content
 	b.WriteString(m.viewport.View())
-	b.WriteString("\n\n")
+	b.WriteString("\n" + readingBar(m.position()) + "  " + m.status + "\n")
 
 	// Help text footer
-	helpParts := []string{"↑/↓/j/k: scroll", "g/G: top/bottom", "d/u: half page"}
-	if m.canBookmark {
+	b.WriteString(m.hints().View())
+
+	return b.String()
+}
+
+// readingBarWidth is the width of the bar showing how much of the article was read
+const readingBarWidth = 20
+
+// readingBar renders how much of the article was read, e.g. "██████░░░░ 60%"
+func readingBar(read float64) string {
+	filled := int(read * readingBarWidth)
+	bar := styles.ActiveStyle.Render(strings.Repeat("█", filled)) + styles.FaintStyle.Render(strings.Repeat("░", readingBarWidth-filled))
+	return bar + styles.FaintStyle.Render(fmt.Sprintf(" %3.0f%%", read*100))
+}
+
+func (m *ArticleViewerModel) hints() components.Hints {
+	km := keys.Active()
+	hints := []components.Hint{
+		{Text: keys.Label(km.Up, km.Down) + ": scroll"},
+		{Text: keys.Label(km.Top, km.Bottom) + ": top/bottom"},
+		{Text: keys.Label(km.HalfPageDown, km.HalfPageUp) + ": half page"},
+	}
+	if m.toggle != nil {
 		if m.article.IsBookmarked {
-			helpParts = append(helpParts, "b: remove bookmark")
+			hints = append(hints, components.Hint{Text: keys.Label(km.Bookmark) + ": remove bookmark", Binding: km.Bookmark})
 		} else {
-			helpParts = append(helpParts, "b: bookmark")
+			hints = append(hints, components.Hint{Text: keys.Label(km.Bookmark) + ": bookmark", Binding: km.Bookmark})
 		}
 	}
-	helpParts = append(helpParts, "r: related", "q: back")
-	helpText := strings.Join(helpParts, " • ")
-	b.WriteString(styles.FaintStyle.Render(helpText))
+	hints = append(hints,
+		components.Hint{Text: keys.Label(km.Related) + ": related", Binding: km.Related},
+		components.Hint{Text: keys.Label(km.Close) + ": back", Binding: km.Close},
+		components.Hint{Text: keys.Label(km.Help) + ": help", Binding: km.Help},
+	)
+	return components.Hints{Hints: hints, Sep: " • "}
+}
 
-	return b.String()
+// FullHelp lists the key bindings of the viewer for the help overlay
+func (m *ArticleViewerModel) FullHelp() [][]key.Binding {
+	km := keys.Active()
+	actions := []key.Binding{km.Related, km.Close, km.Back}
+	if m.toggle != nil {
+		actions = append([]key.Binding{km.Bookmark}, actions...)
+	}
+	return [][]key.Binding{
+		{km.Up, km.Down, km.Top, km.Bottom},
+		{km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown},
+		actions,
+	}
 }
 
-// ViewArticle displays an article and returns the action taken
-func ViewArticle(article *api.Article, canBookmark bool) (ArticleAction, error) {
-	p := tea.NewProgram(NewArticleViewerModel(article, canBookmark))
-	model, err := p.Run()
-	if err != nil {
-		return ArticleActionNone, err
+// ViewArticle displays an article and returns the action taken. A non-nil toggle lets
+// the user bookmark it, updating article. The article opens where progress, its reading
+// progress, was left and progress is updated as it is read.
+func ViewArticle(article *api.Article, toggle ToggleBookmark, progress *history.Entry) (ArticleAction, error) {
+	if tui.IsPlain() {
+		if progress != nil {
+			// The whole article is printed
+			progress.Offset, progress.Percent = 0, 1
+		}
+		return viewArticlePlain(article, toggle)
 	}
 
-	m := model.(*ArticleViewerModel)
+	m := NewArticleViewerModel(article, toggle, progress)
+	if err := tui.Run(m); err != nil {
+		return ArticleActionNone, err
+	}
 	return m.lastAction, nil
 }
+
+// viewArticlePlain prints the whole article and asks for the next action with a line prompt
+func viewArticlePlain(article *api.Article, toggle ToggleBookmark) (ArticleAction, error) {
+	content, err := templates.RenderArticleContent(article, utils.GetTerminalWidth())
+	if err != nil {
+		content = fmt.Sprintf("Error rendering article: %v", err)
+	}
+
+	title := article.Title
+	if article.IsBookmarked {
+		title += " ★"
+	}
+	fmt.Printf("%s\n\n%s\n\n", title, content)
+
+	for {
+		var items []components.SelectItem
+		if toggle != nil {
+			if article.IsBookmarked {
+				items = append(items, ActionItem{Action: "bookmark", Display: "Remove bookmark"})
+			} else {
+				items = append(items, ActionItem{Action: "bookmark", Display: "Bookmark"})
+			}
+		}
+		items = append(items,
+			ActionItem{Action: "related", Display: "Related articles"},
+			ActionItem{Action: "back", Display: "Back"},
+		)
+
+		selected, err := prompts.Select("What would you like to do?", items)
+		if err != nil || selected == nil {
+			return ArticleActionBack, err
+		}
+
+		switch selected.(ActionItem).Action {
+		case "bookmark":
+			updated, err := toggle(*article)
+			switch {
+			case err != nil:
+				fmt.Printf("Couldn't update the bookmark: %v\n\n", err)
+			case updated.IsBookmarked:
+				*article = updated
+				fmt.Print("✓ Article bookmarked\n\n")
+			default:
+				*article = updated
+				fmt.Print("✓ Bookmark removed\n\n")
+			}
+		case "related":
+			return ArticleActionShowRelated, nil
+		default:
+			return ArticleActionBack, nil
+		}
+	}
+}
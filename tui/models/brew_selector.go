@@ -106,18 +106,12 @@ type BrewingMethodResult struct {
 
 // SelectBrewingMethod shows the brew selector and returns the selected brewing method and notes
 func SelectBrewingMethod(grindType string) (*BrewingMethodResult, error) {
-	p := tea.NewProgram(NewBrewSelectorModel(grindType))
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewBrewSelectorModel(grindType), func(m BrewSelectorModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(BrewSelectorModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
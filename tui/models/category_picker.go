@@ -9,10 +9,14 @@ import (
 // CategoryItem wraps an api.Category for use with SelectComponent
 type CategoryItem struct {
 	Category api.Category
+	IsSearch bool
 	IsExit   bool
 }
 
 func (c CategoryItem) Label() string {
+	if c.IsSearch {
+		return "⌕ Search articles"
+	}
 	if c.IsExit {
 		return "← Exit"
 	}
@@ -20,6 +24,9 @@ func (c CategoryItem) Label() string {
 }
 
 func (c CategoryItem) Details() string {
+	if c.IsSearch {
+		return "Search the titles, summaries, tags and content of all articles"
+	}
 	if c.IsExit {
 		return "Return to main menu"
 	}
@@ -33,15 +40,15 @@ type CategoryPickerModel struct {
 }
 
 func NewCategoryPickerModel(categories []api.Category) CategoryPickerModel {
-	items := make([]components.SelectItem, 0, len(categories)+1)
+	items := make([]components.SelectItem, 0, len(categories)+2)
 
 	// Add categories
 	for _, cat := range categories {
 		items = append(items, CategoryItem{Category: cat})
 	}
 
-	// Add exit
-	items = append(items, CategoryItem{IsExit: true})
+	// Add search and exit
+	items = append(items, CategoryItem{IsSearch: true}, CategoryItem{IsExit: true})
 
 	return CategoryPickerModel{
 		duck:     components.NewDuckComponent(),
@@ -82,25 +89,23 @@ func (m CategoryPickerModel) View() string {
 	return m.duck.View() + m.selector.View()
 }
 
-// PickCategory returns selected category or error
+// PickCategory returns selected category or error. It returns ErrSearchSelected if the
+// user picked searching the articles.
 func PickCategory(categories []api.Category) (*api.Category, error) {
-	p := tea.NewProgram(NewCategoryPickerModel(categories))
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewCategoryPickerModel(categories), func(m CategoryPickerModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(CategoryPickerModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
 
 	catItem := selectedItem.(CategoryItem)
+	if catItem.IsSearch {
+		return nil, ErrSearchSelected
+	}
 	if catItem.IsExit {
 		return nil, nil
 	}
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
+)
+
+// DashboardPane is a titled box of lines on the dashboard
+type DashboardPane struct {
+	Title string
+	Lines []string
+}
+
+// DashboardShortcut opens a flow from the dashboard with a single key
+type DashboardShortcut struct {
+	ActionItem
+	Key string
+}
+
+// DashboardModel shows the home screen panes and a bar of shortcuts into the flows
+type DashboardModel struct {
+	panes     []DashboardPane
+	shortcuts []DashboardShortcut
+	cursor    int
+	width     int
+	chosen    string
+}
+
+func NewDashboardModel(panes []DashboardPane, shortcuts []DashboardShortcut) *DashboardModel {
+	return &DashboardModel{
+		panes:     panes,
+		shortcuts: shortcuts,
+		width:     80,
+	}
+}
+
+func (m *DashboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		// Shortcut letters come first, they may shadow letter bindings
+		for i, shortcut := range m.shortcuts {
+			if msg.String() == shortcut.Key {
+				m.cursor = i
+				m.chosen = shortcut.Action
+				return m, tea.Quit
+			}
+		}
+
+		km := keys.Active()
+		switch {
+		case key.Matches(msg, km.Quit, km.Back, km.Close):
+			return m, tea.Quit
+
+		case key.Matches(msg, km.Select):
+			m.chosen = m.shortcuts[m.cursor].Action
+			return m, tea.Quit
+
+		case key.Matches(msg, km.Left) || msg.String() == "shift+tab":
+			m.cursor = (m.cursor + len(m.shortcuts) - 1) % len(m.shortcuts)
+
+		case key.Matches(msg, km.Right) || msg.String() == "tab":
+			m.cursor = (m.cursor + 1) % len(m.shortcuts)
+		}
+	}
+	return m, nil
+}
+
+// FullHelp lists the shortcuts and key bindings of the dashboard for the help overlay
+func (m *DashboardModel) FullHelp() [][]key.Binding {
+	shortcuts := make([]key.Binding, len(m.shortcuts))
+	for i, shortcut := range m.shortcuts {
+		shortcuts[i] = key.NewBinding(key.WithKeys(shortcut.Key), key.WithHelp(shortcut.Key, shortcut.Display))
+	}
+	left, right, sel, closeKey := m.navigation()
+	return [][]key.Binding{shortcuts, {left, right, sel, closeKey}}
+}
+
+// navigation returns the bindings moving between and opening the shortcuts, without the
+// keys the shortcuts themselves take
+func (m *DashboardModel) navigation() (left, right, sel, closeKey key.Binding) {
+	km := keys.Active()
+	taken := make([]string, len(m.shortcuts))
+	for i, shortcut := range m.shortcuts {
+		taken[i] = shortcut.Key
+	}
+	return keys.Without(km.Left, taken...), keys.Without(km.Right, taken...),
+		keys.Without(km.Select, taken...), keys.Without(km.Close, taken...)
+}
+
+func (m *DashboardModel) View() string {
+	var b strings.Builder
+
+	// Panes two per row; an odd one out takes the whole row
+	for i := 0; i < len(m.panes); i += 2 {
+		if i+1 < len(m.panes) {
+			half := m.width / 2
+			b.WriteString(renderPaneRow(
+				[]DashboardPane{m.panes[i], m.panes[i+1]},
+				[]int{half, m.width - half},
+			))
+		} else {
+			b.WriteString(renderPaneRow([]DashboardPane{m.panes[i]}, []int{m.width}))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	parts := make([]string, len(m.shortcuts))
+	for i, shortcut := range m.shortcuts {
+		style := styles.InactiveStyle
+		if i == m.cursor {
+			style = styles.ActiveStyle
+		}
+		parts[i] = styles.DuckAccentStyle.Render("["+shortcut.Key+"]") + " " + style.Render(shortcut.Display)
+	}
+	b.WriteString(strings.Join(parts, "   "))
+	b.WriteString("\n\n")
+	left, right, sel, closeKey := m.navigation()
+	b.WriteString(styles.FaintStyle.Render(fmt.Sprintf("Press a shortcut key, or %s and %s to open it, %s to quit, %s for help",
+		keys.Label(left, right), keys.Label(sel), keys.Label(closeKey), keys.Label(keys.Active().Help))))
+
+	return b.String()
+}
+
+// renderPaneRow draws panes side by side with the given outer widths and equal heights
+func renderPaneRow(panes []DashboardPane, widths []int) string {
+	contents := make([]string, len(panes))
+	height := 0
+	for i, pane := range panes {
+		contents[i] = styles.ActiveStyle.Render(pane.Title) + "\n" + strings.Join(pane.Lines, "\n")
+		height = max(height, lipgloss.Height(paneStyle(widths[i]).Render(contents[i])))
+	}
+
+	boxes := make([]string, len(panes))
+	for i := range panes {
+		// The border adds two lines that Height doesn't count
+		boxes[i] = paneStyle(widths[i]).Height(height - 2).Render(contents[i])
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, boxes...)
+}
+
+func paneStyle(outerWidth int) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Border).
+		Padding(0, 1).
+		Width(max(outerWidth-2, 10))
+}
+
+// ShowDashboard shows the home screen and returns the action of the chosen shortcut,
+// or "" if the user quit. It has no duck of its own and is meant for the app shell.
+func ShowDashboard(panes []DashboardPane, shortcuts []DashboardShortcut) (string, error) {
+	m := NewDashboardModel(panes, shortcuts)
+	if err := tui.RunComponent(m, m); err != nil {
+		return "", err
+	}
+	return m.chosen, nil
+}
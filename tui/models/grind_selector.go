@@ -80,18 +80,12 @@ func (m GrindSelectorModel) View() string {
 
 // SelectGrindType shows the grind selector and returns the selected grind type
 func SelectGrindType() (string, error) {
-	p := tea.NewProgram(NewGrindSelectorModel())
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewGrindSelectorModel(), func(m GrindSelectorModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return "", err
 	}
-
-	m := model.(GrindSelectorModel)
-	if m.selector.Cancelled() {
-		return "", nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return "", nil
 	}
@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/prompts"
 )
 
 // ManageSubscriptionItem wraps a subscription for management
@@ -18,6 +19,7 @@ type ManageSubscriptionItem struct {
 	TotalQuantity   int
 	HasOrderDetails bool
 	IsExit          bool
+	IsBatch         bool
 }
 
 func (m ManageSubscriptionItem) Label() string {
@@ -28,6 +30,9 @@ func (m ManageSubscriptionItem) Details() string {
 	if m.IsExit {
 		return "Return to main menu"
 	}
+	if m.IsBatch {
+		return "Check several subscriptions to pause or resume them at once"
+	}
 
 	details := ""
 	if m.Subscription.ID != "" {
@@ -97,20 +102,15 @@ func (m ManageSubscriptionPickerModel) View() string {
 	return m.duck.View() + m.selector.View()
 }
 
-// PickManageSubscription shows the subscription picker for management
+// PickManageSubscription shows the subscription picker for management. Returns
+// ErrBatchSelected when the batch entry is picked.
 func PickManageSubscription(subscriptions []ManageSubscriptionItem) (*api.Subscription, error) {
-	p := tea.NewProgram(NewManageSubscriptionPickerModel(subscriptions))
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewManageSubscriptionPickerModel(subscriptions), func(m ManageSubscriptionPickerModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(ManageSubscriptionPickerModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
@@ -119,6 +119,29 @@ func PickManageSubscription(subscriptions []ManageSubscriptionItem) (*api.Subscr
 	if subItem.IsExit {
 		return nil, nil
 	}
+	if subItem.IsBatch {
+		return nil, ErrBatchSelected
+	}
 
 	return &subItem.Subscription, nil
 }
+
+// PickSubscriptions lets the user check any number of subscriptions.
+// Returns prompts.ErrUserCancelled if the user cancelled.
+func PickSubscriptions(title string, subscriptions []ManageSubscriptionItem) ([]api.Subscription, error) {
+	items := make([]components.SelectItem, len(subscriptions))
+	for i, sub := range subscriptions {
+		items[i] = sub
+	}
+
+	selected, err := prompts.MultiSelect(title, items, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := make([]api.Subscription, len(selected))
+	for i, item := range selected {
+		picked[i] = item.(ManageSubscriptionItem).Subscription
+	}
+	return picked, nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/prompts"
+)
+
+// ErrBatchSelected is returned by pickers offering a batch entry when the user picks it
+// to act on several items at once
+var ErrBatchSelected = errors.New("batch action selected")
+
+// ErrSearchSelected is returned by the category picker when the user picks searching
+// the articles instead of a category
+var ErrSearchSelected = errors.New("search selected")
+
+// runPicker runs a duck + select picker model and returns the selected item, or nil
+// if the user cancelled. In plain mode the picker's items, all of them if they load
+// lazily, are offered through the line-based prompt instead of a full-screen program,
+// and inside the app shell only the select is shown, below the shell's duck.
+func runPicker[M tea.Model](model M, selector func(M) *components.SelectComponent) (components.SelectItem, error) {
+	s := selector(model)
+	if tui.IsPlain() {
+		if err := s.LoadAll(); err != nil {
+			return nil, err
+		}
+		return prompts.Select(s.Title(), s.Items())
+	}
+
+	if err := tui.RunComponent(model, tui.Component(s, (*components.SelectComponent).Update)); err != nil {
+		return nil, err
+	}
+	if s.Cancelled() {
+		return nil, nil
+	}
+	return s.SelectedItem(), nil
+}
@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hassek/bc-cli/api"
 	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/prompts"
 	"github.com/hassek/bc-cli/utils"
 )
 
@@ -13,12 +14,16 @@ import (
 type ProductItem struct {
 	Product api.AvailableSubscription
 	IsExit  bool
+	IsBatch bool
 }
 
 func (p ProductItem) Label() string {
 	if p.IsExit {
 		return "← Exit"
 	}
+	if p.IsBatch {
+		return "☑ Buy several products"
+	}
 	return p.Product.Name
 }
 
@@ -26,6 +31,9 @@ func (p ProductItem) Details() string {
 	if p.IsExit {
 		return "Return to main menu"
 	}
+	if p.IsBatch {
+		return "Check several products and configure them one after another"
+	}
 
 	// Get terminal width and calculate available space for details
 	termWidth := utils.GetTerminalWidth()
@@ -54,14 +62,19 @@ type ProductPickerModel struct {
 	selector *components.SelectComponent
 }
 
-func NewProductPickerModel(products []api.AvailableSubscription) ProductPickerModel {
+// NewProductPickerModel lists products, with an entry for buying several of them at
+// once if batch is set
+func NewProductPickerModel(products []api.AvailableSubscription, batch bool) ProductPickerModel {
 	// Convert products to SelectItems
-	items := make([]components.SelectItem, len(products)+1)
-	for i, product := range products {
-		items[i] = ProductItem{Product: product}
+	items := make([]components.SelectItem, 0, len(products)+2)
+	for _, product := range products {
+		items = append(items, ProductItem{Product: product})
+	}
+	if batch {
+		items = append(items, ProductItem{IsBatch: true})
 	}
 	// Add exit option
-	items[len(products)] = ProductItem{IsExit: true}
+	items = append(items, ProductItem{IsExit: true})
 
 	return ProductPickerModel{
 		duck:     components.NewDuckComponent(),
@@ -106,20 +119,15 @@ func (m ProductPickerModel) View() string {
 }
 
 // PickProduct shows the product picker and returns the selected product
-// Returns nil if user cancelled or selected exit
-func PickProduct(products []api.AvailableSubscription) (*api.AvailableSubscription, error) {
-	p := tea.NewProgram(NewProductPickerModel(products))
-	model, err := p.Run()
+// Returns nil if user cancelled or selected exit. With batch set it offers buying
+// several products, returning ErrBatchSelected when picked.
+func PickProduct(products []api.AvailableSubscription, batch bool) (*api.AvailableSubscription, error) {
+	selectedItem, err := runPicker(NewProductPickerModel(products, batch), func(m ProductPickerModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(ProductPickerModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
@@ -128,6 +136,29 @@ func PickProduct(products []api.AvailableSubscription) (*api.AvailableSubscripti
 	if productItem.IsExit {
 		return nil, nil
 	}
+	if productItem.IsBatch {
+		return nil, ErrBatchSelected
+	}
 
 	return &productItem.Product, nil
 }
+
+// PickProducts lets the user check any number of products.
+// Returns prompts.ErrUserCancelled if the user cancelled.
+func PickProducts(title string, products []api.AvailableSubscription) ([]api.AvailableSubscription, error) {
+	items := make([]components.SelectItem, len(products))
+	for i, product := range products {
+		items[i] = ProductItem{Product: product}
+	}
+
+	selected, err := prompts.MultiSelect(title, items, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	picked := make([]api.AvailableSubscription, len(selected))
+	for i, item := range selected {
+		picked[i] = item.(ProductItem).Product
+	}
+	return picked, nil
+}
@@ -82,18 +82,12 @@ func (m SectionPickerModel) View() string {
 
 // PickSection returns selected section or nil if back/cancelled
 func PickSection(sections []api.Section) (*api.Section, error) {
-	p := tea.NewProgram(NewSectionPickerModel(sections))
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewSectionPickerModel(sections), func(m SectionPickerModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(SectionPickerModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
@@ -107,18 +107,12 @@ func (m SubscriptionPickerModel) View() string {
 // PickSubscription shows the subscription picker and returns the selected subscription
 // Returns nil if user cancelled or selected exit
 func PickSubscription(subscriptions []api.AvailableSubscription) (*api.AvailableSubscription, error) {
-	p := tea.NewProgram(NewSubscriptionPickerModel(subscriptions))
-	model, err := p.Run()
+	selectedItem, err := runPicker(NewSubscriptionPickerModel(subscriptions), func(m SubscriptionPickerModel) *components.SelectComponent {
+		return m.selector
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	m := model.(SubscriptionPickerModel)
-	if m.selector.Cancelled() {
-		return nil, nil
-	}
-
-	selectedItem := m.selector.SelectedItem()
 	if selectedItem == nil {
 		return nil, nil
 	}
@@ -0,0 +1,18 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// LineCount returns the index of the last line of view, which is where its footer goes.
+// Screens receive mouse events with rows counted from their own top line.
+func LineCount(view string) int {
+	return strings.Count(view, "\n")
+}
+
+// IsClick reports whether msg is a press of the left mouse button
+func IsClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
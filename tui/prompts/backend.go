@@ -0,0 +1,65 @@
+package prompts
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/components"
+	"golang.org/x/term"
+)
+
+// Backend asks the user for input. The Bubble Tea backend draws full-screen prompts;
+// the plain backend reads lines from stdin so prompts work in pipes, CI and screen readers.
+type Backend interface {
+	// Select returns the chosen item, or nil if the user cancelled
+	Select(title string, items []components.SelectItem) (components.SelectItem, error)
+	// MultiSelect returns the checked items, starting from those marked in checked.
+	// It returns ErrUserCancelled if the user cancelled.
+	MultiSelect(title string, items []components.SelectItem, checked []bool) ([]components.SelectItem, error)
+	// Confirm returns false if the user declined or cancelled
+	Confirm(label string) (bool, error)
+	// Quantity returns ErrUserCancelled if the user cancelled
+	Quantity(label string, min, max, defaultVal int) (int, error)
+	// Text returns ErrUserCancelled if the user cancelled
+	Text(label, placeholder, helpText string, optional bool) (string, error)
+}
+
+// stdin is shared by every plain prompt so buffered input isn't lost between prompts
+var stdin = bufio.NewReader(os.Stdin)
+
+// CurrentBackend returns the plain backend when tui.IsPlain, the Bubble Tea one otherwise
+func CurrentBackend() Backend {
+	if tui.IsPlain() {
+		return plainBackend{in: stdin, out: os.Stdout, scripted: !term.IsTerminal(int(os.Stdin.Fd()))}
+	}
+	return teaBackend{}
+}
+
+// PromptQuantityInt prompts the user to enter a quantity as an integer
+// Matches the old API: func PromptQuantityInt(label string, min, max, defaultVal int) (int, error)
+func PromptQuantityInt(label string, min, max, defaultVal int) (int, error) {
+	return CurrentBackend().Quantity(label, min, max, defaultVal)
+}
+
+// PromptConfirm prompts the user for a yes/no confirmation
+// Matches the old API: func PromptConfirm(label string) (bool, error)
+func PromptConfirm(label string) (bool, error) {
+	return CurrentBackend().Confirm(label)
+}
+
+// PromptText prompts the user to enter text with an optional placeholder
+func PromptText(label, placeholder, helpText string, optional bool) (string, error) {
+	return CurrentBackend().Text(label, placeholder, helpText, optional)
+}
+
+// Select prompts the user to pick one of items. Returns nil if the user cancelled.
+func Select(title string, items []components.SelectItem) (components.SelectItem, error) {
+	return CurrentBackend().Select(title, items)
+}
+
+// MultiSelect prompts the user to check any number of items, starting from those
+// marked in checked. Returns ErrUserCancelled if the user cancelled.
+func MultiSelect(title string, items []components.SelectItem, checked []bool) ([]components.SelectItem, error) {
+	return CurrentBackend().MultiSelect(title, items, checked)
+}
@@ -0,0 +1,291 @@
+package prompts
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hassek/bc-cli/tui"
+	"github.com/hassek/bc-cli/tui/components"
+	"golang.org/x/term"
+)
+
+// plainBackend implements Backend with numbered lists and line input. End of input
+// cancels the prompt, so scripts can't hang waiting for an answer.
+type plainBackend struct {
+	in  *bufio.Reader
+	out io.Writer
+	// scripted is set when the answers are piped in rather than typed, so an empty
+	// line doesn't confirm anything
+	scripted bool
+}
+
+// NewPlainBackend returns a line-based Backend reading answers typed in in
+func NewPlainBackend(in *bufio.Reader, out io.Writer) Backend {
+	return plainBackend{in: in, out: out}
+}
+
+// readLine reads one line of input without the trailing newline.
+// A final line without a newline is still returned; io.EOF means no input was left.
+func (p plainBackend) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (p plainBackend) Select(title string, items []components.SelectItem) (components.SelectItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	if title != "" {
+		fmt.Fprintln(p.out, title)
+	}
+	for i, item := range items {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, plainLabel(item))
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Enter a number (1-%d), ? followed by a number for details, or q to cancel: ", len(items))
+		line, err := p.readLine()
+		if err != nil {
+			fmt.Fprintln(p.out)
+			return nil, nil
+		}
+
+		answer := strings.TrimSpace(line)
+		switch {
+		case answer == "q" || answer == "quit":
+			return nil, nil
+		case strings.HasPrefix(answer, "?"):
+			if n, err := strconv.Atoi(strings.TrimSpace(answer[1:])); err == nil && n >= 1 && n <= len(items) {
+				details := items[n-1].Details()
+				if details == "" {
+					details = "No details available"
+				}
+				fmt.Fprintln(p.out, details)
+				continue
+			}
+		default:
+			if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(items) {
+				return items[n-1], nil
+			}
+			// Also accept the item's label so scripts don't depend on ordering
+			for _, item := range items {
+				if strings.EqualFold(item.Label(), answer) {
+					return item, nil
+				}
+			}
+		}
+		fmt.Fprintf(p.out, "Invalid choice %q\n", answer)
+	}
+}
+
+func (p plainBackend) MultiSelect(title string, items []components.SelectItem, checked []bool) ([]components.SelectItem, error) {
+	marked := make([]bool, len(items))
+	copy(marked, checked)
+
+	if title != "" {
+		fmt.Fprintln(p.out, title)
+	}
+	for i, item := range items {
+		mark := " "
+		if marked[i] {
+			mark = "x"
+		}
+		fmt.Fprintf(p.out, "  [%s] %d) %s\n", mark, i+1, plainLabel(item))
+	}
+
+	for {
+		fmt.Fprint(p.out, "Enter numbers or ranges (e.g. 1,3-4), a for all, none, Enter to keep the marked ones, or q to cancel: ")
+		line, err := p.readLine()
+		if err != nil {
+			fmt.Fprintln(p.out)
+			return nil, ErrUserCancelled
+		}
+
+		answer := strings.ToLower(strings.TrimSpace(line))
+		switch answer {
+		case "q", "quit":
+			return nil, ErrUserCancelled
+		case "":
+		case "a", "all":
+			for i := range marked {
+				marked[i] = true
+			}
+		case "none":
+			marked = make([]bool, len(items))
+		default:
+			picked, ok := parseNumberList(answer, len(items))
+			if !ok {
+				fmt.Fprintf(p.out, "Invalid choice %q\n", answer)
+				continue
+			}
+			marked = picked
+		}
+
+		var selected []components.SelectItem
+		for i, item := range items {
+			if marked[i] {
+				selected = append(selected, item)
+			}
+		}
+		return selected, nil
+	}
+}
+
+// parseNumberList parses numbers and ranges like "1, 3-4" between 1 and n into marks
+// for the items they refer to
+func parseNumberList(list string, n int) ([]bool, bool) {
+	marked := make([]bool, n)
+	fields := strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+	for _, field := range fields {
+		from, to, isRange := strings.Cut(field, "-")
+		if !isRange {
+			to = from
+		}
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, false
+		}
+		last, err := strconv.Atoi(to)
+		if err != nil || first < 1 || last > n || first > last {
+			return nil, false
+		}
+		for i := first; i <= last; i++ {
+			marked[i-1] = true
+		}
+	}
+	return marked, len(fields) > 0
+}
+
+// Confirm defaults to yes when the answers are typed, and to no when they're piped in:
+// a stray empty line must not confirm something like a cancellation
+func (p plainBackend) Confirm(label string) (bool, error) {
+	choices := "[Y/n]"
+	if p.scripted {
+		choices = "[y/N]"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s %s: ", label, choices)
+		line, err := p.readLine()
+		if err != nil {
+			fmt.Fprintln(p.out)
+			return false, nil
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "":
+			return !p.scripted, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer y or n")
+	}
+}
+
+func (p plainBackend) Quantity(label string, min, max, defaultVal int) (int, error) {
+	for {
+		fmt.Fprintf(p.out, "%s (%d-%d) [%d]: ", label, min, max, defaultVal)
+		line, err := p.readLine()
+		if err != nil {
+			fmt.Fprintln(p.out)
+			return 0, ErrUserCancelled
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			return defaultVal, nil
+		}
+		if value, err := strconv.Atoi(answer); err == nil && value >= min && value <= max {
+			return value, nil
+		}
+		fmt.Fprintf(p.out, "Please enter a number between %d and %d\n", min, max)
+	}
+}
+
+func (p plainBackend) Text(label, placeholder, helpText string, optional bool) (string, error) {
+	if helpText != "" {
+		fmt.Fprintln(p.out, helpText)
+	}
+
+	suffix := ""
+	if optional {
+		suffix = " (optional, press Enter to skip)"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s%s: ", label, suffix)
+		line, err := p.readLine()
+		if err != nil {
+			fmt.Fprintln(p.out)
+			return "", ErrUserCancelled
+		}
+
+		value := strings.TrimSpace(line)
+		if value != "" || optional {
+			return value, nil
+		}
+		fmt.Fprintln(p.out, "A value is required")
+	}
+}
+
+// ReadLine prints label and reads a line from stdin
+func ReadLine(label string) (string, error) {
+	fmt.Print(label)
+	line, err := plainBackend{in: stdin, out: os.Stdout}.readLine()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ReadPassword prints label and reads a password without echoing it. When stdin
+// is not a terminal the password is read as a plain line so it can be piped in.
+func ReadPassword(label string) (string, error) {
+	fmt.Print(label)
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		line, err := plainBackend{in: stdin, out: os.Stdout}.readLine()
+		fmt.Println()
+		return line, err
+	}
+
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// WaitForEnter prints message and waits for Enter. It returns immediately when stdin
+// is not a terminal, since there is nobody to read the message before continuing.
+// Inside the app shell the message is shown as a screen below the output printed so far.
+func WaitForEnter(message string) {
+	if shell := tui.ActiveShell(); shell != nil {
+		_ = shell.Show(pauseModel{message: strings.TrimSpace(message)}, true)
+		return
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println()
+		return
+	}
+	fmt.Print(message)
+	_, _ = stdin.ReadString('\n')
+}
+
+// plainLabel is the label of item followed by its badge, in parentheses
+func plainLabel(item components.SelectItem) string {
+	if badge := components.ItemBadge(item); badge != "" {
+		return fmt.Sprintf("%s (%s)", item.Label(), badge)
+	}
+	return item.Label()
+}
@@ -0,0 +1,225 @@
+package prompts
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hassek/bc-cli/tui/components"
+)
+
+type testItem struct {
+	label   string
+	details string
+}
+
+func (i testItem) Label() string   { return i.label }
+func (i testItem) Details() string { return i.details }
+
+func newTestBackend(input string) (Backend, *bytes.Buffer) {
+	var out bytes.Buffer
+	return NewPlainBackend(bufio.NewReader(strings.NewReader(input)), &out), &out
+}
+
+func TestPlainSelect(t *testing.T) {
+	items := []components.SelectItem{
+		testItem{label: "Espresso", details: "Small and strong"},
+		testItem{label: "Pour Over"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "by number", input: "2\n", want: "Pour Over"},
+		{name: "by label", input: "espresso\n", want: "Espresso"},
+		{name: "retry after invalid", input: "7\n1\n", want: "Espresso"},
+		{name: "details then choice", input: "?1\n2\n", want: "Pour Over"},
+		{name: "last line without newline", input: "1", want: "Espresso"},
+		{name: "quit", input: "q\n", want: ""},
+		{name: "end of input", input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newTestBackend(tt.input)
+			selected, err := backend.Select("Pick one", items)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := ""
+			if selected != nil {
+				got = selected.Label()
+			}
+			if got != tt.want {
+				t.Errorf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainSelectShowsDetails(t *testing.T) {
+	backend, out := newTestBackend("?1\nq\n")
+	if _, err := backend.Select("Pick one", []components.SelectItem{testItem{label: "Espresso", details: "Small and strong"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Small and strong") {
+		t.Errorf("expected details in output, got %q", out.String())
+	}
+}
+
+func TestPlainMultiSelect(t *testing.T) {
+	items := []components.SelectItem{
+		testItem{label: "Espresso"},
+		testItem{label: "Pour Over"},
+		testItem{label: "Cold Brew"},
+		testItem{label: "Moka Pot"},
+	}
+	checked := []bool{false, true}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "keep marked", input: "\n", want: "Pour Over"},
+		{name: "numbers", input: "1, 3\n", want: "Espresso,Cold Brew"},
+		{name: "range", input: "2-4\n", want: "Pour Over,Cold Brew,Moka Pot"},
+		{name: "all", input: "a\n", want: "Espresso,Pour Over,Cold Brew,Moka Pot"},
+		{name: "none", input: "none\n", want: ""},
+		{name: "retry after invalid", input: "5\n3-1\n1\n", want: "Espresso"},
+		{name: "quit", input: "q\n", wantErr: ErrUserCancelled},
+		{name: "end of input", input: "", wantErr: ErrUserCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newTestBackend(tt.input)
+			selected, err := backend.MultiSelect("Pick some", items, checked)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MultiSelect() error = %v, want %v", err, tt.wantErr)
+			}
+
+			labels := make([]string, len(selected))
+			for i, item := range selected {
+				labels[i] = item.Label()
+			}
+			if got := strings.Join(labels, ","); got != tt.want {
+				t.Errorf("MultiSelect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "\n", want: true},
+		{input: "y\n", want: true},
+		{input: "YES\n", want: true},
+		{input: "n\n", want: false},
+		{input: "maybe\nno\n", want: false},
+		{input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		backend, _ := newTestBackend(tt.input)
+		got, err := backend.Confirm("Continue?")
+		if err != nil {
+			t.Fatalf("Confirm(%q) unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPlainConfirmScripted(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "\n", want: false},
+		{input: "y\n", want: true},
+		{input: "maybe\nyes\n", want: true},
+		{input: "", want: false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		backend := plainBackend{in: bufio.NewReader(strings.NewReader(tt.input)), out: &out, scripted: true}
+		got, err := backend.Confirm("Cancel?")
+		if err != nil {
+			t.Fatalf("Confirm(%q) unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if !strings.Contains(out.String(), "[y/N]") {
+			t.Errorf("Confirm(%q) printed %q, want no as the default", tt.input, out.String())
+		}
+	}
+}
+
+func TestPlainQuantity(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr error
+	}{
+		{name: "default", input: "\n", want: 3},
+		{name: "value", input: "5\n", want: 5},
+		{name: "retry out of range", input: "11\n0\n2\n", want: 2},
+		{name: "retry not a number", input: "two\n2\n", want: 2},
+		{name: "end of input", input: "", wantErr: ErrUserCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newTestBackend(tt.input)
+			got, err := backend.Quantity("Quantity", 1, 10, 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Quantity() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Quantity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		optional bool
+		want     string
+		wantErr  error
+	}{
+		{name: "value", input: "  extra fine  \n", want: "extra fine"},
+		{name: "required retries", input: "\nnotes\n", want: "notes"},
+		{name: "optional empty", input: "\n", optional: true, want: ""},
+		{name: "end of input", input: "", wantErr: ErrUserCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newTestBackend(tt.input)
+			got, err := backend.Text("Notes", "", "", tt.optional)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Text() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Text() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package prompts
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hassek/bc-cli/tui"
 	"github.com/hassek/bc-cli/tui/components"
+	"github.com/hassek/bc-cli/tui/keys"
+	"github.com/hassek/bc-cli/tui/styles"
 )
 
 // Model that composes duck with input/confirm components
@@ -91,81 +95,141 @@ func (m promptModel) View() string {
 	return view
 }
 
-// PromptQuantityInt prompts the user to enter a quantity as an integer
-// Matches the old API: func PromptQuantityInt(label string, min, max, defaultVal int) (int, error)
-func PromptQuantityInt(label string, min, max, defaultVal int) (int, error) {
-	duck := components.NewDuckComponent()
-	input := components.NewInputComponent(label, min, max, defaultVal)
+// teaBackend is the full-screen Bubble Tea implementation of Backend
+type teaBackend struct{}
 
+func (teaBackend) Quantity(label string, min, max, defaultVal int) (int, error) {
+	input := components.NewInputComponent(label, min, max, defaultVal)
 	m := promptModel{
-		duck:      duck,
+		duck:      components.NewDuckComponent(),
 		input:     input,
 		inputMode: true,
 	}
 
-	p := tea.NewProgram(m)
-	finalModel, err := p.Run()
-	if err != nil {
+	if err := tui.RunComponent(m, tui.Component(input, (*components.InputComponent).Update)); err != nil {
 		return 0, err
 	}
-
-	result := finalModel.(promptModel)
-	if result.input.Cancelled() {
+	if input.Cancelled() {
 		return 0, ErrUserCancelled
 	}
 
-	return result.input.Value(), nil
+	return input.Value(), nil
 }
 
-// PromptConfirm prompts the user for a yes/no confirmation
-// Matches the old API: func PromptConfirm(label string) (bool, error)
-func PromptConfirm(label string) (bool, error) {
-	duck := components.NewDuckComponent()
+func (teaBackend) Confirm(label string) (bool, error) {
 	confirm := components.NewConfirmComponent(label)
-
 	m := promptModel{
-		duck:        duck,
+		duck:        components.NewDuckComponent(),
 		confirm:     confirm,
 		confirmMode: true,
 	}
 
-	p := tea.NewProgram(m)
-	finalModel, err := p.Run()
-	if err != nil {
+	if err := tui.RunComponent(m, tui.Component(confirm, (*components.ConfirmComponent).Update)); err != nil {
 		return false, err
 	}
-
-	result := finalModel.(promptModel)
-	if result.confirm.Cancelled() {
+	if confirm.Cancelled() {
 		return false, nil
 	}
 
-	return result.confirm.Result(), nil
+	return confirm.Result(), nil
 }
 
-// PromptText prompts the user to enter text with an optional placeholder
-func PromptText(label, placeholder, helpText string, optional bool) (string, error) {
-	duck := components.NewDuckComponent()
+func (teaBackend) Text(label, placeholder, helpText string, optional bool) (string, error) {
 	textInput := components.NewTextInputComponent(label, placeholder, helpText, optional)
-
 	m := promptModel{
-		duck:      duck,
+		duck:      components.NewDuckComponent(),
 		textInput: textInput,
 		textMode:  true,
 	}
 
-	p := tea.NewProgram(m)
-	finalModel, err := p.Run()
-	if err != nil {
+	if err := tui.RunComponent(m, tui.Component(textInput, (*components.TextInputComponent).Update)); err != nil {
 		return "", err
 	}
-
-	result := finalModel.(promptModel)
-	if result.textInput.Cancelled() {
+	if textInput.Cancelled() {
 		return "", ErrUserCancelled
 	}
 
-	return result.textInput.Value(), nil
+	return textInput.Value(), nil
+}
+
+// Select is only used directly by callers without a picker model of their own
+func (teaBackend) Select(title string, items []components.SelectItem) (components.SelectItem, error) {
+	selector := components.NewSelectComponent(title, items)
+	m := selectModel{
+		duck:     components.NewDuckComponent(),
+		selector: selector,
+	}
+
+	if err := tui.RunComponent(m, tui.Component(selector, (*components.SelectComponent).Update)); err != nil {
+		return nil, err
+	}
+	if selector.Cancelled() {
+		return nil, nil
+	}
+	return selector.SelectedItem(), nil
+}
+
+func (teaBackend) MultiSelect(title string, items []components.SelectItem, checked []bool) ([]components.SelectItem, error) {
+	selector := components.NewMultiSelectComponent(title, items, checked)
+	m := selectModel{
+		duck:     components.NewDuckComponent(),
+		selector: selector,
+	}
+
+	if err := tui.RunComponent(m, tui.Component(selector, (*components.SelectComponent).Update)); err != nil {
+		return nil, err
+	}
+	if !selector.Selected() {
+		return nil, ErrUserCancelled
+	}
+	return selector.CheckedItems(), nil
+}
+
+// selectModel composes duck + select for generic selections
+type selectModel struct {
+	duck     *components.DuckComponent
+	selector *components.SelectComponent
+}
+
+func (m selectModel) Init() tea.Cmd {
+	return tea.Batch(m.duck.Init(), m.selector.Init())
+}
+
+func (m selectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var duckCmd, selectCmd tea.Cmd
+	m.duck, duckCmd = m.duck.Update(msg)
+	m.selector, selectCmd = m.selector.Update(msg)
+	if m.selector.Selected() {
+		m.duck.TriggerAction()
+	}
+	return m, tea.Batch(duckCmd, selectCmd)
+}
+
+func (m selectModel) View() string {
+	return m.duck.View() + m.selector.View()
+}
+
+// pauseModel shows a message until the user presses Enter
+type pauseModel struct {
+	message string
+}
+
+func (m pauseModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pauseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		km := keys.Active()
+		if key.Matches(msg, km.Select, km.Back, km.Close, km.Quit) {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m pauseModel) View() string {
+	return styles.FaintStyle.Render(m.message)
 }
 
 // ErrUserCancelled is returned when the user cancels the prompt
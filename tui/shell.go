@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrQuit is returned by screens shown after the user quit the app shell
+var ErrQuit = errors.New("user quit")
+
+// Shell hosts screens inside one long-lived program, see package tui/app
+type Shell interface {
+	// Show displays screen until it returns tea.Quit. With header set, the shared
+	// duck is drawn above the screen; otherwise the screen gets the whole window.
+	Show(screen tea.Model, header bool) error
+	// Write prints above the next screen shown. Flows print from their own goroutine,
+	// never from a screen's Update.
+	io.Writer
+}
+
+var (
+	// shellMu guards the shell state, read by the flows while the shell is set up and
+	// torn down
+	shellMu sync.RWMutex
+	shell   Shell
+	// quit is set once the user quit the app shell
+	quit bool
+)
+
+// SetShell installs the running app shell, or removes it when s is nil
+func SetShell(s Shell) {
+	shellMu.Lock()
+	defer shellMu.Unlock()
+	shell = s
+}
+
+// ActiveShell returns the running app shell, or nil when there is none
+func ActiveShell() Shell {
+	shellMu.RLock()
+	defer shellMu.RUnlock()
+	return shell
+}
+
+// SetQuit records that the user quit the app shell. A flow left behind, e.g. waiting on
+// the network, can't show screens or print anything from then on.
+func SetQuit() {
+	shellMu.Lock()
+	defer shellMu.Unlock()
+	quit = true
+}
+
+// HasQuit reports whether the user quit the app shell
+func HasQuit() bool {
+	shellMu.RLock()
+	defer shellMu.RUnlock()
+	return quit
+}
+
+// Output returns where flows print: the app shell while one runs, stdout otherwise
+func Output() io.Writer {
+	shellMu.RLock()
+	defer shellMu.RUnlock()
+	switch {
+	case quit:
+		return io.Discard
+	case shell != nil:
+		return shell
+	}
+	return os.Stdout
+}
+
+// Run runs a full-window screen until it quits, inside the app shell if one is running
+func Run(screen tea.Model) error {
+	if shell := ActiveShell(); shell != nil {
+		return shell.Show(withHelp(screen, screen), false)
+	}
+	if HasQuit() {
+		return ErrQuit
+	}
+	_, err := NewProgram(withHelp(screen, screen)).Run()
+	return err
+}
+
+// RunComponent runs component below the duck. Inside the app shell the shell's duck is
+// used; otherwise standalone, the component composed with a duck of its own, runs as a
+// program. Components keep their state, so callers read the result from them afterwards.
+func RunComponent(standalone, component tea.Model) error {
+	if shell := ActiveShell(); shell != nil {
+		return shell.Show(withHelp(component, component), true)
+	}
+	if HasQuit() {
+		return ErrQuit
+	}
+	// Without the mouse, whatever the setting: standalone components are drawn inline
+	// below whatever the command printed, at a row of the terminal the program doesn't
+	// know, so the rows of mouse events can't be mapped onto them. Running them in the
+	// alternate screen would hide that output, which prompts often ask about.
+	_, err := tea.NewProgram(withHelp(standalone, component)).Run()
+	return err
+}
+
+// Component adapts a component whose Update returns its own type, such as
+// components.SelectComponent, to tea.Model so it can be shown by the shell
+func Component[C interface {
+	Init() tea.Cmd
+	View() string
+}](c C, update func(C, tea.Msg) (C, tea.Cmd)) tea.Model {
+	return component[C]{c: c, update: update}
+}
+
+type component[C interface {
+	Init() tea.Cmd
+	View() string
+}] struct {
+	c      C
+	update func(C, tea.Msg) (C, tea.Cmd)
+}
+
+func (m component[C]) Init() tea.Cmd {
+	return m.c.Init()
+}
+
+func (m component[C]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.c, cmd = m.update(m.c, msg)
+	return m, cmd
+}
+
+func (m component[C]) View() string {
+	return m.c.View()
+}
+
+// inner returns the adapted component, e.g. for its help bindings
+func (m component[C]) inner() any {
+	return m.c
+}
@@ -2,30 +2,91 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
-// Color palette matching promptui design
+// Colors of the active theme, see Apply
 var (
-	// Primary colors
-	Cyan   = lipgloss.Color("#00ffff")
-	Green  = lipgloss.Color("#00ff00")
-	Yellow = lipgloss.Color("#ffff00")
-	Red    = lipgloss.Color("#ff0000")
-	Faint  = lipgloss.Color("#808080")
+	Primary  lipgloss.TerminalColor
+	Accent   lipgloss.TerminalColor
+	Success  lipgloss.TerminalColor
+	Warning  lipgloss.TerminalColor
+	Error    lipgloss.TerminalColor
+	Muted    lipgloss.TerminalColor
+	Border   lipgloss.TerminalColor
+	Heading  lipgloss.TerminalColor
+	Section  lipgloss.TerminalColor
+	Emphasis lipgloss.TerminalColor
+	Text     lipgloss.TerminalColor
+)
 
+// Styles built from the active theme, see Apply
+var (
 	// Text styles
-	ActiveStyle   = lipgloss.NewStyle().Foreground(Cyan).Bold(true)
-	SelectedStyle = lipgloss.NewStyle().Foreground(Green).Bold(true)
-	InactiveStyle = lipgloss.NewStyle()
-	FaintStyle    = lipgloss.NewStyle().Foreground(Faint)
-	ErrorStyle    = lipgloss.NewStyle().Foreground(Red).Bold(true)
+	ActiveStyle   lipgloss.Style
+	SelectedStyle lipgloss.Style
+	InactiveStyle lipgloss.Style
+	FaintStyle    lipgloss.Style
+	ErrorStyle    lipgloss.Style
+	WarningStyle  lipgloss.Style
+	TextStyle     lipgloss.Style
+
+	// Headings and inline formatting of rendered content
+	HeadingStyle  lipgloss.Style
+	SectionStyle  lipgloss.Style
+	EmphasisStyle lipgloss.Style
 
 	// Duck styles
-	DuckStyle       = lipgloss.NewStyle().Foreground(Cyan)
-	DuckAccentStyle = lipgloss.NewStyle().Foreground(Yellow)
+	DuckStyle       lipgloss.Style
+	DuckAccentStyle lipgloss.Style
+
+	// Filter matches in item labels
+	MatchStyle lipgloss.Style
 
 	// Cursor
-	CursorStyle = lipgloss.NewStyle().Foreground(Cyan)
-	Cursor      = "▸"
+	CursorStyle lipgloss.Style
+)
+
+const (
+	Cursor = "▸"
 
 	// Spacing
 	Indent = "  "
 )
+
+func init() {
+	Apply(Dark)
+}
+
+// Apply makes t the active theme, rebuilding every style from its colors
+func Apply(t Theme) {
+	active = t
+
+	Primary = t.Primary
+	Accent = t.Accent
+	Success = t.Success
+	Warning = t.Warning
+	Error = t.Error
+	Muted = t.Muted
+	Border = t.Border
+	Heading = t.Heading
+	Section = t.Section
+	Emphasis = t.Emphasis
+	Text = t.Text
+
+	ActiveStyle = lipgloss.NewStyle().Foreground(Primary).Bold(true)
+	SelectedStyle = lipgloss.NewStyle().Foreground(Success).Bold(true)
+	InactiveStyle = lipgloss.NewStyle()
+	FaintStyle = lipgloss.NewStyle().Foreground(Muted)
+	ErrorStyle = lipgloss.NewStyle().Foreground(Error).Bold(true)
+	WarningStyle = lipgloss.NewStyle().Foreground(Warning)
+	TextStyle = lipgloss.NewStyle().Foreground(Text)
+
+	HeadingStyle = lipgloss.NewStyle().Foreground(Heading).Bold(true)
+	SectionStyle = lipgloss.NewStyle().Foreground(Section).Bold(true)
+	EmphasisStyle = lipgloss.NewStyle().Foreground(Emphasis).Italic(true)
+
+	DuckStyle = lipgloss.NewStyle().Foreground(Primary)
+	DuckAccentStyle = lipgloss.NewStyle().Foreground(Accent)
+
+	MatchStyle = lipgloss.NewStyle().Foreground(Accent).Underline(true)
+
+	CursorStyle = lipgloss.NewStyle().Foreground(Primary)
+}
@@ -0,0 +1,238 @@
+package styles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme maps the semantic color roles of the interface to colors
+type Theme struct {
+	Name     string
+	Primary  lipgloss.TerminalColor // titles, the cursor, active items and the duck
+	Accent   lipgloss.TerminalColor // the duck's accents, shortcuts and filter matches
+	Success  lipgloss.TerminalColor // checked items, additions and decreases
+	Warning  lipgloss.TerminalColor // warnings, modifications and increases
+	Error    lipgloss.TerminalColor // errors and removals
+	Muted    lipgloss.TerminalColor // hints, details and anything secondary
+	Border   lipgloss.TerminalColor // boxes around summaries and panes
+	Heading  lipgloss.TerminalColor // headings of boxes
+	Section  lipgloss.TerminalColor // section headings of rendered content
+	Emphasis lipgloss.TerminalColor // emphasized text of rendered content
+	Text     lipgloss.TerminalColor // body text of boxes
+}
+
+// Built-in theme names accepted by the theme config setting
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+)
+
+// ThemeNames lists the built-in theme names
+var ThemeNames = []string{ThemeAuto, ThemeDark, ThemeLight, ThemeHighContrast}
+
+// Dark suits terminals with a dark background. It is the original palette.
+var Dark = Theme{
+	Name:     ThemeDark,
+	Primary:  lipgloss.Color("#00ffff"),
+	Accent:   lipgloss.Color("#ffff00"),
+	Success:  lipgloss.Color("#00ff00"),
+	Warning:  lipgloss.Color("#ffff00"),
+	Error:    lipgloss.Color("#ff0000"),
+	Muted:    lipgloss.Color("#808080"),
+	Border:   lipgloss.Color("240"),
+	Heading:  lipgloss.Color("14"),
+	Section:  lipgloss.Color("214"),
+	Emphasis: lipgloss.Color("247"),
+	Text:     lipgloss.Color("7"),
+}
+
+// Light suits terminals with a light background
+var Light = Theme{
+	Name:     ThemeLight,
+	Primary:  lipgloss.Color("#005f87"),
+	Accent:   lipgloss.Color("#af5f00"),
+	Success:  lipgloss.Color("#008700"),
+	Warning:  lipgloss.Color("#af5f00"),
+	Error:    lipgloss.Color("#d70000"),
+	Muted:    lipgloss.Color("#6c6c6c"),
+	Border:   lipgloss.Color("250"),
+	Heading:  lipgloss.Color("#005f87"),
+	Section:  lipgloss.Color("#875f00"),
+	Emphasis: lipgloss.Color("#585858"),
+	Text:     lipgloss.NoColor{},
+}
+
+// HighContrast uses the terminal's own bright colors and its default foreground for
+// text, so it stays readable on any background
+var HighContrast = Theme{
+	Name:     ThemeHighContrast,
+	Primary:  lipgloss.Color("14"),
+	Accent:   lipgloss.Color("11"),
+	Success:  lipgloss.Color("10"),
+	Warning:  lipgloss.Color("11"),
+	Error:    lipgloss.Color("9"),
+	Muted:    lipgloss.NoColor{},
+	Border:   lipgloss.NoColor{},
+	Heading:  lipgloss.Color("14"),
+	Section:  lipgloss.Color("11"),
+	Emphasis: lipgloss.NoColor{},
+	Text:     lipgloss.NoColor{},
+}
+
+// NoColor draws no colors at all, for NO_COLOR and --no-color. Bold, italics and
+// underlines still apply.
+var NoColor = Theme{
+	Name:     "no-color",
+	Primary:  lipgloss.NoColor{},
+	Accent:   lipgloss.NoColor{},
+	Success:  lipgloss.NoColor{},
+	Warning:  lipgloss.NoColor{},
+	Error:    lipgloss.NoColor{},
+	Muted:    lipgloss.NoColor{},
+	Border:   lipgloss.NoColor{},
+	Heading:  lipgloss.NoColor{},
+	Section:  lipgloss.NoColor{},
+	Emphasis: lipgloss.NoColor{},
+	Text:     lipgloss.NoColor{},
+}
+
+var active Theme
+
+// Active returns the theme in use
+func Active() Theme {
+	return active
+}
+
+// LoadTheme returns the named theme: a built-in one, or a user theme read from
+// themesDir/<name>.json. The auto theme, also used for an empty name, picks dark or
+// light to match the terminal's background.
+func LoadTheme(name, themesDir string) (Theme, error) {
+	switch name {
+	case "", ThemeAuto:
+		if lipgloss.HasDarkBackground() {
+			return Dark, nil
+		}
+		return Light, nil
+	case ThemeDark:
+		return Dark, nil
+	case ThemeLight:
+		return Light, nil
+	case ThemeHighContrast:
+		return HighContrast, nil
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return Theme{}, fmt.Errorf("invalid theme name %q", name)
+	}
+	path := filepath.Join(themesDir, name+".json")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Theme{}, fmt.Errorf("unknown theme %q, expected one of: %s, or a theme file at %s",
+			name, strings.Join(ThemeNames, ", "), path)
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to read theme: %w", err)
+	}
+	t, err := ParseTheme(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to load theme %s: %w", path, err)
+	}
+	t.Name = name
+	return t, nil
+}
+
+// themeFile is the JSON form of a user theme: a built-in base theme and the roles it
+// overrides, e.g. {"base": "light", "primary": "#0087af", "muted": "245"}
+type themeFile struct {
+	Base     string `json:"base"`
+	Primary  string `json:"primary"`
+	Accent   string `json:"accent"`
+	Success  string `json:"success"`
+	Warning  string `json:"warning"`
+	Error    string `json:"error"`
+	Muted    string `json:"muted"`
+	Border   string `json:"border"`
+	Heading  string `json:"heading"`
+	Section  string `json:"section"`
+	Emphasis string `json:"emphasis"`
+	Text     string `json:"text"`
+}
+
+// ParseTheme parses a user theme. Colors are "#rrggbb" or "#rgb" hex colors, ANSI
+// color numbers from 0 to 255, or "none" for the terminal's default color. Roles left
+// out keep the color of the base theme, dark unless given.
+func ParseTheme(data []byte) (Theme, error) {
+	var f themeFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&f); err != nil {
+		return Theme{}, err
+	}
+
+	var t Theme
+	switch f.Base {
+	case "", ThemeDark:
+		t = Dark
+	case ThemeLight:
+		t = Light
+	case ThemeHighContrast:
+		t = HighContrast
+	default:
+		return Theme{}, fmt.Errorf("unknown base theme %q, expected one of: %s, %s, %s",
+			f.Base, ThemeDark, ThemeLight, ThemeHighContrast)
+	}
+
+	roles := []struct {
+		name  string
+		value string
+		color *lipgloss.TerminalColor
+	}{
+		{"primary", f.Primary, &t.Primary},
+		{"accent", f.Accent, &t.Accent},
+		{"success", f.Success, &t.Success},
+		{"warning", f.Warning, &t.Warning},
+		{"error", f.Error, &t.Error},
+		{"muted", f.Muted, &t.Muted},
+		{"border", f.Border, &t.Border},
+		{"heading", f.Heading, &t.Heading},
+		{"section", f.Section, &t.Section},
+		{"emphasis", f.Emphasis, &t.Emphasis},
+		{"text", f.Text, &t.Text},
+	}
+	for _, role := range roles {
+		if role.value == "" {
+			continue
+		}
+		color, err := parseColor(role.value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("invalid %s color: %w", role.name, err)
+		}
+		*role.color = color
+	}
+	return t, nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func parseColor(value string) (lipgloss.TerminalColor, error) {
+	if value == "none" {
+		return lipgloss.NoColor{}, nil
+	}
+	if hexColor.MatchString(value) {
+		return lipgloss.Color(value), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+	return nil, fmt.Errorf("%q is not a hex color, an ANSI color number or none", value)
+}
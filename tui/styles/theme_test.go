@@ -0,0 +1,125 @@
+package styles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantErr     string
+		wantPrimary lipgloss.TerminalColor
+		wantMuted   lipgloss.TerminalColor
+	}{
+		{
+			name:        "Empty theme is dark",
+			data:        `{}`,
+			wantPrimary: Dark.Primary,
+			wantMuted:   Dark.Muted,
+		},
+		{
+			name:        "Overrides the base theme",
+			data:        `{"base": "light", "primary": "#0087af"}`,
+			wantPrimary: lipgloss.Color("#0087af"),
+			wantMuted:   Light.Muted,
+		},
+		{
+			name:        "ANSI numbers and none",
+			data:        `{"primary": "33", "muted": "none"}`,
+			wantPrimary: lipgloss.Color("33"),
+			wantMuted:   lipgloss.NoColor{},
+		},
+		{
+			name:    "Unknown base",
+			data:    `{"base": "solarized"}`,
+			wantErr: "unknown base theme",
+		},
+		{
+			name:    "Invalid color",
+			data:    `{"accent": "orange"}`,
+			wantErr: "invalid accent color",
+		},
+		{
+			name:    "ANSI number out of range",
+			data:    `{"accent": "256"}`,
+			wantErr: "invalid accent color",
+		},
+		{
+			name:    "Unknown role",
+			data:    `{"primray": "#ffffff"}`,
+			wantErr: "unknown field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, err := ParseTheme([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseTheme() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTheme() error = %v", err)
+			}
+			if theme.Primary != tt.wantPrimary {
+				t.Errorf("Primary = %v, want %v", theme.Primary, tt.wantPrimary)
+			}
+			if theme.Muted != tt.wantMuted {
+				t.Errorf("Muted = %v, want %v", theme.Muted, tt.wantMuted)
+			}
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "mine.json"), []byte(`{"base": "light", "accent": "#ff8700"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"accent": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		theme      string
+		wantErr    string
+		wantName   string
+		wantAccent lipgloss.TerminalColor
+	}{
+		{name: "Built-in theme", theme: "high-contrast", wantName: ThemeHighContrast, wantAccent: HighContrast.Accent},
+		{name: "User theme", theme: "mine", wantName: "mine", wantAccent: lipgloss.Color("#ff8700")},
+		{name: "Missing theme", theme: "nope", wantErr: "unknown theme"},
+		{name: "Broken theme file", theme: "broken", wantErr: "failed to load theme"},
+		{name: "Path as name", theme: "../mine", wantErr: "invalid theme name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, err := LoadTheme(tt.theme, dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadTheme(%q) error = %v, want it to contain %q", tt.theme, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTheme(%q) error = %v", tt.theme, err)
+			}
+			if theme.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", theme.Name, tt.wantName)
+			}
+			if theme.Accent != tt.wantAccent {
+				t.Errorf("Accent = %v, want %v", theme.Accent, tt.wantAccent)
+			}
+		})
+	}
+}
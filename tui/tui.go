@@ -0,0 +1,57 @@
+// Package tui holds terminal UI settings shared by the components, models and prompts.
+package tui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+var (
+	forcePlain bool
+	mouse      bool
+)
+
+// SetPlain forces the plain line-based interface, e.g. for --plain
+func SetPlain(plain bool) {
+	forcePlain = plain
+}
+
+// IsPlain reports whether prompts and views should use plain line-based I/O instead of
+// full-screen Bubble Tea programs. This is the case when --plain was passed or when
+// stdin or stdout is not a terminal (pipes, CI, screen readers). It is never the case
+// inside the app shell.
+func IsPlain() bool {
+	if ActiveShell() != nil {
+		return false
+	}
+	return forcePlain || !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// ShowsProgress reports whether progress counters redrawn with carriage returns go to
+// stderr. They don't with --plain or when stderr isn't a terminal, so scripts capturing
+// it don't get them.
+func ShowsProgress() bool {
+	return !forcePlain && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// SetMouse turns mouse support on or off for the programs created afterwards. It is off
+// by default since it takes over the terminal's own text selection. Components run
+// standalone by RunComponent never get the mouse.
+func SetMouse(enabled bool) {
+	mouse = enabled
+}
+
+// MouseEnabled reports whether programs report mouse events
+func MouseEnabled() bool {
+	return mouse
+}
+
+// NewProgram creates a Bubble Tea program with the CLI's standard options
+func NewProgram(model tea.Model, opts ...tea.ProgramOption) *tea.Program {
+	if mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	return tea.NewProgram(model, opts...)
+}
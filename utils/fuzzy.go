@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"unicode"
+)
+
+// Fuzzy match scoring. Matches at word starts and runs of consecutive matches rank
+// higher, gaps between matched characters rank lower.
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyBonusBoundary    = 10
+	fuzzyBonusConsecutive = 8
+	fuzzyPenaltyGapStart  = 3
+	fuzzyPenaltyGapExtend = 1
+)
+
+// FuzzyMatch reports whether all runes of pattern appear in text in order, ignoring
+// case. It returns a score, higher for better matches, and the rune indexes of text
+// that matched. An empty pattern matches everything with a score of zero.
+func FuzzyMatch(pattern, text string) (int, []int, bool) {
+	needle := []rune(pattern)
+	if len(needle) == 0 {
+		return 0, nil, true
+	}
+	haystack := []rune(text)
+
+	// Find the first place the whole pattern fits, scanning forward...
+	end := -1
+	p := 0
+	for i, r := range haystack {
+		if foldEqual(r, needle[p]) {
+			p++
+			if p == len(needle) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// ...then scan back from its end, which finds the tightest match ending there
+	positions := make([]int, len(needle))
+	p = len(needle) - 1
+	for i := end; i >= 0 && p >= 0; i-- {
+		if foldEqual(haystack[i], needle[p]) {
+			positions[p] = i
+			p--
+		}
+	}
+
+	score := 0
+	for i, pos := range positions {
+		score += fuzzyScoreMatch
+		if isWordStart(haystack, pos) {
+			score += fuzzyBonusBoundary
+		}
+		if i > 0 {
+			if gap := pos - positions[i-1] - 1; gap == 0 {
+				score += fuzzyBonusConsecutive
+			} else {
+				score -= fuzzyPenaltyGapStart + fuzzyPenaltyGapExtend*(gap-1)
+			}
+		}
+	}
+	return score, positions, true
+}
+
+func foldEqual(a, b rune) bool {
+	return a == b || unicode.ToLower(a) == unicode.ToLower(b)
+}
+
+// isWordStart reports whether the rune at i starts a word, after a separator or
+// at a lower to upper case change
+func isWordStart(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := runes[i-1], runes[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		matches   bool
+		positions []int
+	}{
+		{
+			name:    "Empty pattern matches anything",
+			pattern: "",
+			text:    "Espresso",
+			matches: true,
+		},
+		{
+			name:      "Exact prefix",
+			pattern:   "esp",
+			text:      "Espresso",
+			matches:   true,
+			positions: []int{0, 1, 2},
+		},
+		{
+			name:      "Characters spread over the text",
+			pattern:   "pov",
+			text:      "Pour Over",
+			matches:   true,
+			positions: []int{0, 5, 6},
+		},
+		{
+			name:      "Tightest match is preferred",
+			pattern:   "ab",
+			text:      "a-xab",
+			matches:   true,
+			positions: []int{3, 4},
+		},
+		{
+			name:      "Accents are not folded",
+			pattern:   "cafe",
+			text:      "Café",
+			matches:   false,
+			positions: nil,
+		},
+		{
+			name:    "Out of order characters don't match",
+			pattern: "ba",
+			text:    "ab",
+			matches: false,
+		},
+		{
+			name:    "Pattern longer than text",
+			pattern: "espresso",
+			text:    "esp",
+			matches: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := FuzzyMatch(tt.pattern, tt.text)
+			if ok != tt.matches {
+				t.Fatalf("FuzzyMatch(%q, %q) matched = %v, want %v", tt.pattern, tt.text, ok, tt.matches)
+			}
+			if ok && !reflect.DeepEqual(positions, tt.positions) {
+				t.Errorf("FuzzyMatch(%q, %q) positions = %v, want %v", tt.pattern, tt.text, positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchRanking(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		better  string
+		worse   string
+	}{
+		{
+			name:    "Consecutive beats scattered",
+			pattern: "brew",
+			better:  "Brewing Basics",
+			worse:   "Bean Roasting Explained Well",
+		},
+		{
+			name:    "Word starts beat the middle of words",
+			pattern: "cb",
+			better:  "Cold Brew",
+			worse:   "Acrobat",
+		},
+		{
+			name:    "Shorter gaps beat longer gaps",
+			pattern: "ae",
+			better:  "Aeropress",
+			worse:   "Arabica Bean",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, _, ok := FuzzyMatch(tt.pattern, tt.better)
+			if !ok {
+				t.Fatalf("FuzzyMatch(%q, %q) didn't match", tt.pattern, tt.better)
+			}
+			worse, _, ok := FuzzyMatch(tt.pattern, tt.worse)
+			if !ok {
+				t.Fatalf("FuzzyMatch(%q, %q) didn't match", tt.pattern, tt.worse)
+			}
+			if better <= worse {
+				t.Errorf("score for %q = %d, want more than %d for %q", tt.better, better, worse, tt.worse)
+			}
+		})
+	}
+}
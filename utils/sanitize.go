@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeText removes terminal control sequences and control characters from text,
+// keeping newlines and tabs. Text from the API goes through it, so a server can't
+// style, hide or overwrite output, change the window title or write to the clipboard;
+// only styling the CLI adds itself reaches the terminal.
+//
+// Escape sequences are removed whole: CSI sequences like "\x1b[31m", OSC, DCS, SOS, PM
+// and APC strings up to their terminator, and two-character escapes. Their 8-bit C1
+// forms are removed the same way, and invalid UTF-8 becomes U+FFFD so a terminal can't
+// read it as C1 controls either.
+func SanitizeText(text string) string {
+	if isClean(text) {
+		return text
+	}
+
+	text = strings.ToValidUTF8(text, "�")
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\x1b':
+			i += escapeLength(text[i:])
+		case r == '\u009b':
+			i += size + csiLength(text[i+size:])
+		case r == '\u0090' || r == '\u0098' || r == '\u009d' || r == '\u009e' || r == '\u009f':
+			i += size + stringLength(text[i+size:])
+		case isControl(r):
+			i += size
+		default:
+			b.WriteString(text[i : i+size])
+			i += size
+		}
+	}
+	return b.String()
+}
+
+// isClean reports whether text is valid UTF-8 without anything SanitizeText removes,
+// which is nearly all text, so it is returned as is
+func isClean(text string) bool {
+	return utf8.ValidString(text) && !strings.ContainsFunc(text, isControl)
+}
+
+// isControl reports whether r is a C0 or C1 control character other than a newline or
+// tab, or DEL
+func isControl(r rune) bool {
+	return r < 0x20 && r != '\n' && r != '\t' || r >= 0x7f && r <= 0x9f
+}
+
+// escapeLength returns the length of the escape sequence at the start of text, which
+// starts with ESC
+func escapeLength(text string) int {
+	if len(text) < 2 {
+		return len(text)
+	}
+	switch text[1] {
+	case '[':
+		return 2 + csiLength(text[2:])
+	case ']', 'P', 'X', '^', '_':
+		return 2 + stringLength(text[2:])
+	}
+	// Intermediate bytes, then a final byte
+	n := 1
+	for n < len(text) && text[n] >= 0x20 && text[n] <= 0x2f {
+		n++
+	}
+	if n < len(text) && text[n] >= 0x30 && text[n] <= 0x7e {
+		n++
+	}
+	return n
+}
+
+// csiLength returns the length of the parameters, intermediates and final byte of a
+// CSI sequence. A malformed sequence ends at the first byte that doesn't belong to it.
+func csiLength(text string) int {
+	n := 0
+	for n < len(text) && text[n] >= 0x20 && text[n] <= 0x3f {
+		n++
+	}
+	if n < len(text) && text[n] >= 0x40 && text[n] <= 0x7e {
+		n++
+	}
+	return n
+}
+
+// stringLength returns the length of the body and terminator of an OSC, DCS, SOS, PM
+// or APC string: up to BEL, ESC \ or the 8-bit ST, or all of text when unterminated
+func stringLength(text string) int {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\a':
+			return i + 1
+		case text[i] == '\x1b':
+			if i+1 < len(text) && text[i+1] == '\\' {
+				return i + 2
+			}
+			return i
+		case strings.HasPrefix(text[i:], "\u009c"):
+			return i + len("\u009c")
+		}
+	}
+	return len(text)
+}
+
+// SanitizeStrings applies SanitizeText to every string v points to: struct fields,
+// elements of slices, arrays and maps, and the values of interfaces, at any depth. It
+// is meant for decoded API responses, which have no cycles.
+func SanitizeStrings(v any) {
+	sanitizeValue(reflect.ValueOf(v))
+}
+
+func sanitizeValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(SanitizeText(v.String()))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			sanitizeValue(v.Elem())
+		}
+	case reflect.Interface:
+		// The value in an interface can't be changed in place, so it is replaced
+		if !v.IsNil() && v.CanSet() {
+			v.Set(sanitizedCopy(v.Elem()))
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			sanitizeValue(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			sanitizeValue(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		for _, key := range v.MapKeys() {
+			value := sanitizedCopy(v.MapIndex(key))
+			if key.Kind() == reflect.String {
+				if clean := SanitizeText(key.String()); clean != key.String() {
+					v.SetMapIndex(key, reflect.Value{})
+					key = reflect.ValueOf(clean).Convert(key.Type())
+				}
+			}
+			v.SetMapIndex(key, value)
+		}
+	}
+}
+
+// sanitizedCopy returns a sanitized copy of a value that can't be set, like a map value
+func sanitizedCopy(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	sanitizeValue(c)
+	return c
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "Plain text", text: "Fresh coffee\n\tdelivered", want: "Fresh coffee\n\tdelivered"},
+		{name: "Unicode", text: "Café ☕ — ñ", want: "Café ☕ — ñ"},
+		{name: "SGR styling", text: "\x1b[31;1mred\x1b[0m text", want: "red text"},
+		{name: "Concealed text", text: "shown\x1b[8mhidden", want: "shownhidden"},
+		{name: "Window title with BEL", text: "\x1b]0;pwned\aafter", want: "after"},
+		{name: "Clipboard write with ST", text: "\x1b]52;c;ZWNobyBoaQ==\x1b\\after", want: "after"},
+		{name: "Unterminated OSC", text: "before\x1b]0;title", want: "before"},
+		{name: "DCS string", text: "\x1bPq#0;2;0;0;0\x1b\\after", want: "after"},
+		{name: "Two-character escape", text: "a\x1bcb\x1b(Bc", want: "abc"},
+		{name: "Lone escape", text: "end\x1b", want: "end"},
+		{name: "8-bit CSI", text: "\u009b31mred", want: "red"},
+		{name: "8-bit OSC", text: "\u009d0;title\u009cafter", want: "after"},
+		{name: "Carriage return overwrite", text: "safe\rrm -rf", want: "saferm -rf"},
+		{name: "Windows line endings", text: "one\r\ntwo", want: "one\ntwo"},
+		{name: "Other control characters", text: "a\bb\x00c\x7fd\u0085e", want: "abcde"},
+		{name: "Invalid UTF-8", text: "a\x9b31mb", want: "a�31mb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeText(tt.text); got != tt.want {
+				t.Errorf("SanitizeText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeStrings(t *testing.T) {
+	type item struct {
+		Title    string
+		Tags     []string
+		Extra    map[string]any
+		Next     *item
+		internal string
+	}
+	v := &item{
+		Title: "\x1b[2Jtitle",
+		Tags:  []string{"\x1b]0;x\atag"},
+		Extra: map[string]any{
+			"\x1b[1mkey": "\x1b[1mvalue",
+			"list":       []any{"\x1b[1mnested"},
+		},
+		Next:     &item{Title: "next\x07"},
+		internal: "\x1b[1m",
+	}
+
+	SanitizeStrings(v)
+
+	if v.Title != "title" || v.Tags[0] != "tag" || v.Next.Title != "next" {
+		t.Errorf("fields not sanitized: %q %q %q", v.Title, v.Tags[0], v.Next.Title)
+	}
+	if v.Extra["key"] != "value" {
+		t.Errorf("map not sanitized: %q", v.Extra)
+	}
+	if list := v.Extra["list"].([]any); list[0] != "nested" {
+		t.Errorf("nested value not sanitized: %q", list[0])
+	}
+	if v.internal != "\x1b[1m" {
+		t.Errorf("unexported field changed: %q", v.internal)
+	}
+}
+
+func FuzzSanitizeText(f *testing.F) {
+	for _, seed := range []string{"plain", "\x1b[31mred", "\x1b]0;t\a", "\u009b1m", "\x1bP\x1b\\", "a\r\nb"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		got := SanitizeText(text)
+		if strings.ContainsFunc(got, isControl) {
+			t.Fatalf("SanitizeText(%q) = %q, which has control characters", text, got)
+		}
+		if again := SanitizeText(got); again != got {
+			t.Fatalf("SanitizeText(%q) = %q, not idempotent: %q", text, got, again)
+		}
+	})
+}